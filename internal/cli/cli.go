@@ -0,0 +1,270 @@
+// Package cli implements the non-interactive subcommands used for scripting
+// (e.g., "ledger-a add 2026-10-03 'nasi goreng' 45000 IDR").
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"ledger-a/internal/config"
+	"ledger-a/internal/currency"
+	"ledger-a/internal/ledger"
+)
+
+// Exit codes returned by Run
+const (
+	ExitOK    = 0 // The command succeeded
+	ExitError = 1 // The command failed (e.g., unreadable data or no exchange rate)
+	ExitUsage = 2 // The command line was invalid
+)
+
+// Usage describes the subcommands
+const Usage = `Usage: ledger-a [flags] [command [command flags] [args]]
+
+Without a command, ledger-a opens the interactive ledger.
+
+Commands:
+  add [--category "food #tag"] [--account NAME] DATE DESCRIPTION AMOUNT [CURRENCY]
+        add an entry; AMOUNT is spending, or income if it starts with +
+        (e.g., +2500); CURRENCY is the home or local currency (default: local)
+        and the other amount is converted at the day's rate; the account
+        defaults to the first one in the config
+  list [--from DATE] [--to DATE] [--query TEXT] [--format tsv|json]
+        print entries; tsv columns are date, id, description, category, tags,
+        home amount, local amount, and json prints one object per line
+  totals [--from DATE] [--to DATE] [--query TEXT] [--by-category] [--format tsv|json]
+        print the home total, local total and entry count, leaving out
+        transfers, settlements and adjustments
+  export [--from DATE] [--to DATE] [--format csv|json|hledger|beancount] [--output FILE]
+        write entries (csv), whole days with journals (json), or transactions
+        priced at the stored rates with journals as comments (hledger, beancount)
+  rate [--date DATE] [--format tsv|json]
+        print the exchange rate, refreshing it unless offline
+
+DATE is YYYY-MM-DD, MM/DD/YYYY, "today" or "yesterday". Ranges default to all data.
+Amounts are plain decimals; a leading - is accepted and means spending too.
+
+Exit status is 0 on success, 1 if the command failed and 2 for invalid usage.
+`
+
+// usageHint follows usage errors
+const usageHint = "Run 'ledger-a help' for usage.\n"
+
+// usageError reports an invalid command line
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// usagef returns a usage error with a formatted message
+func usagef(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// runner holds what the commands share: the ledger, the converter and the output streams
+type runner struct {
+	cfg       *config.Config
+	service   *ledger.Service
+	converter *currency.Converter
+	stdout    io.Writer
+	stderr    io.Writer
+}
+
+// command runs a subcommand with its arguments
+type command func(r *runner, args []string) error
+
+var commands = map[string]command{
+	"add":    runAdd,
+	"list":   runList,
+	"totals": runTotals,
+	"export": runExport,
+	"rate":   runRate,
+}
+
+// readOnly lists the commands that don't write to the data directory, so a new
+// ledger's currencies aren't pinned by just looking at it
+var readOnly = map[string]bool{
+	"list":   true,
+	"totals": true,
+	"export": true,
+	"rate":   true,
+}
+
+// Run executes a subcommand against the configured ledger and returns the exit code
+func Run(cfg *config.Config, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" {
+		fmt.Fprint(stdout, Usage)
+		return ExitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "Error: unknown command %q\n%s", args[0], usageHint)
+		return ExitUsage
+	}
+
+	if notice := cfg.LegacyDataNotice(); notice != "" {
+		fmt.Fprintf(stderr, "Warning: %s\n", notice)
+	}
+
+	resolve := cfg.Currencies
+	if readOnly[args[0]] {
+		resolve = cfg.ReadCurrencies
+	}
+	currencies, err := resolve()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitError
+	}
+
+	accounts, err := cfg.LedgerAccounts(currencies)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitError
+	}
+
+	// Same ledger and rate cache as the interactive app
+	converter := currency.NewConverter(cfg.CacheDir, currencies.Home.Code, currencies.Local.Code)
+	converter.SetOfflineMode(cfg.Offline)
+	converter.SetFixedRate(cfg.Rate)
+
+	r := &runner{
+		cfg:       cfg,
+		service:   ledger.NewServiceWithCurrencies(cfg.DataDir, currencies),
+		converter: converter,
+		stdout:    stdout,
+		stderr:    stderr,
+	}
+	r.service.SetAccounts(accounts)
+	// Recurring entries due on a day are added when a command opens it
+	r.service.SetRateSource(func(date time.Time) float64 {
+		rate, err := converter.RateOn(date)
+		if err != nil {
+			// The entries wait for the rate and are converted when the day is opened in the editor
+			r.warn("no %s/%s rate for recurring entries on %s: %v", converter.From(), converter.To(), date.Format(ledger.DateFormat), err)
+		}
+		return rate
+	})
+
+	err = cmd(r, args[1:])
+	var usageErr *usageError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, flag.ErrHelp):
+		return ExitOK
+	case errors.As(err, &usageErr):
+		fmt.Fprintf(stderr, "Error: %v\n%s", err, usageHint)
+		return ExitUsage
+	default:
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitError
+	}
+}
+
+// newFlagSet creates a flag set for a subcommand that reports errors instead of exiting
+func (r *runner) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(r.stderr)
+	return fs
+}
+
+// warn prints a message that doesn't fail the command
+func (r *runner) warn(format string, args ...any) {
+	fmt.Fprintf(r.stderr, "Warning: "+format+"\n", args...)
+}
+
+// parseArgs parses flags anywhere on the command line and returns the positional
+// arguments. Everything after "--" and negative numbers are positional.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for len(args) > 0 {
+		if isNegativeNumber(args[0]) {
+			positional = append(positional, args[0])
+			args = args[1:]
+			continue
+		}
+
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, usagef("%v", err)
+		}
+
+		rest := fs.Args()
+		consumed := len(args) - len(rest)
+		if consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			break
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+	return positional, nil
+}
+
+// isNegativeNumber returns true for arguments like "-45000" or "-3.50"
+func isNegativeNumber(arg string) bool {
+	return len(arg) > 1 && arg[0] == '-' && (arg[1] >= '0' && arg[1] <= '9' || arg[1] == '.')
+}
+
+// parseDate parses a command-line date: YYYY-MM-DD, MM/DD/YYYY, "today" or "yesterday"
+func parseDate(s string) (time.Time, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "today":
+		return ledger.Today(), nil
+	case "yesterday":
+		return ledger.Today().AddDate(0, 0, -1), nil
+	}
+
+	if date, err := time.ParseInLocation(ledger.DateFormat, s, time.Local); err == nil {
+		return date, nil
+	}
+	date, err := ledger.ParseDate(s)
+	if err != nil {
+		return time.Time{}, usagef("invalid date %q (use YYYY-MM-DD)", s)
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local), nil
+}
+
+// loadRange loads the days between from and to. An empty bound defaults to the
+// first or last day with data, so no flags means the whole ledger.
+func (r *runner) loadRange(from, to string) (*ledger.DateRange, error) {
+	start, end := ledger.Today(), ledger.Today()
+
+	dates, err := r.service.ListAvailableDates()
+	if err != nil {
+		return nil, err
+	}
+	if len(dates) > 0 {
+		first, last := dates[0], dates[len(dates)-1]
+		start = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, time.Local)
+		end = time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, time.Local)
+	}
+
+	if from != "" {
+		if start, err = parseDate(from); err != nil {
+			return nil, err
+		}
+	}
+	if to != "" {
+		if end, err = parseDate(to); err != nil {
+			return nil, err
+		}
+	}
+	if end.Before(start) {
+		return nil, usagef("--to %s is before --from %s", end.Format(ledger.DateFormat), start.Format(ledger.DateFormat))
+	}
+
+	return r.service.GetDateRange(start, end)
+}
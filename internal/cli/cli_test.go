@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"ledger-a/internal/config"
+	"ledger-a/internal/ledger"
+)
+
+// testConfig returns an offline config with a fixed rate and a fresh data directory
+func testConfig(t *testing.T) *config.Config {
+	t.Helper()
+	t.Chdir(t.TempDir()) // No ledger-data directory to warn about
+	cfg := &config.Config{
+		DataDir:       t.TempDir(),
+		HomeCurrency:  "CAD",
+		LocalCurrency: "IDR",
+		Offline:       true,
+		Rate:          10000,
+	}
+	if err := cfg.Resolve(); err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	return cfg
+}
+
+// run runs a command and returns its exit code and output
+func run(t *testing.T, cfg *config.Config, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := Run(cfg, args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestReadOnlyCommandsDontPinCurrencies(t *testing.T) {
+	cfg := testConfig(t)
+	pairFile := filepath.Join(cfg.DataDir, ledger.ConfigFileName)
+
+	for _, args := range [][]string{{"list"}, {"totals"}, {"export"}, {"rate"}} {
+		if code, _, stderr := run(t, cfg, args...); code != ExitOK {
+			t.Fatalf("%v exited %d: %s", args, code, stderr)
+		}
+		if _, err := os.Stat(pairFile); err == nil {
+			t.Fatalf("%v wrote %s", args, ledger.ConfigFileName)
+		}
+	}
+
+	if code, _, stderr := run(t, cfg, "add", "today", "Coffee", "35000"); code != ExitOK {
+		t.Fatalf("add exited %d: %s", code, stderr)
+	}
+	if _, err := os.Stat(pairFile); err != nil {
+		t.Errorf("add didn't save the currencies: %v", err)
+	}
+}
+
+func TestAddSpendingUnlessPlus(t *testing.T) {
+	cfg := testConfig(t)
+	tests := []struct {
+		args        []string
+		home, local string
+	}{
+		{[]string{"45000", "IDR"}, "-4.50", "-45000"},
+		{[]string{"--", "-45000"}, "-4.50", "-45000"},
+		{[]string{"+12", "CAD"}, "12.00", "120000"},
+	}
+	for _, tt := range tests {
+		args := append([]string{"add", "2026-10-03", "Entry"}, tt.args...)
+		code, stdout, stderr := run(t, cfg, args...)
+		if code != ExitOK {
+			t.Fatalf("%v exited %d: %s", args, code, stderr)
+		}
+		var entry entryJSON
+		if err := json.Unmarshal([]byte(stdout), &entry); err != nil {
+			t.Fatalf("%v printed %q: %v", args, stdout, err)
+		}
+		if string(entry.Home) != tt.home || string(entry.Local) != tt.local {
+			t.Errorf("%v added %s, %s, want %s, %s", args, entry.Home, entry.Local, tt.home, tt.local)
+		}
+	}
+}
+
+func TestExportLedgerFormats(t *testing.T) {
+	cfg := testConfig(t)
+	if code, _, stderr := run(t, cfg, "add", "2026-10-03", "Nasi goreng", "45000", "IDR"); code != ExitOK {
+		t.Fatalf("add exited %d: %s", code, stderr)
+	}
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"hledger", []string{"2026-10-03 * Nasi goreng", "45000 IDR @ 0.0001 CAD", "-4.50 CAD"}},
+		{"beancount", []string{`2026-10-03 * "Nasi goreng"`, "45000 IDR @ 0.0001 CAD", "-4.50 CAD"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			code, stdout, stderr := run(t, cfg, "export", "--format", tt.format)
+			if code != ExitOK {
+				t.Fatalf("export exited %d: %s", code, stderr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(stdout, want) {
+					t.Errorf("export is missing %q:\n%s", want, stdout)
+				}
+			}
+		})
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		args     []string
+		want     []string
+		category string
+	}{
+		{[]string{"today", "lunch", "45000"}, []string{"today", "lunch", "45000"}, ""},
+		{[]string{"--category", "food", "today", "lunch", "45000"}, []string{"today", "lunch", "45000"}, "food"},
+		{[]string{"today", "lunch", "--category", "food", "45000", "IDR"}, []string{"today", "lunch", "45000", "IDR"}, "food"},
+		{[]string{"today", "lunch", "-45000", "--category=food"}, []string{"today", "lunch", "-45000"}, "food"},
+		{[]string{"today", "-.5", "CAD"}, []string{"today", "-.5", "CAD"}, ""},
+		{[]string{"today", "--", "--category", "-x"}, []string{"today", "--category", "-x"}, ""},
+	}
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("add", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		category := fs.String("category", "", "")
+		got, err := parseArgs(fs, tt.args)
+		if err != nil {
+			t.Errorf("parseArgs(%q): %v", tt.args, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) || *category != tt.category {
+			t.Errorf("parseArgs(%q) = %q with category %q, want %q with %q", tt.args, got, *category, tt.want, tt.category)
+		}
+	}
+
+	fs := flag.NewFlagSet("add", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var usageErr *usageError
+	if _, err := parseArgs(fs, []string{"today", "--nope"}); !errors.As(err, &usageErr) {
+		t.Errorf("parseArgs with an unknown flag = %v, want a usage error", err)
+	}
+}
+
+func TestIsNegativeNumber(t *testing.T) {
+	tests := map[string]bool{
+		"-45000": true,
+		"-3.50":  true,
+		"-.5":    true,
+		"-":      false,
+		"--":     false,
+		"-x":     false,
+		"--rate": false,
+		"45000":  false,
+		"+12":    false,
+	}
+	for arg, want := range tests {
+		if got := isNegativeNumber(arg); got != want {
+			t.Errorf("isNegativeNumber(%q) = %v, want %v", arg, got, want)
+		}
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	cfg := testConfig(t)
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"help", []string{"help"}, ExitOK},
+		{"no command", nil, ExitOK},
+		{"command help", []string{"list", "-h"}, ExitOK},
+		{"unknown command", []string{"frobnicate"}, ExitUsage},
+		{"unknown flag", []string{"list", "--nope"}, ExitUsage},
+		{"missing arguments", []string{"add", "today"}, ExitUsage},
+		{"invalid date", []string{"add", "someday", "lunch", "45000"}, ExitUsage},
+		{"invalid amount", []string{"add", "today", "lunch", "lots"}, ExitUsage},
+		{"other currency", []string{"add", "today", "lunch", "45000", "EUR"}, ExitUsage},
+		{"unknown format", []string{"totals", "--format", "xml"}, ExitUsage},
+		{"range backwards", []string{"list", "--from", "2026-10-02", "--to", "2026-10-01"}, ExitUsage},
+		{"success", []string{"add", "today", "lunch", "45000"}, ExitOK},
+	}
+	for _, tt := range tests {
+		if code, _, stderr := run(t, cfg, tt.args...); code != tt.want {
+			t.Errorf("%s: exit code %d, want %d (%s)", tt.name, code, tt.want, stderr)
+		}
+	}
+
+	// No rate for a past day the cache doesn't have fails the command
+	offline := testConfig(t)
+	offline.Rate = 0
+	if code, _, _ := run(t, offline, "add", "2020-01-01", "lunch", "45000"); code != ExitError {
+		t.Errorf("add without a rate exited %d, want %d", code, ExitError)
+	}
+}
@@ -0,0 +1,523 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"ledger-a/internal/fsutil"
+	"ledger-a/internal/ledger"
+)
+
+// entryJSON is the machine-readable form of an entry. Amounts are exact decimals
+// in the ledger's currencies.
+type entryJSON struct {
+	ID          string        `json:"id"`
+	Date        string        `json:"date"`
+	Description string        `json:"description"`
+	Category    string        `json:"category"`
+	Tags        []string      `json:"tags"`
+	Home        json.Number   `json:"home"`
+	Local       json.Number   `json:"local"`
+	Rate        float64       `json:"rate,omitempty"`
+	Source      string        `json:"source,omitempty"`
+	Charges     *chargesJSON  `json:"charges,omitempty"`
+	PaidBy      string        `json:"paid_by,omitempty"`
+	Split       string        `json:"split,omitempty"`
+	Account     string        `json:"account,omitempty"`
+	Transfer    *transferJSON `json:"transfer,omitempty"`
+}
+
+// transferJSON is the money an entry moved to another account
+type transferJSON struct {
+	To     string      `json:"to"`
+	Home   json.Number `json:"home"`
+	Local  json.Number `json:"local"`
+	FXCost json.Number `json:"fx_cost,omitempty"` // Home amount paid on top of the market rate
+}
+
+// chargesJSON is the service charge, tax and tip breakdown of an entry, with
+// amounts in the currency the entry was entered in
+type chargesJSON struct {
+	Currency string      `json:"currency"`
+	Base     json.Number `json:"base"`
+	Service  float64     `json:"service"`
+	Tax      float64     `json:"tax"`
+	Tip      json.Number `json:"tip"`
+	Total    json.Number `json:"total"`
+}
+
+// newEntryJSON converts an entry for output
+func newEntryJSON(entry *ledger.Entry, currencies ledger.Pair) entryJSON {
+	tags := entry.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	var charges *chargesJSON
+	if c := entry.Charges; c != nil {
+		cur := entry.SourceCurrency(currencies)
+		charges = &chargesJSON{
+			Currency: cur.Code,
+			Base:     json.Number(c.Base.Format(cur)),
+			Service:  c.Service,
+			Tax:      c.Tax,
+			Tip:      json.Number(c.Tip.Format(cur)),
+			Total:    json.Number(c.Total().Format(cur)),
+		}
+	}
+	var paidBy, split string
+	if entry.Split != nil {
+		paidBy = entry.Payer
+		if paidBy == "" {
+			paidBy = ledger.Me
+		}
+		split = entry.Split.Format(entry.SourceCurrency(currencies))
+	}
+	var transfer *transferJSON
+	if t := entry.Transfer; t != nil {
+		transfer = &transferJSON{
+			To:    t.To,
+			Home:  json.Number(t.Home.Format(currencies.Home)),
+			Local: json.Number(t.Local.Format(currencies.Local)),
+		}
+		if cost, ok := entry.TransferFXCost(currencies); ok {
+			transfer.FXCost = json.Number(cost.Format(currencies.Home))
+		}
+	}
+	return entryJSON{
+		ID:          entry.ID,
+		Date:        entry.DateString(),
+		Description: entry.Description,
+		Category:    entry.Category,
+		Tags:        tags,
+		Home:        json.Number(entry.Home.Format(currencies.Home)),
+		Local:       json.Number(entry.Local.Format(currencies.Local)),
+		Rate:        entry.Rate,
+		Source:      entry.Source,
+		Charges:     charges,
+		PaidBy:      paidBy,
+		Split:       split,
+		Account:     entry.Account,
+		Transfer:    transfer,
+	}
+}
+
+// writeJSON writes v as a single line of JSON
+func writeJSON(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// tsvField keeps a value on one line and inside its column
+func tsvField(s string) string {
+	return strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(s)
+}
+
+// checkFormat validates a --format value
+func checkFormat(format string, allowed ...string) error {
+	for _, a := range allowed {
+		if format == a {
+			return nil
+		}
+	}
+	return usagef("unknown format %q (use %s)", format, strings.Join(allowed, " or "))
+}
+
+// rateOn returns the home to local rate for a date. A rate for today is refreshed
+// first unless it was already fetched today, so entries logged from cron or shell
+// aliases don't use a stale cached rate.
+func (r *runner) rateOn(date time.Time) (float64, error) {
+	c := r.converter
+	if !date.Before(ledger.Today()) && c.CanRefresh() && !sameDay(c.GetLastUpdated(), time.Now()) {
+		if err := c.RefreshRate(); err != nil {
+			r.warn("failed to refresh the %s/%s rate, using the cached rate: %v", c.From(), c.To(), err)
+		}
+	}
+
+	rate, err := c.RateOn(date)
+	if err != nil {
+		return 0, fmt.Errorf("no %s/%s rate for %s; pass --rate to set one: %w", c.From(), c.To(), date.Format(ledger.DateFormat), err)
+	}
+	if rate == 0 {
+		return 0, fmt.Errorf("no %s/%s rate available; pass --rate to set one", c.From(), c.To())
+	}
+	return rate, nil
+}
+
+// sameDay returns true if both times fall on the same calendar day
+func sameDay(a, b time.Time) bool {
+	return a.Format(ledger.DateFormat) == b.Format(ledger.DateFormat)
+}
+
+// runAdd adds an entry and prints it as JSON
+func runAdd(r *runner, args []string) error {
+	fs := r.newFlagSet("add")
+	category := fs.String("category", "", `category and tags (e.g., "food #dinner")`)
+	account := fs.String("account", "", "account it was paid from (default: the first configured)")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 3 || len(positional) > 4 {
+		return usagef("add takes DATE DESCRIPTION AMOUNT [CURRENCY]")
+	}
+
+	date, err := parseDate(positional[0])
+	if err != nil {
+		return err
+	}
+	description := strings.TrimSpace(positional[1])
+	if description == "" {
+		return usagef("description is empty")
+	}
+
+	currencies := r.service.GetCurrencies()
+	cur := currencies.Local
+	if len(positional) == 4 {
+		var ok bool
+		if cur, ok = currencies.Currency(positional[3]); !ok {
+			return usagef("currency %q is not part of this ledger (%s)", positional[3], currencies)
+		}
+	}
+	amount, err := ledger.ParseMoney(positional[2], cur)
+	if err != nil {
+		return usagef("%v", err)
+	}
+	// Amounts are spending unless a leading + marks them as income
+	if !strings.HasPrefix(strings.TrimSpace(positional[2]), "+") {
+		amount = -amount.Abs()
+	}
+
+	rate, err := r.rateOn(date)
+	if err != nil {
+		return err
+	}
+
+	day, err := r.service.OpenDay(date)
+	if day == nil {
+		return err
+	}
+	if err != nil {
+		r.warn("%v", err)
+	}
+
+	entry := ledger.NewEntry(date, description, 0, 0, day.ScreenTime)
+	entry.SetCategoryInput(*category)
+	if accounts := r.service.GetAccounts(); *account != "" {
+		a, ok := ledger.FindAccount(accounts, *account)
+		if !ok {
+			return usagef("account %q is not in the config", *account)
+		}
+		entry.Account = a.Name
+	} else if len(accounts) > 0 {
+		entry.Account = accounts[0].Name
+	}
+	entry.SetAmount(amount, cur, currencies, rate)
+
+	if err := r.service.AddEntry(day, entry); err != nil {
+		return err
+	}
+
+	// Record the add so it can be undone from the day editor
+	undoManager := ledger.NewUndoManager(r.service)
+	if err := undoManager.Load(); err != nil {
+		r.warn("undo history not loaded, not recording this entry: %v", err)
+	} else if err := undoManager.RecordAddEntry(date, entry); err != nil {
+		r.warn("%v", err)
+	}
+
+	return writeJSON(r.stdout, newEntryJSON(entry, currencies))
+}
+
+// runList prints the entries in a range
+func runList(r *runner, args []string) error {
+	fs := r.newFlagSet("list")
+	from := fs.String("from", "", "first date (default: first day with data)")
+	to := fs.String("to", "", "last date (default: last day with data)")
+	query := fs.String("query", "", "only entries matching this search")
+	format := fs.String("format", "tsv", "output format: tsv or json (one object per line)")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usagef("list takes no arguments")
+	}
+	if err := checkFormat(*format, "tsv", "json"); err != nil {
+		return err
+	}
+
+	dateRange, err := r.loadRange(*from, *to)
+	if err != nil {
+		return err
+	}
+
+	currencies := dateRange.Currencies
+	for _, entry := range dateRange.AllEntries(*query) {
+		if *format == "json" {
+			if err := writeJSON(r.stdout, newEntryJSON(entry, currencies)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		_, err := fmt.Fprintln(r.stdout, strings.Join([]string{
+			entry.DateString(),
+			entry.ID,
+			tsvField(entry.Description),
+			tsvField(entry.Category),
+			tsvField(ledger.FormatTags(entry.Tags)),
+			entry.Home.Format(currencies.Home),
+			entry.Local.Format(currencies.Local),
+		}, "\t"))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// categoryTotalJSON is the machine-readable form of a category subtotal
+type categoryTotalJSON struct {
+	Category string      `json:"category"`
+	Home     json.Number `json:"home"`
+	Local    json.Number `json:"local"`
+	Count    int         `json:"count"`
+}
+
+// totalsJSON is the machine-readable output of the totals command
+type totalsJSON struct {
+	From          string              `json:"from"`
+	To            string              `json:"to"`
+	HomeCurrency  string              `json:"home_currency"`
+	LocalCurrency string              `json:"local_currency"`
+	Home          json.Number         `json:"home"`
+	Local         json.Number         `json:"local"`
+	Count         int                 `json:"count"`
+	Categories    []categoryTotalJSON `json:"categories,omitempty"`
+}
+
+// runTotals prints the totals for a range, optionally by category
+func runTotals(r *runner, args []string) error {
+	fs := r.newFlagSet("totals")
+	from := fs.String("from", "", "first date (default: first day with data)")
+	to := fs.String("to", "", "last date (default: last day with data)")
+	query := fs.String("query", "", "only entries matching this search")
+	byCategory := fs.Bool("by-category", false, "print a subtotal per category")
+	format := fs.String("format", "tsv", "output format: tsv or json")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usagef("totals takes no arguments")
+	}
+	if err := checkFormat(*format, "tsv", "json"); err != nil {
+		return err
+	}
+
+	dateRange, err := r.loadRange(*from, *to)
+	if err != nil {
+		return err
+	}
+	currencies := dateRange.Currencies
+
+	var categories []ledger.CategoryTotal
+	if *byCategory {
+		categories = dateRange.CategoryTotals(*query)
+	}
+
+	if *format == "json" {
+		out := totalsJSON{
+			From:          dateRange.Start.Format(ledger.DateFormat),
+			To:            dateRange.End.Format(ledger.DateFormat),
+			HomeCurrency:  currencies.Home.Code,
+			LocalCurrency: currencies.Local.Code,
+			Home:          json.Number(dateRange.FilteredTotalHome(*query).Format(currencies.Home)),
+			Local:         json.Number(dateRange.FilteredTotalLocal(*query).Format(currencies.Local)),
+			Count:         len(dateRange.Counted(*query)),
+		}
+		for _, total := range categories {
+			out.Categories = append(out.Categories, categoryTotalJSON{
+				Category: total.Category,
+				Home:     json.Number(total.Home.Format(currencies.Home)),
+				Local:    json.Number(total.Local.Format(currencies.Local)),
+				Count:    total.Count,
+			})
+		}
+		return writeJSON(r.stdout, out)
+	}
+
+	if *byCategory {
+		for _, total := range categories {
+			_, err := fmt.Fprintf(r.stdout, "%s\t%s\t%s\t%d\n", tsvField(total.Category),
+				total.Home.Format(currencies.Home), total.Local.Format(currencies.Local), total.Count)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	_, err = fmt.Fprintf(r.stdout, "%s\t%s\t%d\n",
+		dateRange.FilteredTotalHome(*query).Format(currencies.Home),
+		dateRange.FilteredTotalLocal(*query).Format(currencies.Local),
+		len(dateRange.Counted(*query)))
+	return err
+}
+
+// dayJSON is the machine-readable form of a day, including its journal
+type dayJSON struct {
+	Date       string      `json:"date"`
+	ScreenTime string      `json:"screen_time,omitempty"`
+	Journal    string      `json:"journal,omitempty"`
+	Entries    []entryJSON `json:"entries"`
+}
+
+// exportJSON is the machine-readable output of the export command
+type exportJSON struct {
+	From          string    `json:"from"`
+	To            string    `json:"to"`
+	HomeCurrency  string    `json:"home_currency"`
+	LocalCurrency string    `json:"local_currency"`
+	Days          []dayJSON `json:"days"`
+}
+
+// runExport writes a range as CSV (entries), JSON (days with journals) or a
+// plain-text accounting file (hledger or Beancount)
+func runExport(r *runner, args []string) error {
+	fs := r.newFlagSet("export")
+	from := fs.String("from", "", "first date (default: first day with data)")
+	to := fs.String("to", "", "last date (default: last day with data)")
+	format := fs.String("format", "csv", "output format: csv, json, hledger or beancount")
+	output := fs.String("output", "", "write to this file instead of standard output")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usagef("export takes no arguments")
+	}
+	if err := checkFormat(*format, "csv", "json", "hledger", "beancount"); err != nil {
+		return err
+	}
+
+	dateRange, err := r.loadRange(*from, *to)
+	if err != nil {
+		return err
+	}
+
+	write := func(w io.Writer) error {
+		switch *format {
+		case "csv":
+			return r.service.GetCSVManager().WriteCSV(w, dateRange.Days)
+		case "hledger":
+			return ledger.WriteHledger(w, dateRange, r.service.GetAccounts())
+		case "beancount":
+			return ledger.WriteBeancount(w, dateRange, r.service.GetAccounts())
+		}
+		return writeExportJSON(w, dateRange)
+	}
+
+	if *output == "" {
+		return write(r.stdout)
+	}
+	if err := fsutil.WriteFileAtomic(*output, 0644, write); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *output, err)
+	}
+	return nil
+}
+
+// writeExportJSON writes a range as an indented JSON document
+func writeExportJSON(w io.Writer, dateRange *ledger.DateRange) error {
+	currencies := dateRange.Currencies
+	out := exportJSON{
+		From:          dateRange.Start.Format(ledger.DateFormat),
+		To:            dateRange.End.Format(ledger.DateFormat),
+		HomeCurrency:  currencies.Home.Code,
+		LocalCurrency: currencies.Local.Code,
+		Days:          make([]dayJSON, 0, len(dateRange.Days)),
+	}
+	for _, day := range dateRange.Days {
+		d := dayJSON{
+			Date:       day.Date.Format(ledger.DateFormat),
+			ScreenTime: day.ScreenTime,
+			Journal:    day.Journal,
+			Entries:    make([]entryJSON, 0, len(day.Entries)),
+		}
+		for _, entry := range day.Entries {
+			d.Entries = append(d.Entries, newEntryJSON(entry, currencies))
+		}
+		out.Days = append(out.Days, d)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+// rateJSON is the machine-readable output of the rate command
+type rateJSON struct {
+	From    string  `json:"from"`
+	To      string  `json:"to"`
+	Rate    float64 `json:"rate"`
+	Date    string  `json:"date"`
+	Updated string  `json:"updated,omitempty"` // When the latest rate was fetched
+}
+
+// runRate prints the home to local rate, refreshing today's rate from the API
+func runRate(r *runner, args []string) error {
+	fs := r.newFlagSet("rate")
+	dateFlag := fs.String("date", "", "date of a historical rate (default: today)")
+	format := fs.String("format", "tsv", "output format: tsv or json")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usagef("rate takes no arguments")
+	}
+	if err := checkFormat(*format, "tsv", "json"); err != nil {
+		return err
+	}
+
+	date := ledger.Today()
+	if *dateFlag != "" {
+		if date, err = parseDate(*dateFlag); err != nil {
+			return err
+		}
+	}
+
+	c := r.converter
+	latest := !date.Before(ledger.Today())
+	var rate float64
+	if latest {
+		// Always ask the API when the rate is requested explicitly
+		if c.CanRefresh() {
+			if err := c.RefreshRate(); err != nil {
+				if c.GetRate() == 0 {
+					return fmt.Errorf("failed to fetch the %s/%s rate: %w", c.From(), c.To(), err)
+				}
+				r.warn("failed to refresh the %s/%s rate, using the cached rate: %v", c.From(), c.To(), err)
+			}
+		}
+		if rate = c.GetRate(); rate == 0 {
+			return fmt.Errorf("no %s/%s rate available; pass --rate to set one", c.From(), c.To())
+		}
+	} else if rate, err = r.rateOn(date); err != nil {
+		return err
+	}
+
+	out := rateJSON{From: c.From(), To: c.To(), Rate: rate, Date: date.Format(ledger.DateFormat)}
+	if latest && r.cfg.Rate == 0 && !c.GetLastUpdated().IsZero() {
+		out.Updated = c.GetLastUpdated().Format(time.RFC3339)
+	}
+
+	if *format == "json" {
+		return writeJSON(r.stdout, out)
+	}
+	_, err = fmt.Fprintf(r.stdout, "%s\t%s\t%s\t%s\n", out.From, out.To, strconv.FormatFloat(rate, 'f', -1, 64), out.Date)
+	return err
+}
@@ -0,0 +1,307 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ledger-a/internal/ledger"
+)
+
+const (
+	// AppName is the directory name used under the XDG config and data directories
+	AppName = "ledger-a"
+	// FileName is the name of the config file in the config directory
+	FileName = "config.json"
+)
+
+// Config holds the settings read from the config file and command-line flags
+type Config struct {
+	DataDir          string    `json:"data_dir"`           // Ledger data directory
+	CacheDir         string    `json:"cache_dir"`          // Exchange rate cache directory ("" = data directory)
+	HomeCurrency     string    `json:"home_currency"`      // Default home currency for new ledgers
+	LocalCurrency    string    `json:"local_currency"`     // Default local currency for new ledgers
+	Offline          bool      `json:"offline"`            // Never contact the rate API
+	Rate             float64   `json:"rate"`               // Fixed home to local rate (0 = use the API)
+	Budgets          []Budget  `json:"budgets"`            // Spending limits shown in the editor and range view
+	Charges          *Charges  `json:"charges"`            // Restaurant service charge and tax (unset = 10% and 11%)
+	Accounts         []Account `json:"accounts"`           // Cash wallet, cards and bank accounts; the first is the default
+	IgnoreLegacyData bool      `json:"ignore_legacy_data"` // Don't warn about ./ledger-data from older versions
+}
+
+// Account is somewhere money is paid from as written in the config file
+type Account struct {
+	Name     string      `json:"name"`     // e.g., "cash" or "wise"
+	Currency string      `json:"currency"` // Currency of the balance ("" = the ledger's local currency)
+	Opening  json.Number `json:"opening"`  // Balance before the first entry ("" = 0)
+}
+
+// Charges is the service charge and tax profile as written in the config file
+type Charges struct {
+	Service float64 `json:"service"` // Service charge in percent
+	Tax     float64 `json:"tax"`     // Tax in percent, on the menu prices plus the service charge
+}
+
+// Budget is a spending limit as written in the config file
+type Budget struct {
+	Period   string      `json:"period"`   // day, week or month ("" = month)
+	Category string      `json:"category"` // Only count entries in this category ("" = all spending)
+	Amount   json.Number `json:"amount"`   // Limit per period
+	Currency string      `json:"currency"` // Currency of the amount ("" = the ledger's home currency)
+}
+
+// DefaultPath returns the config file path in the XDG config directory
+// (e.g., ~/.config/ledger-a/config.json)
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find config directory: %w", err)
+	}
+	return filepath.Join(dir, AppName, FileName), nil
+}
+
+// DefaultDataDir returns the data directory used when none is configured
+// (e.g., ~/.local/share/ledger-a)
+func DefaultDataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, AppName), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", AppName), nil
+}
+
+// Load reads the config file at path. A missing file yields an empty config.
+func Load(path string) (*Config, error) {
+	cfg := &Config{}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Resolve fills in defaults, expands paths and validates the config.
+// The data directory is the one set by flag or in the config file, or the XDG
+// default; the working directory is never used (see LegacyDataNotice).
+func (c *Config) Resolve() error {
+	if c.DataDir == "" {
+		dir, err := DefaultDataDir()
+		if err != nil {
+			return err
+		}
+		c.DataDir = dir
+	}
+
+	dataDir, err := expandPath(c.DataDir)
+	if err != nil {
+		return fmt.Errorf("invalid data directory: %w", err)
+	}
+	c.DataDir = dataDir
+
+	if c.CacheDir == "" {
+		c.CacheDir = c.DataDir
+	}
+	cacheDir, err := expandPath(c.CacheDir)
+	if err != nil {
+		return fmt.Errorf("invalid cache directory: %w", err)
+	}
+	c.CacheDir = cacheDir
+
+	if c.Rate < 0 {
+		return fmt.Errorf("rate must be positive, got %v", c.Rate)
+	}
+
+	if c.Charges != nil && (c.Charges.Service < 0 || c.Charges.Tax < 0) {
+		return fmt.Errorf("charges must not be negative, got %v%% service and %v%% tax", c.Charges.Service, c.Charges.Tax)
+	}
+
+	if _, err := c.DefaultCurrencies(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// LegacyDataNotice returns a notice about a ledger-data directory in the working
+// directory, where older versions kept their data, unless it is the data
+// directory. It is reported on every run until the directory is moved away or
+// IgnoreLegacyData is set. Returns "" if there's nothing to report.
+func (c *Config) LegacyDataNotice() string {
+	if c.IgnoreLegacyData {
+		return ""
+	}
+	info, err := os.Stat(ledger.DataDir)
+	if err != nil || !info.IsDir() {
+		return ""
+	}
+	legacy, err := filepath.Abs(ledger.DataDir)
+	if err != nil || legacy == c.DataDir {
+		return ""
+	}
+	return fmt.Sprintf("%s from an older version isn't used anymore; move its contents to %s, pass --data-dir %s to keep using it, or set \"ignore_legacy_data\": true in the config file to stop this warning", legacy, c.DataDir, legacy)
+}
+
+// ChargeProfile returns the configured service charge and tax, or
+// ledger.DefaultChargeProfile if none is set
+func (c *Config) ChargeProfile() ledger.ChargeProfile {
+	if c.Charges == nil {
+		return ledger.DefaultChargeProfile
+	}
+	return ledger.ChargeProfile{Service: c.Charges.Service, Tax: c.Charges.Tax}
+}
+
+// LedgerBudgets returns the configured budgets in a ledger's currencies
+func (c *Config) LedgerBudgets(pair ledger.Pair) ([]ledger.Budget, error) {
+	budgets := make([]ledger.Budget, 0, len(c.Budgets))
+	for i, budget := range c.Budgets {
+		b, err := budget.parse(pair)
+		if err != nil {
+			return nil, fmt.Errorf("invalid budget %d: %w", i+1, err)
+		}
+		budgets = append(budgets, b)
+	}
+	return budgets, nil
+}
+
+// LedgerAccounts returns the configured accounts in a ledger's currencies
+func (c *Config) LedgerAccounts(pair ledger.Pair) ([]ledger.Account, error) {
+	accounts := make([]ledger.Account, 0, len(c.Accounts))
+	for i, account := range c.Accounts {
+		a, err := account.parse(pair)
+		if err != nil {
+			return nil, fmt.Errorf("invalid account %d: %w", i+1, err)
+		}
+		if _, ok := ledger.FindAccount(accounts, a.Name); ok {
+			return nil, fmt.Errorf("invalid account %d: %s is listed twice", i+1, a.Name)
+		}
+		accounts = append(accounts, a)
+	}
+	return accounts, nil
+}
+
+// parse checks the account against a ledger's currencies
+func (a Account) parse(pair ledger.Pair) (ledger.Account, error) {
+	name := strings.TrimSpace(a.Name)
+	if name == "" {
+		return ledger.Account{}, fmt.Errorf("name is missing")
+	}
+	if strings.ContainsAny(name, ">,") {
+		return ledger.Account{}, fmt.Errorf("name %q must not contain > or ,", name)
+	}
+
+	cur := pair.Local
+	if a.Currency != "" {
+		c, ok := pair.Currency(a.Currency)
+		if !ok {
+			return ledger.Account{}, fmt.Errorf("currency %s is not one of the ledger's currencies (%s)", a.Currency, pair)
+		}
+		cur = c
+	}
+
+	var opening ledger.Money
+	if a.Opening != "" {
+		var err error
+		if opening, err = ledger.ParseMoney(a.Opening.String(), cur); err != nil {
+			return ledger.Account{}, err
+		}
+	}
+	return ledger.Account{Name: name, Currency: cur, Opening: opening}, nil
+}
+
+// parse checks the budget against a ledger's currencies
+func (b Budget) parse(pair ledger.Pair) (ledger.Budget, error) {
+	period := ledger.BudgetMonth
+	if b.Period != "" {
+		p, err := ledger.ParseBudgetPeriod(b.Period)
+		if err != nil {
+			return ledger.Budget{}, err
+		}
+		period = p
+	}
+
+	cur := pair.Home
+	if b.Currency != "" {
+		c, ok := pair.Currency(b.Currency)
+		if !ok {
+			return ledger.Budget{}, fmt.Errorf("currency %s is not one of the ledger's currencies (%s)", b.Currency, pair)
+		}
+		cur = c
+	}
+
+	limit, err := ledger.ParseMoney(b.Amount.String(), cur)
+	if err != nil {
+		return ledger.Budget{}, err
+	}
+	if limit <= 0 {
+		return ledger.Budget{}, fmt.Errorf("amount must be positive, got %s", b.Amount)
+	}
+
+	return ledger.Budget{
+		Period:   period,
+		Category: strings.ToLower(strings.TrimSpace(b.Category)),
+		Limit:    limit,
+		Currency: cur,
+	}, nil
+}
+
+// DefaultCurrencies returns the configured currency pair, or ledger.DefaultPair if none is set
+func (c *Config) DefaultCurrencies() (ledger.Pair, error) {
+	if c.HomeCurrency == "" && c.LocalCurrency == "" {
+		return ledger.DefaultPair, nil
+	}
+
+	home, local := c.HomeCurrency, c.LocalCurrency
+	if home == "" {
+		home = ledger.DefaultPair.Home.Code
+	}
+	if local == "" {
+		local = ledger.DefaultPair.Local.Code
+	}
+	return ledger.NewPair(home, local)
+}
+
+// Currencies returns the pair for the configured ledger: the data directory's own
+// config if it has one, otherwise the default currencies, which are then saved to
+// the data directory. It fails if the directory's entries were written in other
+// currencies.
+func (c *Config) Currencies() (ledger.Pair, error) {
+	fallback, err := c.DefaultCurrencies()
+	if err != nil {
+		return ledger.Pair{}, err
+	}
+	return ledger.OpenPair(c.DataDir, fallback)
+}
+
+// ReadCurrencies returns the pair like Currencies without saving it to the data
+// directory, for commands that only read the ledger
+func (c *Config) ReadCurrencies() (ledger.Pair, error) {
+	fallback, err := c.DefaultCurrencies()
+	if err != nil {
+		return ledger.Pair{}, err
+	}
+	return ledger.ResolvePair(c.DataDir, fallback)
+}
+
+// expandPath expands a leading ~ and makes the path absolute
+func expandPath(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	return filepath.Abs(path)
+}
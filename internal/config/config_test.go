@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveIgnoresWorkingDirectory(t *testing.T) {
+	work := t.TempDir()
+	t.Chdir(work)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(t.TempDir(), "share"))
+	if err := os.Mkdir("ledger-data", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Config{}
+	if err := cfg.Resolve(); err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	want := filepath.Join(os.Getenv("XDG_DATA_HOME"), AppName)
+	if cfg.DataDir != want {
+		t.Errorf("DataDir = %s, want %s", cfg.DataDir, want)
+	}
+
+	// The legacy directory is reported on every run until it's moved or ignored
+	for range 2 {
+		if notice := cfg.LegacyDataNotice(); !strings.Contains(notice, filepath.Join(work, "ledger-data")) {
+			t.Errorf("notice %q doesn't name the legacy directory", notice)
+		}
+	}
+	cfg.IgnoreLegacyData = true
+	if notice := cfg.LegacyDataNotice(); notice != "" {
+		t.Errorf("LegacyDataNotice = %q with the warning ignored, want nothing", notice)
+	}
+	cfg.IgnoreLegacyData = false
+	if err := os.Rename("ledger-data", "old-ledger"); err != nil {
+		t.Fatal(err)
+	}
+	if notice := cfg.LegacyDataNotice(); notice != "" {
+		t.Errorf("LegacyDataNotice = %q after moving the directory, want nothing", notice)
+	}
+}
+
+func TestLegacyDataNoticeWhenUsed(t *testing.T) {
+	work := t.TempDir()
+	t.Chdir(work)
+	if err := os.Mkdir("ledger-data", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Config{DataDir: "ledger-data"}
+	if err := cfg.Resolve(); err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if notice := cfg.LegacyDataNotice(); notice != "" {
+		t.Errorf("LegacyDataNotice = %q for the data directory itself, want nothing", notice)
+	}
+}
@@ -12,6 +12,8 @@ const (
 	FrankfurterAPI = "https://api.frankfurter.app"
 	// Timeout for API requests
 	APITimeout = 10 * time.Second
+	// DateFormat is the date format used by the historical endpoint
+	DateFormat = "2006-01-02"
 )
 
 // APIResponse represents the response from frankfurter.app
@@ -25,21 +27,39 @@ type APIResponse struct {
 // Client handles currency API operations
 type Client struct {
 	httpClient *http.Client
+	baseURL    string
 }
 
 // NewClient creates a new currency API client
 func NewClient() *Client {
+	return NewClientWithBaseURL(FrankfurterAPI)
+}
+
+// NewClientWithBaseURL creates a new currency API client against a custom base URL
+func NewClientWithBaseURL(baseURL string) *Client {
 	return &Client{
 		httpClient: &http.Client{
 			Timeout: APITimeout,
 		},
+		baseURL: baseURL,
 	}
 }
 
-// FetchRate fetches the exchange rate from one currency to another
+// FetchRate fetches the latest exchange rate from one currency to another
 func (c *Client) FetchRate(from, to string) (float64, error) {
-	url := fmt.Sprintf("%s/latest?from=%s&to=%s", FrankfurterAPI, from, to)
-	
+	return c.fetch("latest", from, to)
+}
+
+// FetchRateOn fetches the exchange rate from one currency to another on a given date.
+// For weekends and holidays the API returns the rate of the previous business day.
+func (c *Client) FetchRateOn(date time.Time, from, to string) (float64, error) {
+	return c.fetch(date.Format(DateFormat), from, to)
+}
+
+// fetch requests the rate for the given endpoint ("latest" or a YYYY-MM-DD date)
+func (c *Client) fetch(endpoint, from, to string) (float64, error) {
+	url := fmt.Sprintf("%s/%s?from=%s&to=%s", c.baseURL, endpoint, from, to)
+
 	resp, err := c.httpClient.Get(url)
 	if err != nil {
 		return 0, fmt.Errorf("failed to fetch exchange rate: %w", err)
@@ -62,13 +82,3 @@ func (c *Client) FetchRate(from, to string) (float64, error) {
 
 	return rate, nil
 }
-
-// FetchCADToIDR fetches the CAD to IDR exchange rate
-func (c *Client) FetchCADToIDR() (float64, error) {
-	return c.FetchRate("CAD", "IDR")
-}
-
-// FetchIDRToCAD fetches the IDR to CAD exchange rate
-func (c *Client) FetchIDRToCAD() (float64, error) {
-	return c.FetchRate("IDR", "CAD")
-}
@@ -0,0 +1,55 @@
+package currency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientFetchRateOn(t *testing.T) {
+	var gotPath, gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+		w.Write([]byte(`{"amount":1.0,"base":"CAD","date":"2024-03-15","rates":{"IDR":11512.5}}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL(server.URL)
+	rate, err := client.FetchRateOn(time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local), "CAD", "IDR")
+	if err != nil {
+		t.Fatalf("FetchRateOn: %v", err)
+	}
+	if rate != 11512.5 {
+		t.Errorf("rate = %v, want 11512.5", rate)
+	}
+	if gotPath != "/2024-03-15" || gotQuery != "from=CAD&to=IDR" {
+		t.Errorf("requested %s?%s, want /2024-03-15?from=CAD&to=IDR", gotPath, gotQuery)
+	}
+}
+
+func TestClientFetchRateOnErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"status", http.StatusNotFound, `{"message":"not found"}`},
+		{"missing rate", http.StatusOK, `{"amount":1.0,"base":"CAD","rates":{"USD":0.73}}`},
+		{"bad body", http.StatusOK, `not json`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClientWithBaseURL(server.URL)
+			if rate, err := client.FetchRateOn(time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local), "CAD", "IDR"); err == nil {
+				t.Errorf("FetchRateOn = %v, want an error", rate)
+			}
+		})
+	}
+}
@@ -5,73 +5,174 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
+
+	"ledger-a/internal/fsutil"
 )
 
 const (
 	// CacheFileName is the name of the rate cache file
 	CacheFileName = ".rate_cache.json"
-	// DefaultCADToIDR is the fallback rate if no cache exists
-	DefaultCADToIDR = 11800.0
+	// HistoricalRetryDelay is how long a date whose historical rate couldn't be
+	// fetched waits before it is fetched again in the background
+	HistoricalRetryDelay = 5 * time.Minute
 )
 
-// RateCache represents the cached exchange rate
+// DefaultRates are the fallback rates (to units per from unit) used when a pair
+// has never been fetched. Pairs not listed have no fallback until the first refresh.
+var DefaultRates = map[string]float64{
+	"CAD/IDR": 11800.0,
+}
+
+// PairRates holds the cached rates for one currency pair
+type PairRates struct {
+	Rate        float64            `json:"rate"` // Latest rate
+	LastUpdated time.Time          `json:"last_updated"`
+	Historical  map[string]float64 `json:"historical"` // YYYY-MM-DD -> rate on that date
+}
+
+// RateCache represents the cached exchange rates, keyed by pair (e.g., "CAD/IDR")
 type RateCache struct {
-	CADToIDR    float64   `json:"cad_to_idr"`
-	LastUpdated time.Time `json:"last_updated"`
+	Pairs map[string]*PairRates `json:"pairs"`
+
+	// Caches written before pairs were configurable held a single CAD to IDR rate
+	LegacyCADToIDR    float64            `json:"cad_to_idr,omitempty"`
+	LegacyLastUpdated *time.Time         `json:"last_updated,omitempty"`
+	LegacyHistorical  map[string]float64 `json:"historical,omitempty"`
+}
+
+// pairKey returns the cache key for a pair
+func pairKey(from, to string) string {
+	return from + "/" + to
+}
+
+// newPairRates returns rates holding only the fallback rate for a pair
+func newPairRates(key string) *PairRates {
+	return &PairRates{
+		Rate:        DefaultRates[key],
+		LastUpdated: time.Time{},
+		Historical:  make(map[string]float64),
+	}
 }
 
-// Converter handles currency conversion with caching
+// migrateLegacy moves a single-pair cache into the CAD/IDR entry
+func (rc *RateCache) migrateLegacy() {
+	if rc.LegacyCADToIDR == 0 {
+		return
+	}
+
+	key := pairKey("CAD", "IDR")
+	if _, ok := rc.Pairs[key]; !ok {
+		rates := newPairRates(key)
+		rates.Rate = rc.LegacyCADToIDR
+		if rc.LegacyLastUpdated != nil {
+			rates.LastUpdated = *rc.LegacyLastUpdated
+		}
+		for date, rate := range rc.LegacyHistorical {
+			rates.Historical[date] = rate
+		}
+		rc.Pairs[key] = rates
+	}
+
+	rc.LegacyCADToIDR = 0
+	rc.LegacyLastUpdated = nil
+	rc.LegacyHistorical = nil
+}
+
+// Converter handles currency conversion with caching for one pair
 type Converter struct {
 	client   *Client
 	cache    *RateCache
+	rates    *PairRates // Entry of cache for from/to
+	from     string
+	to       string
 	cacheDir string
 	offline  bool
 	lastErr  error
+	failedOn map[string]time.Time // YYYY-MM-DD -> when fetching its historical rate last failed
+
+	offlineMode bool    // Never contact the API, only use cached rates
+	fixedRate   float64 // Rate used for every conversion instead of API rates (0 if unset)
+}
+
+// NewConverter creates a new currency converter from one currency to another
+// (e.g., "CAD" to "IDR")
+func NewConverter(cacheDir, from, to string) *Converter {
+	return NewConverterWithClient(cacheDir, from, to, NewClient())
 }
 
-// NewConverter creates a new currency converter
-func NewConverter(cacheDir string) *Converter {
+// NewConverterWithClient creates a new currency converter using the given API client
+func NewConverterWithClient(cacheDir, from, to string, client *Client) *Converter {
 	c := &Converter{
-		client:   NewClient(),
+		client:   client,
+		from:     from,
+		to:       to,
 		cacheDir: cacheDir,
 		offline:  false,
+		failedOn: make(map[string]time.Time),
 	}
 	c.loadCache()
 	return c
 }
 
+// From returns the code of the currency converted from
+func (c *Converter) From() string {
+	return c.from
+}
+
+// To returns the code of the currency converted to
+func (c *Converter) To() string {
+	return c.to
+}
+
+// SetOfflineMode stops the converter from contacting the API; cached rates are still used
+func (c *Converter) SetOfflineMode(offline bool) {
+	c.offlineMode = offline
+}
+
+// SetFixedRate makes every conversion use the given rate instead of API rates.
+// A rate of 0 goes back to API rates.
+func (c *Converter) SetFixedRate(rate float64) {
+	c.fixedRate = rate
+}
+
 // getCachePath returns the full path to the cache file
 func (c *Converter) getCachePath() string {
 	return filepath.Join(c.cacheDir, CacheFileName)
 }
 
-// loadCache loads the cached rate from disk
+// loadCache loads the cached rates from disk
 func (c *Converter) loadCache() {
-	path := c.getCachePath()
-	data, err := os.ReadFile(path)
-	if err != nil {
-		// No cache exists, use default
-		c.cache = &RateCache{
-			CADToIDR:    DefaultCADToIDR,
-			LastUpdated: time.Time{},
+	cache := &RateCache{}
+	if data, err := os.ReadFile(c.getCachePath()); err == nil {
+		// A corrupt cache is treated like a missing one
+		if err := json.Unmarshal(data, cache); err != nil {
+			cache = &RateCache{}
 		}
-		return
 	}
+	if cache.Pairs == nil {
+		cache.Pairs = make(map[string]*PairRates)
+	}
+	cache.migrateLegacy()
 
-	var cache RateCache
-	if err := json.Unmarshal(data, &cache); err != nil {
-		c.cache = &RateCache{
-			CADToIDR:    DefaultCADToIDR,
-			LastUpdated: time.Time{},
-		}
-		return
+	key := pairKey(c.from, c.to)
+	rates, ok := cache.Pairs[key]
+	if !ok {
+		rates = newPairRates(key)
+		cache.Pairs[key] = rates
+	}
+	// Caches written before historical rates were tracked have no map
+	if rates.Historical == nil {
+		rates.Historical = make(map[string]float64)
 	}
 
-	c.cache = &cache
+	c.cache = cache
+	c.rates = rates
 }
 
-// saveCache saves the current rate to disk
+// saveCache saves the cached rates to disk
 func (c *Converter) saveCache() error {
 	if err := os.MkdirAll(c.cacheDir, 0755); err != nil {
 		return fmt.Errorf("failed to create cache directory: %w", err)
@@ -83,59 +184,133 @@ func (c *Converter) saveCache() error {
 	}
 
 	path := c.getCachePath()
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := fsutil.WriteFile(path, data, 0644); err != nil {
 		return fmt.Errorf("failed to write cache: %w", err)
 	}
 
 	return nil
 }
 
-// RefreshRate fetches the latest exchange rate from the API
+// RefreshRate fetches the latest exchange rate from the API.
+// It does nothing in offline mode or with a fixed rate.
 func (c *Converter) RefreshRate() error {
-	rate, err := c.client.FetchCADToIDR()
+	if !c.CanRefresh() {
+		return nil
+	}
+	return c.ApplyRefresh(c.FetchLatest())
+}
+
+// CanRefresh returns false if the converter never contacts the API
+// (offline mode or a fixed rate)
+func (c *Converter) CanRefresh() bool {
+	return !c.offlineMode && c.fixedRate == 0
+}
+
+// FetchLatest fetches the latest rate without touching the converter's state,
+// so it can run in the background. Pass the result to ApplyRefresh.
+func (c *Converter) FetchLatest() (float64, error) {
+	return c.client.FetchRate(c.from, c.to)
+}
+
+// ApplyRefresh records the result of FetchLatest: a successful fetch updates the
+// cached rate and clears the offline state and the failed historical fetches, a
+// failed one marks the converter offline
+func (c *Converter) ApplyRefresh(rate float64, err error) error {
 	if err != nil {
 		c.offline = true
 		c.lastErr = err
 		return err
 	}
 
-	c.cache = &RateCache{
-		CADToIDR:    rate,
-		LastUpdated: time.Now(),
-	}
+	c.rates.Rate = rate
+	c.rates.LastUpdated = time.Now()
 	c.offline = false
 	c.lastErr = nil
+	clear(c.failedOn)
 
 	return c.saveCache()
 }
 
-// GetCADToIDRRate returns the current CAD to IDR rate
-func (c *Converter) GetCADToIDRRate() float64 {
-	return c.cache.CADToIDR
+// RateOn returns the rate (to units per from unit) for a given date.
+// Past dates use the historical rate, fetched once and then served from the cache.
+// Today and future dates use the latest rate. A fixed rate applies to every date.
+// It returns an error rather than another day's rate if the historical rate can't
+// be fetched, and blocks on the API, so interactive callers use CachedRateOn and
+// fetch missing rates in the background with FetchRateOn.
+func (c *Converter) RateOn(date time.Time) (float64, error) {
+	if rate, ok := c.CachedRateOn(date); ok || !isPastDate(date) {
+		return rate, nil
+	}
+	if c.offlineMode {
+		return 0, fmt.Errorf("no cached %s rate for %s in offline mode", pairKey(c.from, c.to), date.Format(DateFormat))
+	}
+	rate, err := c.FetchRateOn(date)
+	if err := c.ApplyRateOn(date, rate, err); err != nil {
+		return 0, err
+	}
+	return rate, nil
 }
 
-// GetIDRToCADRate returns the current IDR to CAD rate
-func (c *Converter) GetIDRToCADRate() float64 {
-	if c.cache.CADToIDR == 0 {
-		return 0
+// CachedRateOn returns the rate for a date like RateOn, but never contacts the API.
+// It returns false if a past date's historical rate isn't cached (or, for today,
+// if no rate has been fetched yet).
+func (c *Converter) CachedRateOn(date time.Time) (float64, bool) {
+	if c.fixedRate != 0 {
+		return c.fixedRate, true
 	}
-	return 1.0 / c.cache.CADToIDR
+	if !isPastDate(date) {
+		return c.rates.Rate, c.rates.Rate != 0
+	}
+	rate, ok := c.rates.Historical[date.Format(DateFormat)]
+	return rate, ok
 }
 
-// CADToIDR converts a CAD amount to IDR
-func (c *Converter) CADToIDR(cad float64) float64 {
-	return cad * c.cache.CADToIDR
+// FetchRateOn fetches the historical rate for a date without touching the
+// converter's state, so it can run in the background. Pass the result to ApplyRateOn.
+func (c *Converter) FetchRateOn(date time.Time) (float64, error) {
+	return c.client.FetchRateOn(date, c.from, c.to)
 }
 
-// IDRToCAD converts an IDR amount to CAD
-func (c *Converter) IDRToCAD(idr float64) float64 {
-	if c.cache.CADToIDR == 0 {
-		return 0
+// ApplyRateOn records the result of fetching the historical rate for a date:
+// a successful fetch is cached, a failed one holds off fetching that date again
+// (see ShouldFetchRateOn). Other dates and the latest rate are unaffected.
+func (c *Converter) ApplyRateOn(date time.Time, rate float64, err error) error {
+	key := date.Format(DateFormat)
+	if err != nil {
+		c.failedOn[key] = time.Now()
+		c.lastErr = err
+		return err
 	}
-	return idr / c.cache.CADToIDR
+
+	delete(c.failedOn, key)
+	c.rates.Historical[key] = rate
+	return c.saveCache()
+}
+
+// ShouldFetchRateOn returns false if fetching the historical rate for a date
+// failed less than HistoricalRetryDelay ago, so background fetches don't retry
+// it every time the day is opened
+func (c *Converter) ShouldFetchRateOn(date time.Time) bool {
+	failed, ok := c.failedOn[date.Format(DateFormat)]
+	return !ok || time.Since(failed) >= HistoricalRetryDelay
+}
+
+// isPastDate returns true if the date falls before today
+func isPastDate(date time.Time) bool {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, date.Location())
+	return date.Before(today)
+}
+
+// GetRate returns the current rate (to units per from unit)
+func (c *Converter) GetRate() float64 {
+	if c.fixedRate != 0 {
+		return c.fixedRate
+	}
+	return c.rates.Rate
 }
 
-// IsOffline returns true if the last API call failed
+// IsOffline returns true if the last refresh of the latest rate failed
 func (c *Converter) IsOffline() bool {
 	return c.offline
 }
@@ -147,29 +322,56 @@ func (c *Converter) GetLastError() error {
 
 // GetLastUpdated returns when the rate was last updated
 func (c *Converter) GetLastUpdated() time.Time {
-	return c.cache.LastUpdated
+	return c.rates.LastUpdated
 }
 
 // GetLastUpdatedString returns a human-readable last updated string
 func (c *Converter) GetLastUpdatedString() string {
-	if c.cache.LastUpdated.IsZero() {
+	if c.rates.LastUpdated.IsZero() {
 		return "never (using default rate)"
 	}
-	return c.cache.LastUpdated.Format("Jan 2, 2006 at 3:04 PM")
+	return c.rates.LastUpdated.Format("Jan 2, 2006 at 3:04 PM")
 }
 
 // GetStatusMessage returns a status message about the current rate
 func (c *Converter) GetStatusMessage() string {
+	if c.fixedRate != 0 {
+		return fmt.Sprintf("Rate: %s (fixed)", c.FormatRate())
+	}
+	if c.offlineMode {
+		if c.rates.LastUpdated.IsZero() {
+			return fmt.Sprintf("Offline mode - using default rate (%s)", c.FormatRate())
+		}
+		return fmt.Sprintf("Offline mode - using cached rate from %s", c.rates.LastUpdated.Format("Jan 2"))
+	}
 	if c.offline {
-		if c.cache.LastUpdated.IsZero() {
-			return fmt.Sprintf("⚠ Offline - using default rate (1 CAD = %.0f IDR)", c.cache.CADToIDR)
+		if c.rates.LastUpdated.IsZero() {
+			if c.rates.Rate == 0 {
+				return fmt.Sprintf("⚠ Offline - no %s rate available", pairKey(c.from, c.to))
+			}
+			return fmt.Sprintf("⚠ Offline - using default rate (%s)", c.FormatRate())
+		}
+		return fmt.Sprintf("⚠ Offline - using cached rate from %s", c.rates.LastUpdated.Format("Jan 2"))
+	}
+	if c.rates.LastUpdated.IsZero() {
+		if c.rates.Rate == 0 {
+			return fmt.Sprintf("No %s rate yet", pairKey(c.from, c.to))
 		}
-		return fmt.Sprintf("⚠ Offline - using cached rate from %s", c.cache.LastUpdated.Format("Jan 2"))
+		return fmt.Sprintf("Rate: %s (default)", c.FormatRate())
 	}
-	return fmt.Sprintf("Rate: 1 CAD = %.0f IDR (updated %s)", c.cache.CADToIDR, c.cache.LastUpdated.Format("Jan 2"))
+	return fmt.Sprintf("Rate: %s (updated %s)", c.FormatRate(), c.rates.LastUpdated.Format("Jan 2"))
 }
 
-// FormatRate returns a formatted string of the current rate
+// FormatRate returns a formatted string of the current rate (e.g., "1 CAD = 11800 IDR")
 func (c *Converter) FormatRate() string {
-	return fmt.Sprintf("1 CAD = %.0f IDR", c.cache.CADToIDR)
+	return fmt.Sprintf("1 %s = %s %s", c.from, FormatRateValue(c.GetRate()), c.to)
+}
+
+// FormatRateValue prints large rates as whole numbers and small ones to at most
+// six places (e.g., "11800", "0.7342")
+func FormatRateValue(rate float64) string {
+	if rate >= 100 {
+		return fmt.Sprintf("%.0f", rate)
+	}
+	return strings.TrimRight(strings.TrimRight(strconv.FormatFloat(rate, 'f', 6, 64), "0"), ".")
 }
@@ -0,0 +1,190 @@
+package currency
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// rateServer serves a fixed rate for every date, or fails when down is set,
+// counting the requests it gets
+type rateServer struct {
+	*httptest.Server
+	requests int
+	down     bool
+}
+
+func newRateServer(t *testing.T, rate float64) *rateServer {
+	s := &rateServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests++
+		if s.down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, `{"amount":1.0,"base":"CAD","rates":{"IDR":%v}}`, rate)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestConverterRateOnCachesByDate(t *testing.T) {
+	dir := t.TempDir()
+	server := newRateServer(t, 11512.5)
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+
+	c := NewConverterWithClient(dir, "CAD", "IDR", NewClientWithBaseURL(server.URL))
+	if _, ok := c.CachedRateOn(date); ok {
+		t.Fatal("CachedRateOn found a rate before it was fetched")
+	}
+	for i := 0; i < 2; i++ {
+		rate, err := c.RateOn(date)
+		if err != nil {
+			t.Fatalf("RateOn: %v", err)
+		}
+		if rate != 11512.5 {
+			t.Errorf("RateOn = %v, want 11512.5", rate)
+		}
+	}
+	if server.requests != 1 {
+		t.Errorf("API requests = %d, want 1", server.requests)
+	}
+
+	// Another converter on the same cache directory doesn't fetch it again
+	reloaded := NewConverterWithClient(dir, "CAD", "IDR", NewClientWithBaseURL(server.URL))
+	if rate, ok := reloaded.CachedRateOn(date); !ok || rate != 11512.5 {
+		t.Errorf("CachedRateOn after reload = %v, %v, want 11512.5, true", rate, ok)
+	}
+	if rate, ok := reloaded.CachedRateOn(date.AddDate(0, 0, -1)); ok {
+		t.Errorf("CachedRateOn for another date = %v, want no rate", rate)
+	}
+	if server.requests != 1 {
+		t.Errorf("API requests after reload = %d, want 1", server.requests)
+	}
+}
+
+func TestConverterRateOnFailure(t *testing.T) {
+	server := newRateServer(t, 11512.5)
+	server.down = true
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+
+	c := NewConverterWithClient(t.TempDir(), "CAD", "IDR", NewClientWithBaseURL(server.URL))
+	c.rates.Rate = 11800
+	rate, err := c.RateOn(date)
+	if err == nil {
+		t.Fatalf("RateOn = %v, want an error", rate)
+	}
+	if rate != 0 {
+		t.Errorf("RateOn = %v after a failed fetch, want 0 rather than the latest rate", rate)
+	}
+	// The failure is kept to that date
+	if c.IsOffline() {
+		t.Error("a failed historical fetch marked the converter offline")
+	}
+	if c.ShouldFetchRateOn(date) {
+		t.Error("ShouldFetchRateOn is true right after the date's fetch failed")
+	}
+	if !c.ShouldFetchRateOn(date.AddDate(0, 0, 1)) {
+		t.Error("ShouldFetchRateOn is false for another date")
+	}
+	c.failedOn[date.Format(DateFormat)] = time.Now().Add(-HistoricalRetryDelay)
+	if !c.ShouldFetchRateOn(date) {
+		t.Error("ShouldFetchRateOn is false once the retry delay has passed")
+	}
+	if _, ok := c.CachedRateOn(date); ok {
+		t.Error("a failed fetch cached a rate")
+	}
+
+	// The next attempt asks the API again
+	server.down = false
+	if rate, err := c.RateOn(date); err != nil || rate != 11512.5 {
+		t.Errorf("RateOn after recovery = %v, %v, want 11512.5", rate, err)
+	}
+}
+
+func TestConverterRateOnOfflineMode(t *testing.T) {
+	server := newRateServer(t, 11512.5)
+	c := NewConverterWithClient(t.TempDir(), "CAD", "IDR", NewClientWithBaseURL(server.URL))
+	c.SetOfflineMode(true)
+
+	if rate, err := c.RateOn(time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)); err == nil {
+		t.Errorf("RateOn in offline mode = %v, want an error for an uncached date", rate)
+	}
+	if server.requests != 0 {
+		t.Errorf("API requests in offline mode = %d, want 0", server.requests)
+	}
+}
+
+func TestConverterFetchRateOnLeavesState(t *testing.T) {
+	server := newRateServer(t, 11512.5)
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	c := NewConverterWithClient(t.TempDir(), "CAD", "IDR", NewClientWithBaseURL(server.URL))
+
+	rate, err := c.FetchRateOn(date)
+	if err != nil {
+		t.Fatalf("FetchRateOn: %v", err)
+	}
+	if _, ok := c.CachedRateOn(date); ok {
+		t.Fatal("FetchRateOn cached the rate before ApplyRateOn")
+	}
+	if err := c.ApplyRateOn(date, rate, nil); err != nil {
+		t.Fatalf("ApplyRateOn: %v", err)
+	}
+	if got, ok := c.CachedRateOn(date); !ok || got != 11512.5 {
+		t.Errorf("CachedRateOn = %v, %v, want 11512.5, true", got, ok)
+	}
+}
+
+func TestConverterRefreshRetry(t *testing.T) {
+	dir := t.TempDir()
+	server := newRateServer(t, 11512.5)
+	server.down = true
+	c := NewConverterWithClient(dir, "CAD", "IDR", NewClientWithBaseURL(server.URL))
+
+	// The fetch runs in the background; only applying its result changes the converter
+	rate, err := c.FetchLatest()
+	if c.IsOffline() || c.GetRate() != DefaultRates["CAD/IDR"] {
+		t.Error("FetchLatest changed the converter before ApplyRefresh")
+	}
+	if c.ApplyRefresh(rate, err) == nil {
+		t.Fatal("ApplyRefresh didn't report the failed fetch")
+	}
+	if !c.IsOffline() || c.GetLastError() == nil {
+		t.Error("converter isn't offline after a failed refresh")
+	}
+	if c.GetRate() != DefaultRates["CAD/IDR"] {
+		t.Errorf("rate = %v after a failed refresh, want the default kept", c.GetRate())
+	}
+	past := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	c.ApplyRateOn(past, 0, fmt.Errorf("no rate"))
+
+	// Retrying once the API is back clears the offline warning
+	server.down = false
+	if err := c.RefreshRate(); err != nil {
+		t.Fatalf("RefreshRate: %v", err)
+	}
+	if c.IsOffline() || c.GetLastError() != nil {
+		t.Error("converter is still offline after a successful retry")
+	}
+	if c.GetRate() != 11512.5 {
+		t.Errorf("rate = %v, want 11512.5", c.GetRate())
+	}
+	if !c.ShouldFetchRateOn(past) {
+		t.Error("a date that failed while the API was down isn't fetched again once it's back")
+	}
+	if reloaded := NewConverterWithClient(dir, "CAD", "IDR", NewClientWithBaseURL(server.URL)); reloaded.GetRate() != 11512.5 {
+		t.Errorf("cached rate = %v, want 11512.5", reloaded.GetRate())
+	}
+
+	// Nothing is fetched when the converter never contacts the API
+	requests := server.requests
+	c.SetOfflineMode(true)
+	if c.CanRefresh() {
+		t.Error("CanRefresh in offline mode")
+	}
+	if err := c.RefreshRate(); err != nil || server.requests != requests {
+		t.Errorf("RefreshRate in offline mode = %v with %d requests, want nothing fetched", err, server.requests-requests)
+	}
+}
@@ -0,0 +1,71 @@
+package fsutil
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic writes a file by streaming into a temporary file in the same
+// directory, syncing it to disk and renaming it over path. A crash or a full disk
+// mid-write leaves the previous file intact.
+func WriteFileAtomic(path string, perm os.FileMode, write func(w io.Writer) error) (err error) {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	closed := false
+	defer func() {
+		if err != nil {
+			if !closed {
+				_ = tmp.Close()
+			}
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	if err = write(tmp); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to sync %s: %w", filepath.Base(path), err)
+	}
+	closed = true
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", filepath.Base(path), err)
+	}
+	if err = os.Chmod(tmpPath, perm); err != nil {
+		return fmt.Errorf("failed to set permissions on %s: %w", filepath.Base(path), err)
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", filepath.Base(path), err)
+	}
+
+	syncDir(dir)
+	return nil
+}
+
+// WriteFile atomically writes data to path (see WriteFileAtomic)
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	return WriteFileAtomic(path, perm, func(w io.Writer) error {
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+		}
+		return nil
+	})
+}
+
+// syncDir flushes a directory entry so the rename survives a crash.
+// Not every platform supports syncing directories, so failures are ignored.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	_ = d.Close()
+}
@@ -0,0 +1,66 @@
+package fsutil
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomicReplaces(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.csv")
+	if err := WriteFile(path, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(path, []byte("new"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "new" {
+		t.Errorf("file = %q, %v, want %q", data, err, "new")
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0644 {
+		t.Errorf("mode = %v, %v, want 0644", info.Mode().Perm(), err)
+	}
+	assertOnlyFile(t, path)
+}
+
+func TestWriteFileAtomicFailedWriteKeepsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.csv")
+	if err := WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A write failing halfway through, as on a full disk
+	full := errors.New("no space left on device")
+	err := WriteFileAtomic(path, 0644, func(w io.Writer) error {
+		fmt.Fprint(w, "half")
+		return full
+	})
+	if !errors.Is(err, full) {
+		t.Errorf("WriteFileAtomic = %v, want %v", err, full)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "old" {
+		t.Errorf("file = %q, %v after a failed write, want %q", data, err, "old")
+	}
+	assertOnlyFile(t, path)
+}
+
+// assertOnlyFile fails if anything but path, like a leftover temp file, is in its directory
+func assertOnlyFile(t *testing.T, path string) {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.Name() != filepath.Base(path) {
+			t.Errorf("%s left next to %s", e.Name(), filepath.Base(path))
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AdjustmentCategory is the category of entries that bring an account in line
+// with what is actually in it
+const AdjustmentCategory = "adjustment"
+
+// Account is somewhere money is paid from: a cash wallet, a card or a bank account
+type Account struct {
+	Name     string
+	Currency Currency // The account's balance is kept in this currency
+	Opening  Money    // Balance before the first entry paid from the account
+}
+
+// FindAccount returns the account with a name, ignoring case
+func FindAccount(accounts []Account, name string) (Account, bool) {
+	name = strings.TrimSpace(name)
+	for _, a := range accounts {
+		if strings.EqualFold(a.Name, name) {
+			return a, true
+		}
+	}
+	return Account{}, false
+}
+
+// Transfer is money moved from the entry's account to another account, such as
+// an ATM withdrawal from a card into the cash wallet. It isn't spending, so the
+// entry's own amount is zero and the amount moved is kept here in both
+// currencies (positive).
+type Transfer struct {
+	To    string `json:"to"`
+	Home  Money  `json:"home"`
+	Local Money  `json:"local"`
+}
+
+// Clone returns a copy of the transfer (nil stays nil)
+func (t *Transfer) Clone() *Transfer {
+	if t == nil {
+		return nil
+	}
+	clone := *t
+	return &clone
+}
+
+// ParseAccountInput reads the account prompt: an account name ("wise"), or two
+// joined by ">" for a transfer from the first to the second ("wise > cash").
+// Both must be among the accounts; empty input clears the account.
+func ParseAccountInput(input string, accounts []Account) (string, string, error) {
+	fromPart, toPart, isTransfer := strings.Cut(input, ">")
+	fromPart = strings.TrimSpace(fromPart)
+	if !isTransfer && fromPart == "" {
+		return "", "", nil
+	}
+
+	from, err := lookupAccount(accounts, fromPart)
+	if err != nil {
+		return "", "", err
+	}
+	if !isTransfer {
+		return from, "", nil
+	}
+	to, err := lookupAccount(accounts, strings.TrimSpace(toPart))
+	if err != nil {
+		return "", "", err
+	}
+	if from == to {
+		return "", "", errors.New("can't transfer to the same account")
+	}
+	return from, to, nil
+}
+
+// lookupAccount returns the configured spelling of an account name
+func lookupAccount(accounts []Account, name string) (string, error) {
+	if name == "" {
+		return "", errors.New("account name is missing")
+	}
+	account, ok := FindAccount(accounts, name)
+	if !ok {
+		names := make([]string, len(accounts))
+		for i, a := range accounts {
+			names[i] = a.Name
+		}
+		return "", fmt.Errorf("unknown account %q (have %s)", name, strings.Join(names, ", "))
+	}
+	return account.Name, nil
+}
+
+// IsTransfer returns true if the entry moves money between accounts
+func (e *Entry) IsTransfer() bool {
+	return e.Transfer != nil
+}
+
+// MoveAmountToTransfer makes the entry's amount the amount transferred, leaving
+// the entry's own amount at zero
+func (e *Entry) MoveAmountToTransfer() {
+	if e.Transfer == nil {
+		return
+	}
+	e.Transfer.Home, e.Transfer.Local = e.Home.Abs(), e.Local.Abs()
+	e.Home, e.Local = 0, 0
+}
+
+// SetAccount sets the account the entry was paid from and, for a transfer, the
+// account the money went to ("" for none). Making an entry a transfer moves its
+// amount into the transfer; it moves back as money out when it stops being one.
+func (e *Entry) SetAccount(from, to string) error {
+	if to != "" && e.IsShared() {
+		return errors.New("a shared entry can't be a transfer")
+	}
+	if to != "" && from == "" {
+		return errors.New("a transfer needs an account to come from")
+	}
+
+	switch {
+	case to == "" && e.Transfer != nil:
+		e.Home, e.Local = -e.Transfer.Home, -e.Transfer.Local
+		e.Transfer = nil
+	case to != "" && e.Transfer == nil:
+		e.Transfer = &Transfer{To: to}
+		e.MoveAmountToTransfer()
+	case to != "":
+		e.Transfer.To = to
+	}
+	e.Account = from
+	return nil
+}
+
+// FormatAccount describes the entry's account as typed in the account prompt
+// ("wise" or "wise > cash"), or "" if it has none
+func (e *Entry) FormatAccount() string {
+	if e.Transfer != nil {
+		return e.Account + " > " + e.Transfer.To
+	}
+	return e.Account
+}
+
+// AccountBalance is an account's balance after a run of entries, in the account's currency
+type AccountBalance struct {
+	Account Account
+	Balance Money
+}
+
+// AccountBalances returns each account's opening balance plus the entries paid
+// from it and the transfers in and out of it. Entries without an account, or
+// with one that isn't configured, don't count toward any balance.
+func AccountBalances(accounts []Account, entries []*Entry, currencies Pair) []AccountBalance {
+	balances := make([]AccountBalance, len(accounts))
+	index := make(map[string]int, len(accounts))
+	for i, a := range accounts {
+		balances[i] = AccountBalance{Account: a, Balance: a.Opening}
+		index[strings.ToLower(a.Name)] = i
+	}
+
+	add := func(name string, home, local Money) {
+		i, ok := index[strings.ToLower(name)]
+		if !ok {
+			return
+		}
+		if balances[i].Account.Currency.Code == currencies.Home.Code {
+			balances[i].Balance += home
+		} else {
+			balances[i].Balance += local
+		}
+	}
+	for _, e := range entries {
+		if e.Account == "" {
+			continue
+		}
+		add(e.Account, e.Home, e.Local)
+		if t := e.Transfer; t != nil {
+			add(e.Account, -t.Home, -t.Local)
+			add(t.To, t.Home, t.Local)
+		}
+	}
+	return balances
+}
+
+// NewAdjustment creates the entry that brings an account's balance to the amount
+// actually in it, such as the cash counted in the wallet. The difference is
+// money that left (or turned up in) the account without an entry.
+// Rate is the number of local units per home unit.
+func NewAdjustment(date time.Time, balance AccountBalance, actual Money, currencies Pair, rate float64) *Entry {
+	entry := NewEntry(date, "Reconcile "+balance.Account.Name, 0, 0, "")
+	entry.Category = AdjustmentCategory
+	entry.Account = balance.Account.Name
+	entry.SetAmount(actual-balance.Balance, balance.Account.Currency, currencies, rate)
+	return entry
+}
@@ -0,0 +1,121 @@
+package ledger
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAccountBalances(t *testing.T) {
+	date := time.Date(2026, 10, 14, 0, 0, 0, 0, time.Local)
+	accounts := []Account{
+		{Name: "wise", Currency: CAD, Opening: 100000},
+		{Name: "cash", Currency: IDR, Opening: 50000},
+		{Name: "card", Currency: CAD},
+	}
+
+	coffee := NewEntry(date, "Coffee", -300, -30000, "")
+	coffee.Account = "cash"
+	salary := NewEntry(date, "Salary", 200000, 2000000000, "")
+	salary.Account = "WISE" // Names match regardless of case
+	unknown := NewEntry(date, "Book", -2000, -200000, "")
+	unknown.Account = "paypal"
+	entries := []*Entry{
+		coffee,
+		withdrawal(t, date, 10500, 1000000),
+		salary,
+		unknown,
+		NewEntry(date, "Lunch", -500, -50000, ""),
+	}
+
+	got := AccountBalances(accounts, entries, DefaultPair)
+	want := []AccountBalance{
+		{Account: accounts[0], Balance: 100000 - 10500 + 200000},
+		{Account: accounts[1], Balance: 50000 - 30000 + 1000000},
+		{Account: accounts[2], Balance: 0},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("AccountBalances = %v, want %v", got, want)
+	}
+}
+
+func TestNewAdjustment(t *testing.T) {
+	date := time.Date(2026, 10, 14, 0, 0, 0, 0, time.Local)
+	cash := AccountBalance{Account: Account{Name: "cash", Currency: IDR}, Balance: 1020000}
+	wise := AccountBalance{Account: Account{Name: "wise", Currency: CAD}, Balance: 289500}
+
+	tests := []struct {
+		name        string
+		balance     AccountBalance
+		actual      Money
+		home, local Money
+	}{
+		{"cash missing from the wallet", cash, 1000000, -200, -20000},
+		{"cash turned up", cash, 1030000, 100, 10000},
+		{"home-currency account", wise, 290000, 500, 50000},
+	}
+	for _, tt := range tests {
+		entry := NewAdjustment(date, tt.balance, tt.actual, DefaultPair, 10000)
+		if entry.Home != tt.home || entry.Local != tt.local {
+			t.Errorf("%s: amounts = %d, %d, want %d, %d", tt.name, entry.Home, entry.Local, tt.home, tt.local)
+		}
+		if entry.Source != tt.balance.Account.Currency.Code {
+			t.Errorf("%s: Source = %q, want %q", tt.name, entry.Source, tt.balance.Account.Currency.Code)
+		}
+		if entry.Account != tt.balance.Account.Name || entry.Category != AdjustmentCategory {
+			t.Errorf("%s: account %q in category %q, want %q in %q", tt.name, entry.Account, entry.Category, tt.balance.Account.Name, AdjustmentCategory)
+		}
+
+		// The adjustment brings the balance to what was counted
+		balances := AccountBalances([]Account{tt.balance.Account}, []*Entry{entry}, DefaultPair)
+		if balances[0].Balance+tt.balance.Balance != tt.actual {
+			t.Errorf("%s: balance after adjusting = %d, want %d", tt.name, balances[0].Balance+tt.balance.Balance, tt.actual)
+		}
+	}
+}
+
+func TestEntrySetAccountTransfer(t *testing.T) {
+	date := time.Date(2026, 10, 14, 0, 0, 0, 0, time.Local)
+	entry := NewEntry(date, "ATM", -10500, -1000000, "")
+
+	// Becoming a transfer moves the amount into it
+	if err := entry.SetAccount("wise", "cash"); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Transfer == nil || *entry.Transfer != (Transfer{To: "cash", Home: 10500, Local: 1000000}) {
+		t.Errorf("Transfer = %+v, want 10500, 1000000 to cash", entry.Transfer)
+	}
+	if entry.Home != 0 || entry.Local != 0 || entry.Account != "wise" {
+		t.Errorf("transfer = %d, %d from %q, want 0, 0 from wise", entry.Home, entry.Local, entry.Account)
+	}
+	if got := entry.FormatAccount(); got != "wise > cash" {
+		t.Errorf("FormatAccount = %q, want %q", got, "wise > cash")
+	}
+
+	// Another destination keeps the amount
+	if err := entry.SetAccount("wise", "card"); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Transfer.To != "card" || entry.Transfer.Home != 10500 {
+		t.Errorf("Transfer = %+v, want 10500 to card", entry.Transfer)
+	}
+
+	// Stopping being a transfer moves it back as money out
+	if err := entry.SetAccount("wise", ""); err != nil {
+		t.Fatal(err)
+	}
+	if entry.IsTransfer() || entry.Home != -10500 || entry.Local != -1000000 {
+		t.Errorf("entry = %d, %d, transfer %v, want -10500, -1000000 and no transfer", entry.Home, entry.Local, entry.IsTransfer())
+	}
+
+	if err := entry.SetAccount("", "cash"); err == nil {
+		t.Error("SetAccount made a transfer without an account to come from")
+	}
+	shared := NewEntry(date, "Dinner", -1000, -100000, "")
+	if err := shared.SetSplit("", &Split{Method: SplitEqual, People: []SplitPerson{{Name: "me"}, {Name: "alice"}}}, DefaultPair); err != nil {
+		t.Fatal(err)
+	}
+	if err := shared.SetAccount("wise", "cash"); err == nil {
+		t.Error("SetAccount made a shared entry a transfer")
+	}
+}
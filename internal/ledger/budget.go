@@ -0,0 +1,160 @@
+package ledger
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BudgetPeriod is the length of time a budget's limit applies to
+type BudgetPeriod string
+
+const (
+	BudgetDay   BudgetPeriod = "day"
+	BudgetWeek  BudgetPeriod = "week" // Monday to Sunday
+	BudgetMonth BudgetPeriod = "month"
+)
+
+// ParseBudgetPeriod reads a period name such as "day", "weekly" or "Month"
+func ParseBudgetPeriod(s string) (BudgetPeriod, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "day", "daily":
+		return BudgetDay, nil
+	case "week", "weekly":
+		return BudgetWeek, nil
+	case "month", "monthly":
+		return BudgetMonth, nil
+	}
+	return "", fmt.Errorf("unknown budget period %q (want day, week or month)", s)
+}
+
+// Bounds returns the first and last day of the period containing a date
+func (p BudgetPeriod) Bounds(date time.Time) (time.Time, time.Time) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	switch p {
+	case BudgetWeek:
+		start := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 6)
+	case BudgetMonth:
+		start := day.AddDate(0, 0, 1-day.Day())
+		return start, start.AddDate(0, 1, -1)
+	}
+	return day, day
+}
+
+// Label returns the period for display (e.g., "Day")
+func (p BudgetPeriod) Label() string {
+	switch p {
+	case BudgetWeek:
+		return "Week"
+	case BudgetMonth:
+		return "Month"
+	}
+	return "Day"
+}
+
+// FormatRange describes one period of this length (e.g., "10/12/2026", "Week of 10/12/2026", "October 2026")
+func (p BudgetPeriod) FormatRange(start time.Time) string {
+	switch p {
+	case BudgetWeek:
+		return "Week of " + start.Format("01/02/2006")
+	case BudgetMonth:
+		return start.Format("January 2006")
+	}
+	return start.Format("01/02/2006")
+}
+
+// Budget is a spending limit per period, optionally for a single category
+type Budget struct {
+	Period   BudgetPeriod
+	Category string   // Only entries in this category count ("" for all spending)
+	Limit    Money    // Maximum spending per period, in Currency
+	Currency Currency // One of the ledger's currencies
+}
+
+// Name returns the budget for display (e.g., "Month" or "Month food")
+func (b Budget) Name() string {
+	if b.Category == "" {
+		return b.Period.Label()
+	}
+	return b.Period.Label() + " " + b.Category
+}
+
+// Counts returns true if an entry is spending that falls under the budget.
+// Transfers, settlements and adjustments move money without spending it.
+func (b Budget) Counts(entry *Entry) bool {
+	if entry.IsIncome() || entry.IsBookkeeping() {
+		return false
+	}
+	return b.Category == "" || strings.EqualFold(entry.CategoryOrDefault(), b.Category)
+}
+
+// BudgetStatus is the spending against a budget over one period, in both currencies.
+// The limit in the budget's own currency is exact; the other is converted at a rate.
+type BudgetStatus struct {
+	Budget     Budget
+	Start      time.Time
+	End        time.Time
+	LimitHome  Money
+	LimitLocal Money
+	SpentHome  Money // Spending as a positive amount
+	SpentLocal Money
+
+	inHome bool // The budget is set in the home currency
+}
+
+// NewBudgetStatus sums the spending on the given days that counts toward the budget.
+// Rate is the number of local units per home unit, used to express the limit in
+// the currency it wasn't set in.
+func NewBudgetStatus(budget Budget, start, end time.Time, days []*Day, currencies Pair, rate float64) BudgetStatus {
+	status := BudgetStatus{
+		Budget: budget,
+		Start:  start,
+		End:    end,
+		inHome: budget.Currency.Code == currencies.Home.Code,
+	}
+	if status.inHome {
+		status.LimitHome = budget.Limit
+		status.LimitLocal = budget.Limit.MulRate(currencies.Home, currencies.Local, rate)
+	} else {
+		status.LimitLocal = budget.Limit
+		status.LimitHome = budget.Limit.DivRate(currencies.Local, currencies.Home, rate)
+	}
+
+	first, last := start.Format(DateFormat), end.Format(DateFormat)
+	for _, day := range days {
+		if key := day.Date.Format(DateFormat); key < first || key > last {
+			continue
+		}
+		for _, entry := range day.Entries {
+			if budget.Counts(entry) {
+				status.SpentHome -= entry.Home
+				status.SpentLocal -= entry.Local
+			}
+		}
+	}
+	return status
+}
+
+// RemainingHome returns the limit minus spending in the home currency (negative when over)
+func (s BudgetStatus) RemainingHome() Money {
+	return s.LimitHome - s.SpentHome
+}
+
+// RemainingLocal returns the limit minus spending in the local currency (negative when over)
+func (s BudgetStatus) RemainingLocal() Money {
+	return s.LimitLocal - s.SpentLocal
+}
+
+// Over returns true if spending exceeds the limit, compared in the budget's own currency
+func (s BudgetStatus) Over() bool {
+	if s.inHome {
+		return s.SpentHome > s.LimitHome
+	}
+	return s.SpentLocal > s.LimitLocal
+}
+
+// FormatPeriod describes the status's period (e.g., "October 2026")
+func (s BudgetStatus) FormatPeriod() string {
+	return s.Budget.Period.FormatRange(s.Start)
+}
@@ -0,0 +1,115 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBudgetCounts(t *testing.T) {
+	date := time.Date(2026, 10, 14, 0, 0, 0, 0, time.Local)
+	food := func() *Entry {
+		entry := NewEntry(date, "Lunch", -500, -50000, "")
+		entry.SetCategoryInput("food #work")
+		return entry
+	}
+
+	income := NewEntry(date, "Salary", 100000, 1000000000, "")
+	settlement := NewEntry(date, "Settle up with Ann", -500, -50000, "")
+	settlement.Category = SettlementCategory
+	adjustment := NewEntry(date, "Reconcile cash", -500, -50000, "")
+	adjustment.Category = AdjustmentCategory
+	transfer := NewEntry(date, "ATM", -500, -50000, "")
+	if err := transfer.SetAccount("wise", "cash"); err != nil {
+		t.Fatal(err)
+	}
+
+	all := Budget{Period: BudgetMonth}
+	foodOnly := Budget{Period: BudgetMonth, Category: "Food"}
+	tests := []struct {
+		name   string
+		budget Budget
+		entry  *Entry
+		want   bool
+	}{
+		{"spending", all, food(), true},
+		{"category matches case-insensitively", foodOnly, food(), true},
+		{"other category", foodOnly, NewEntry(date, "Taxi", -500, -50000, ""), false},
+		{"income", all, income, false},
+		{"settlement", all, settlement, false},
+		{"adjustment", all, adjustment, false},
+		{"transfer", all, transfer, false},
+	}
+	for _, tt := range tests {
+		if got := tt.budget.Counts(tt.entry); got != tt.want {
+			t.Errorf("%s: Counts = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBudgetPeriodBounds(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	}
+	tests := []struct {
+		name       string
+		period     BudgetPeriod
+		date       time.Time
+		start, end time.Time
+	}{
+		{"day drops the time", BudgetDay, time.Date(2026, 10, 14, 18, 30, 0, 0, time.Local), day(2026, 10, 14), day(2026, 10, 14)},
+		{"week from Wednesday", BudgetWeek, day(2026, 10, 14), day(2026, 10, 12), day(2026, 10, 18)},
+		{"week from Monday", BudgetWeek, day(2026, 10, 12), day(2026, 10, 12), day(2026, 10, 18)},
+		{"week from Sunday", BudgetWeek, day(2026, 10, 18), day(2026, 10, 12), day(2026, 10, 18)},
+		{"week across months", BudgetWeek, day(2026, 10, 1), day(2026, 9, 28), day(2026, 10, 4)},
+		{"week across years", BudgetWeek, day(2026, 1, 1), day(2025, 12, 29), day(2026, 1, 4)},
+		{"month from the last day", BudgetMonth, day(2026, 10, 31), day(2026, 10, 1), day(2026, 10, 31)},
+		{"month from the first day", BudgetMonth, day(2026, 10, 1), day(2026, 10, 1), day(2026, 10, 31)},
+		{"leap February", BudgetMonth, day(2024, 2, 10), day(2024, 2, 1), day(2024, 2, 29)},
+		{"December", BudgetMonth, day(2026, 12, 31), day(2026, 12, 1), day(2026, 12, 31)},
+	}
+	for _, tt := range tests {
+		start, end := tt.period.Bounds(tt.date)
+		if !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("%s: Bounds = %s to %s, want %s to %s", tt.name,
+				start.Format(DateFormat), end.Format(DateFormat), tt.start.Format(DateFormat), tt.end.Format(DateFormat))
+		}
+	}
+}
+
+func TestNewBudgetStatus(t *testing.T) {
+	start := time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, 0, 6)
+
+	inWeek := NewDay(start.AddDate(0, 0, 2))
+	inWeek.AddEntry(NewEntry(inWeek.Date, "Lunch", -500, -50000, ""))
+	inWeek.AddEntry(NewEntry(inWeek.Date, "Refund", 200, 20000, ""))
+	settlement := NewEntry(inWeek.Date, "Settle up with Ann", -1000, -100000, "")
+	settlement.Category = SettlementCategory
+	inWeek.AddEntry(settlement)
+	lastDay := NewDay(end)
+	lastDay.AddEntry(NewEntry(end, "Dinner", -1500, -150000, ""))
+	after := NewDay(end.AddDate(0, 0, 1))
+	after.AddEntry(NewEntry(after.Date, "Taxi", -900, -90000, ""))
+	days := []*Day{inWeek, lastDay, after}
+
+	// A limit in the home currency is exact there and converted to local
+	status := NewBudgetStatus(Budget{Period: BudgetWeek, Limit: 2500, Currency: CAD}, start, end, days, DefaultPair, 10000)
+	if status.SpentHome != 2000 || status.SpentLocal != 200000 {
+		t.Errorf("spent = %d, %d, want 2000, 200000", status.SpentHome, status.SpentLocal)
+	}
+	if status.LimitHome != 2500 || status.LimitLocal != 250000 {
+		t.Errorf("limit = %d, %d, want 2500, 250000", status.LimitHome, status.LimitLocal)
+	}
+	if status.RemainingHome() != 500 || status.Over() {
+		t.Errorf("remaining = %d, over = %v, want 500 and not over", status.RemainingHome(), status.Over())
+	}
+
+	// A limit in the local currency is compared there
+	status = NewBudgetStatus(Budget{Period: BudgetWeek, Limit: 150000, Currency: IDR}, start, end, days, DefaultPair, 10000)
+	if status.LimitHome != 1500 || status.LimitLocal != 150000 {
+		t.Errorf("limit = %d, %d, want 1500, 150000", status.LimitHome, status.LimitLocal)
+	}
+	if !status.Over() || status.RemainingLocal() != -50000 {
+		t.Errorf("remaining = %d, over = %v, want -50000 and over", status.RemainingLocal(), status.Over())
+	}
+}
@@ -0,0 +1,140 @@
+package ledger
+
+import (
+	"sort"
+	"strings"
+)
+
+// Uncategorized is the label used for entries without a category
+const Uncategorized = "uncategorized"
+
+// CategoryTotal holds the subtotals for one category
+type CategoryTotal struct {
+	Category string
+	Home     Money
+	Local    Money
+	Count    int
+}
+
+// ParseCategoryInput splits user input like "food #dinner #friends" into a
+// category ("food") and its tags ("dinner", "friends").
+// Categories and tags are lowercased so that totals group consistently.
+func ParseCategoryInput(input string) (string, []string) {
+	var words []string
+	var tags []string
+	seen := make(map[string]bool)
+
+	for _, field := range strings.Fields(input) {
+		if strings.HasPrefix(field, "#") {
+			tag := strings.ToLower(strings.TrimLeft(field, "#"))
+			if tag != "" && !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+			continue
+		}
+		words = append(words, strings.ToLower(field))
+	}
+
+	return strings.Join(words, " "), tags
+}
+
+// ParseTags parses the space-separated tag list stored in CSV
+func ParseTags(s string) []string {
+	_, tags := ParseCategoryInput(strings.Join(prefixTags(strings.Fields(s)), " "))
+	return tags
+}
+
+// FormatTags formats tags as a space-separated list for CSV storage
+func FormatTags(tags []string) string {
+	return strings.Join(tags, " ")
+}
+
+// prefixTags makes sure every word starts with '#'
+func prefixTags(words []string) []string {
+	out := make([]string, len(words))
+	for i, w := range words {
+		if !strings.HasPrefix(w, "#") {
+			w = "#" + w
+		}
+		out[i] = w
+	}
+	return out
+}
+
+// CategoryLabel returns the category and tags as the user would type them (e.g., "food #dinner")
+func (e *Entry) CategoryLabel() string {
+	parts := make([]string, 0, len(e.Tags)+1)
+	if e.Category != "" {
+		parts = append(parts, e.Category)
+	}
+	parts = append(parts, prefixTags(e.Tags)...)
+	return strings.Join(parts, " ")
+}
+
+// SetCategoryInput sets the category and tags from user input
+func (e *Entry) SetCategoryInput(input string) {
+	e.Category, e.Tags = ParseCategoryInput(input)
+}
+
+// HasTag returns true if the entry is tagged with the given tag
+func (e *Entry) HasTag(tag string) bool {
+	tag = strings.ToLower(strings.TrimLeft(tag, "#"))
+	for _, t := range e.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// CategoryOrDefault returns the entry's category, or Uncategorized if it has none
+func (e *Entry) CategoryOrDefault() string {
+	if e.Category == "" {
+		return Uncategorized
+	}
+	return e.Category
+}
+
+// CategoryTotals returns per-category subtotals for filtered entries across all days,
+// largest home currency spend first. Bookkeeping is left out like in the totals.
+func (dr *DateRange) CategoryTotals(query string) []CategoryTotal {
+	byCategory := make(map[string]*CategoryTotal)
+	for _, entry := range dr.Counted(query) {
+		name := entry.CategoryOrDefault()
+		total, ok := byCategory[name]
+		if !ok {
+			total = &CategoryTotal{Category: name}
+			byCategory[name] = total
+		}
+		total.Home += entry.Home
+		total.Local += entry.Local
+		total.Count++
+	}
+
+	totals := make([]CategoryTotal, 0, len(byCategory))
+	for _, total := range byCategory {
+		totals = append(totals, *total)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Home.Abs() != totals[j].Home.Abs() {
+			return totals[i].Home.Abs() > totals[j].Home.Abs()
+		}
+		return totals[i].Category < totals[j].Category
+	})
+	return totals
+}
+
+// Categories returns the distinct categories used in the range, sorted by name
+func (dr *DateRange) Categories() []string {
+	seen := make(map[string]bool)
+	var categories []string
+	for _, entry := range dr.AllEntries("") {
+		if entry.Category != "" && !seen[entry.Category] {
+			seen[entry.Category] = true
+			categories = append(categories, entry.Category)
+		}
+	}
+	sort.Strings(categories)
+	return categories
+}
@@ -0,0 +1,72 @@
+package ledger
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseCategoryInput(t *testing.T) {
+	tests := []struct {
+		in       string
+		category string
+		tags     []string
+	}{
+		{"", "", nil},
+		{"food", "food", nil},
+		{"Food #Dinner #friends", "food", []string{"dinner", "friends"}},
+		{"#work lunch", "lunch", []string{"work"}},
+		{"eating out #a #A ##a", "eating out", []string{"a"}},
+		{"  food   #  #dinner ", "food", []string{"dinner"}},
+		{"#only #tags", "", []string{"only", "tags"}},
+	}
+	for _, tt := range tests {
+		category, tags := ParseCategoryInput(tt.in)
+		if category != tt.category || !slices.Equal(tags, tt.tags) {
+			t.Errorf("ParseCategoryInput(%q) = %q, %q, want %q, %q", tt.in, category, tags, tt.category, tt.tags)
+		}
+	}
+
+	entry := NewEntry(time.Now(), "Dinner", -800, -80000, "")
+	entry.SetCategoryInput("Food #Dinner #friends")
+	if got := entry.CategoryLabel(); got != "food #dinner #friends" {
+		t.Errorf("CategoryLabel = %q, want %q", got, "food #dinner #friends")
+	}
+}
+
+func TestCategoryTotals(t *testing.T) {
+	date := time.Date(2026, 10, 14, 0, 0, 0, 0, time.Local)
+	entry := func(description, category string, home, local Money) *Entry {
+		e := NewEntry(date, description, home, local, "")
+		e.SetCategoryInput(category)
+		return e
+	}
+
+	day := NewDay(date)
+	day.AddEntry(entry("Lunch", "food #work", -500, -50000))
+	day.AddEntry(entry("Dinner", "Food", -800, -80000))
+	day.AddEntry(entry("Taxi", "transport", -300, -30000))
+	day.AddEntry(entry("Refund", "transport", 100, 10000))
+	day.AddEntry(entry("Gum", "", -300, -30000))
+	next := NewDay(date.AddDate(0, 0, 1))
+	next.AddEntry(entry("Breakfast", "food", -200, -20000))
+	dateRange := NewDateRange(date, next.Date)
+	dateRange.AddDay(day)
+	dateRange.AddDay(next)
+
+	// Largest home amount first; a refund nets out against its category
+	want := []CategoryTotal{
+		{Category: "food", Home: -1500, Local: -150000, Count: 3},
+		{Category: Uncategorized, Home: -300, Local: -30000, Count: 1},
+		{Category: "transport", Home: -200, Local: -20000, Count: 2},
+	}
+	if got := dateRange.CategoryTotals(""); !slices.Equal(got, want) {
+		t.Errorf("CategoryTotals = %v, want %v", got, want)
+	}
+
+	// A search only sums the matching entries
+	want = []CategoryTotal{{Category: "food", Home: -500, Local: -50000, Count: 1}}
+	if got := dateRange.CategoryTotals("#work"); !slices.Equal(got, want) {
+		t.Errorf("CategoryTotals(#work) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,189 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ChargeProfile is the service charge and tax a restaurant adds on top of menu
+// prices, in percent
+type ChargeProfile struct {
+	Service float64
+	Tax     float64 // Charged on the menu prices plus the service charge
+}
+
+// DefaultChargeProfile is the usual Bali restaurant bill: 10% service and 11% PB1 tax
+var DefaultChargeProfile = ChargeProfile{Service: 10, Tax: 11}
+
+// Charges is the breakdown of a bill: the menu prices, the percentages added on
+// top of them and a tip. Amounts are cash flow in the currency the entry was
+// entered in, so they carry the same sign as the entry.
+type Charges struct {
+	Base    Money   `json:"base"`              // Menu prices
+	Service float64 `json:"service,omitempty"` // Service charge in percent of the base
+	Tax     float64 `json:"tax,omitempty"`     // Tax in percent of the base plus the service charge
+	Tip     Money   `json:"tip,omitempty"`
+}
+
+// NewCharges creates the breakdown for a base amount with a profile's percentages
+func NewCharges(base Money, profile ChargeProfile) *Charges {
+	return &Charges{Base: base, Service: profile.Service, Tax: profile.Tax}
+}
+
+// Clone returns a copy of the charges (nil stays nil)
+func (c *Charges) Clone() *Charges {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	return &clone
+}
+
+// HasPercentages returns true if a service charge or tax is applied
+func (c *Charges) HasPercentages() bool {
+	return c.Service != 0 || c.Tax != 0
+}
+
+// ServiceAmount returns the service charge, rounded to the minor unit
+func (c *Charges) ServiceAmount() Money {
+	return percentOf(c.Base, c.Service)
+}
+
+// TaxAmount returns the tax on the base plus the service charge, rounded to the minor unit
+func (c *Charges) TaxAmount() Money {
+	return percentOf(c.Base+c.ServiceAmount(), c.Tax)
+}
+
+// Total returns what was paid: base, service charge, tax and tip
+func (c *Charges) Total() Money {
+	return c.Base + c.ServiceAmount() + c.TaxAmount() + c.Tip
+}
+
+// Percentages describes the applied percentages (e.g., "+10% +11%")
+func (c *Charges) Percentages() string {
+	var parts []string
+	for _, pct := range []float64{c.Service, c.Tax} {
+		if pct != 0 {
+			parts = append(parts, "+"+formatPercent(pct)+"%")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// percentOf returns pct percent of an amount, rounded half away from zero
+func percentOf(m Money, pct float64) Money {
+	if pct == 0 {
+		return 0
+	}
+	r := new(big.Rat).Mul(new(big.Rat).SetInt64(int64(m)), rateRat(pct))
+	r.Quo(r, big.NewRat(100, 1))
+	return Money(roundRat(r))
+}
+
+// formatPercent formats a percentage without trailing zeros (e.g., "10" or "2.5")
+func formatPercent(pct float64) string {
+	return strconv.FormatFloat(pct, 'f', -1, 64)
+}
+
+// ParseChargedAmount reads an amount like ParseAmount, with two additions for
+// restaurant bills: a trailing "++" on the menu prices adds the profile's service
+// charge and tax, and "tip" followed by an amount adds a tip ("150k++",
+// "2 x 45k++ tip 20k", "-80k tip 10k idr"). The tip takes the sign of the base.
+// A currency code at the end applies to both unless the base names its own, and
+// the tip must be in the base's currency. Charges is nil when the amount has neither.
+func ParseChargedAmount(input string, cur Currency, currencies Pair, profile ChargeProfile) (Money, Currency, *Charges, error) {
+	s := strings.ToLower(strings.TrimSpace(input))
+
+	trailing := ""
+	if fields := strings.Fields(s); len(fields) > 1 {
+		if c, ok := currencies.Currency(fields[len(fields)-1]); ok {
+			cur = c
+			trailing = fields[len(fields)-1]
+			s = strings.Join(fields[:len(fields)-1], " ")
+		}
+	}
+	named := cur
+
+	basePart, tipPart, hasTip := cutWord(s, "tip")
+	applyProfile := strings.HasSuffix(basePart, "++")
+	basePart = strings.TrimSpace(strings.TrimSuffix(basePart, "++"))
+
+	base, cur, err := ParseAmount(basePart, cur, currencies)
+	if err != nil {
+		return 0, cur, nil, err
+	}
+	if !applyProfile && !hasTip {
+		return base, cur, nil, nil
+	}
+
+	charges := &Charges{Base: base}
+	if applyProfile {
+		charges.Service, charges.Tax = profile.Service, profile.Tax
+	}
+	if hasTip {
+		// A base in its own currency leaves the code at the end to the tip
+		if trailing != "" && cur.Code != named.Code {
+			tipPart += " " + trailing
+		}
+		tip, tipCur, err := ParseAmount(tipPart, cur, currencies)
+		if err != nil {
+			return 0, cur, nil, fmt.Errorf("tip: %w", err)
+		}
+		if tipCur.Code != cur.Code {
+			return 0, cur, nil, errors.New("tip must be in the same currency as the amount")
+		}
+		tip = tip.Abs()
+		if base < 0 {
+			tip = -tip
+		}
+		charges.Tip = tip
+	}
+	return charges.Total(), cur, charges, nil
+}
+
+// cutWord slices s around the first whitespace-delimited occurrence of word,
+// returning the words before and after it
+func cutWord(s, word string) (string, string, bool) {
+	fields := strings.Fields(s)
+	for i, f := range fields {
+		if f == word {
+			return strings.Join(fields[:i], " "), strings.Join(fields[i+1:], " "), true
+		}
+	}
+	return s, "", false
+}
+
+// ChargeTotals sums the breakdown of entries with charges, in the local currency
+type ChargeTotals struct {
+	Entries int // Number of entries with charges
+	Base    Money
+	Service Money
+	Tax     Money
+	Tip     Money
+}
+
+// SumCharges adds up the charges of the entries that have them. Charges entered
+// in the home currency are converted at the entry's rate.
+func SumCharges(entries []*Entry, currencies Pair) ChargeTotals {
+	var totals ChargeTotals
+	for _, e := range entries {
+		c := e.Charges
+		if c == nil {
+			continue
+		}
+		toLocal := func(m Money) Money { return m }
+		if e.Source == currencies.Home.Code {
+			rate := e.Rate
+			toLocal = func(m Money) Money { return m.MulRate(currencies.Home, currencies.Local, rate) }
+		}
+		totals.Entries++
+		totals.Base += toLocal(c.Base)
+		totals.Service += toLocal(c.ServiceAmount())
+		totals.Tax += toLocal(c.TaxAmount())
+		totals.Tip += toLocal(c.Tip)
+	}
+	return totals
+}
@@ -0,0 +1,95 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseChargedAmount(t *testing.T) {
+	tests := []struct {
+		in      string
+		cur     Currency
+		want    Money
+		wantCur Currency
+		charges *Charges
+	}{
+		{"45k", IDR, 45000, IDR, nil},
+		{"150k++", IDR, 183150, IDR, &Charges{Base: 150000, Service: 10, Tax: 11}},
+		{"2 x 45k++ tip 20k", IDR, 129890, IDR, &Charges{Base: 90000, Service: 10, Tax: 11, Tip: 20000}},
+		{"12.50++ cad", IDR, 1526, CAD, &Charges{Base: 1250, Service: 10, Tax: 11}},
+		{"-80k tip 10k", IDR, -90000, IDR, &Charges{Base: -80000, Tip: -10000}},
+		{"-80k tip 10k idr", CAD, -90000, IDR, &Charges{Base: -80000, Tip: -10000}},
+		{"100k idr tip 5k idr", CAD, 105000, IDR, &Charges{Base: 100000, Tip: 5000}},
+	}
+	for _, tt := range tests {
+		got, cur, charges, err := ParseChargedAmount(tt.in, tt.cur, DefaultPair, DefaultChargeProfile)
+		if err != nil {
+			t.Errorf("ParseChargedAmount(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want || cur.Code != tt.wantCur.Code {
+			t.Errorf("ParseChargedAmount(%q) = %d %s, want %d %s", tt.in, got, cur.Code, tt.want, tt.wantCur.Code)
+		}
+		switch {
+		case (charges == nil) != (tt.charges == nil):
+			t.Errorf("ParseChargedAmount(%q) charges = %+v, want %+v", tt.in, charges, tt.charges)
+		case charges != nil && *charges != *tt.charges:
+			t.Errorf("ParseChargedAmount(%q) charges = %+v, want %+v", tt.in, *charges, *tt.charges)
+		}
+	}
+
+	for _, in := range []string{
+		"100k idr tip 5 cad", // The tip in another currency
+		"5 cad tip 50k idr",
+		"100k tip 5cad",
+		"45k tip20k", // "tip" is only read as a word
+		"45k tip",
+	} {
+		if got, _, _, err := ParseChargedAmount(in, IDR, DefaultPair, DefaultChargeProfile); err == nil {
+			t.Errorf("ParseChargedAmount(%q) = %d, want an error", in, got)
+		}
+	}
+}
+
+func TestChargesAmounts(t *testing.T) {
+	tests := []struct {
+		charges      Charges
+		service, tax Money
+		total        Money
+	}{
+		// Tax is charged on the base plus the service charge
+		{Charges{Base: 150000, Service: 10, Tax: 11}, 15000, 18150, 183150},
+		{Charges{Base: 12345, Service: 10, Tax: 11}, 1235, 1494, 15074},
+		{Charges{Base: -12345, Service: 10, Tax: 11, Tip: -1000}, -1235, -1494, -16074},
+		{Charges{Base: 10000, Tax: 11}, 0, 1100, 11100},
+	}
+	for _, tt := range tests {
+		c := tt.charges
+		if c.ServiceAmount() != tt.service || c.TaxAmount() != tt.tax || c.Total() != tt.total {
+			t.Errorf("%+v: service %d, tax %d, total %d, want %d, %d, %d",
+				c, c.ServiceAmount(), c.TaxAmount(), c.Total(), tt.service, tt.tax, tt.total)
+		}
+	}
+}
+
+func TestSumCharges(t *testing.T) {
+	date := time.Date(2026, 10, 14, 0, 0, 0, 0, time.Local)
+
+	local := NewEntry(date, "Warung", -1831, -183150, "")
+	local.SetConverted("IDR", 10000)
+	local.Charges = &Charges{Base: -150000, Service: 10, Tax: 11}
+
+	// Charges entered in the home currency are converted at the entry's rate
+	home := NewEntry(date, "Bistro", -1626, -162600, "")
+	home.SetConverted("CAD", 10000)
+	home.Charges = &Charges{Base: -1250, Service: 10, Tax: 11, Tip: -100}
+
+	plain := NewEntry(date, "Coffee", -300, -30000, "")
+	plain.SetConverted("IDR", 10000)
+
+	got := SumCharges([]*Entry{local, home, plain}, DefaultPair)
+	want := ChargeTotals{Entries: 2, Base: -275000, Service: -27500, Tax: -33250, Tip: -10000}
+	if got != want {
+		t.Errorf("SumCharges = %+v, want %+v", got, want)
+	}
+}
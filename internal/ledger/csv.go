@@ -2,38 +2,76 @@ package ledger
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
+
+	"ledger-a/internal/fsutil"
 )
 
 const (
 	DataDir         = "ledger-data"
 	DateFormat      = "2006-01-02"
-	CSVHeader       = "date,description,cad,idr,screen_time"
 	CSVFileName     = "data.csv"
 	JournalFileName = "entry.md"
 )
 
 // CSVManager handles CSV file operations
 type CSVManager struct {
-	dataDir string
+	dataDir    string
+	currencies Pair
 }
 
 // NewCSVManager creates a new CSV manager
 func NewCSVManager() *CSVManager {
 	return &CSVManager{
-		dataDir: DataDir,
+		dataDir:    DataDir,
+		currencies: DefaultPair,
 	}
 }
 
 // NewCSVManagerWithDir creates a new CSV manager with a custom data directory
 func NewCSVManagerWithDir(dataDir string) *CSVManager {
 	return &CSVManager{
-		dataDir: dataDir,
+		dataDir:    dataDir,
+		currencies: DefaultPair,
+	}
+}
+
+// SetCurrencies sets the home and local currency used for the amount columns
+func (m *CSVManager) SetCurrencies(pair Pair) {
+	m.currencies = pair
+}
+
+// GetCurrencies returns the home and local currency used for the amount columns
+func (m *CSVManager) GetCurrencies() Pair {
+	return m.currencies
+}
+
+// csvHeader returns the header row; the amount columns are named after the
+// ledger's currencies (e.g., "cad" and "idr"). The charge columns (base, service,
+// tax and tip) are empty for entries without charges, the split columns
+// (paid_by, split and the bill someone else paid) for entries that aren't shared,
+// and the transfer columns (the account moved to and the amount) for entries that
+// aren't transfers.
+func (m *CSVManager) csvHeader() []string {
+	return []string{
+		"id", "date", "description",
+		strings.ToLower(m.currencies.Home.Code),
+		strings.ToLower(m.currencies.Local.Code),
+		"screen_time", "rate", "source", "category", "tags",
+		"base", "service", "tax", "tip",
+		"paid_by", "split",
+		"bill_" + strings.ToLower(m.currencies.Home.Code),
+		"bill_" + strings.ToLower(m.currencies.Local.Code),
+		"account", "transfer_to",
+		"transfer_" + strings.ToLower(m.currencies.Home.Code),
+		"transfer_" + strings.ToLower(m.currencies.Local.Code),
 	}
 }
 
@@ -96,7 +134,7 @@ func (m *CSVManager) SaveJournal(date time.Time, content string) error {
 	}
 
 	path := m.GetJournalPath(date)
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+	if err := fsutil.WriteFile(path, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write journal: %w", err)
 	}
 	return nil
@@ -118,55 +156,59 @@ func (m *CSVManager) DayHasData(date time.Time) bool {
 
 // LoadDay loads entries from a CSV file for a specific date
 func (m *CSVManager) LoadDay(date time.Time) (*Day, error) {
+	day, _, err := m.loadDay(date)
+	return day, err
+}
+
+// loadDay loads a day like LoadDay and also returns the number of rows that
+// couldn't be read and were left out of the day
+func (m *CSVManager) loadDay(date time.Time) (*Day, int, error) {
 	day := NewDay(date)
+	day.Currencies = m.currencies
+	skipped := 0
 
 	// Load CSV data
 	path := m.GetFilePath(date)
 	file, err := os.Open(path)
 	if err != nil {
 		if !os.IsNotExist(err) {
-			return nil, fmt.Errorf("failed to open file: %w", err)
+			return nil, 0, fmt.Errorf("failed to open file: %w", err)
 		}
 		// File doesn't exist, continue with empty day
 	} else {
 		defer file.Close()
 
 		reader := csv.NewReader(file)
+		reader.FieldsPerRecord = -1 // Older files have fewer columns
 		records, err := reader.ReadAll()
 		if err != nil {
-			return nil, fmt.Errorf("failed to read CSV: %w", err)
+			return nil, 0, fmt.Errorf("failed to read CSV: %w", err)
 		}
 
-		// Skip header row
-		for i, record := range records {
-			if i == 0 {
-				continue // Skip header
-			}
-			if len(record) < 5 {
-				continue // Skip malformed rows
-			}
-
-			entryDate, err := time.Parse(DateFormat, record[0])
-			if err != nil {
-				continue // Skip rows with invalid dates
-			}
-
-			cad, err := strconv.ParseFloat(record[2], 64)
-			if err != nil {
-				cad = 0
-			}
-
-			idr, err := strconv.ParseFloat(record[3], 64)
-			if err != nil {
-				idr = 0
-			}
+		if len(records) > 0 {
+			columns := parseCSVHeader(records[0])
+			seenIDs := make(map[string]bool)
 
-			entry := NewEntry(entryDate, record[1], cad, idr, record[4])
-			day.AddEntry(entry)
+			// Skip header row
+			for _, record := range records[1:] {
+				entry, ok := parseEntryRecord(record, columns, m.currencies)
+				if !ok {
+					skipped++ // Skip malformed rows
+					continue
+				}
+				// Files from before IDs were persisted (or hand-edited duplicates)
+				// get a fresh ID; MigrateIDs writes them back once
+				if entry.ID == "" || seenIDs[entry.ID] {
+					entry.ID = NewEntryID()
+				}
+				seenIDs[entry.ID] = true
+				screenTime := entry.ScreenTime
+				day.AddEntry(entry)
 
-			// Set screen time from first entry (all entries have same screen time)
-			if day.ScreenTime == "" && record[4] != "" {
-				day.SetScreenTime(record[4])
+				// Set screen time from first entry (all entries have same screen time)
+				if day.ScreenTime == "" && screenTime != "" {
+					day.SetScreenTime(screenTime)
+				}
 			}
 		}
 	}
@@ -174,58 +216,235 @@ func (m *CSVManager) LoadDay(date time.Time) (*Day, error) {
 	// Load journal if it exists
 	journal, err := m.LoadJournal(date)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load journal: %w", err)
+		return nil, 0, fmt.Errorf("failed to load journal: %w", err)
 	}
 	day.Journal = journal
+	day.loaded = true
 
-	return day, nil
+	return day, skipped, nil
 }
 
-// SaveDay saves a day's entries to a CSV file
-func (m *CSVManager) SaveDay(day *Day) error {
-	if err := m.EnsureDayDir(day.Date); err != nil {
-		return fmt.Errorf("failed to create day directory: %w", err)
+// csvColumns maps header names to their column index
+type csvColumns map[string]int
+
+// parseCSVHeader builds the column index from a header row.
+// Files written before a column existed simply lack it in their header.
+func parseCSVHeader(header []string) csvColumns {
+	columns := make(csvColumns, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.ToLower(name))] = i
 	}
+	return columns
+}
 
-	// Only create CSV if there are entries
-	if len(day.Entries) > 0 {
-		path := m.GetFilePath(day.Date)
-		file, err := os.Create(path)
-		if err != nil {
-			return fmt.Errorf("failed to create file: %w", err)
-		}
-		defer file.Close()
+// get returns the value of a named column, or "" if the file has no such column
+func (c csvColumns) get(record []string, name string) string {
+	i, ok := c[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return record[i]
+}
 
-		writer := csv.NewWriter(file)
-		defer writer.Flush()
+// parseEntryRecord parses a CSV row into an entry.
+// Amounts are read from the columns named after the ledger's currencies.
+func parseEntryRecord(record []string, columns csvColumns, currencies Pair) (*Entry, bool) {
+	entryDate, err := time.Parse(DateFormat, columns.get(record, "date"))
+	if err != nil {
+		return nil, false // Skip rows with invalid dates
+	}
 
-		// Write header
-		if err := writer.Write(strings.Split(CSVHeader, ",")); err != nil {
-			return fmt.Errorf("failed to write header: %w", err)
-		}
+	home, err := ParseMoney(columns.get(record, strings.ToLower(currencies.Home.Code)), currencies.Home)
+	if err != nil {
+		home = 0
+	}
 
-		// Write entries
-		for _, entry := range day.Entries {
-			record := []string{
-				entry.DateString(),
-				entry.Description,
-				fmt.Sprintf("%.2f", entry.CAD),
-				fmt.Sprintf("%.0f", entry.IDR),
-				day.ScreenTime,
-			}
-			if err := writer.Write(record); err != nil {
-				return fmt.Errorf("failed to write entry: %w", err)
-			}
+	local, err := ParseMoney(columns.get(record, strings.ToLower(currencies.Local.Code)), currencies.Local)
+	if err != nil {
+		local = 0
+	}
+
+	entry := NewEntry(entryDate, columns.get(record, "description"), home, local, columns.get(record, "screen_time"))
+	entry.ID = strings.TrimSpace(columns.get(record, "id"))
+
+	if rate, err := strconv.ParseFloat(columns.get(record, "rate"), 64); err == nil {
+		entry.Rate = rate
+	}
+	entry.Source = strings.ToUpper(columns.get(record, "source"))
+	entry.Category, _ = ParseCategoryInput(columns.get(record, "category"))
+	entry.Tags = ParseTags(columns.get(record, "tags"))
+	entry.Charges = parseChargesRecord(record, columns, entry.SourceCurrency(currencies))
+	entry.Payer, entry.Split = parseSplitRecord(record, columns, entry.SourceCurrency(currencies), currencies)
+	entry.Account = strings.TrimSpace(columns.get(record, "account"))
+	entry.Transfer = parseTransferRecord(record, columns, currencies)
+
+	return entry, true
+}
+
+// entryRecord formats an entry as a CSV row matching csvHeader
+func entryRecord(entry *Entry, screenTime string, currencies Pair) []string {
+	rate := ""
+	if entry.Rate != 0 {
+		rate = strconv.FormatFloat(entry.Rate, 'f', -1, 64)
+	}
+
+	record := []string{
+		entry.ID,
+		entry.DateString(),
+		entry.Description,
+		entry.Home.Format(currencies.Home),
+		entry.Local.Format(currencies.Local),
+		screenTime,
+		rate,
+		entry.Source,
+		entry.Category,
+		FormatTags(entry.Tags),
+	}
+	record = append(record, chargesRecord(entry.Charges, entry.SourceCurrency(currencies))...)
+	record = append(record, splitRecord(entry, currencies)...)
+	return append(record, transferRecord(entry, currencies)...)
+}
+
+// parseChargesRecord reads the charge columns, in the entry's source currency.
+// Rows without a base amount have no charges.
+func parseChargesRecord(record []string, columns csvColumns, cur Currency) *Charges {
+	baseField := strings.TrimSpace(columns.get(record, "base"))
+	base, err := ParseMoney(baseField, cur)
+	if err != nil || baseField == "" {
+		return nil
+	}
+	charges := &Charges{Base: base}
+	charges.Service, _ = strconv.ParseFloat(columns.get(record, "service"), 64)
+	charges.Tax, _ = strconv.ParseFloat(columns.get(record, "tax"), 64)
+	charges.Tip, _ = ParseMoney(columns.get(record, "tip"), cur)
+	return charges
+}
+
+// parseSplitRecord reads who paid and how the entry is shared. A payer without
+// people means the bill was all yours.
+func parseSplitRecord(record []string, columns csvColumns, cur Currency, currencies Pair) (string, *Split) {
+	payer := strings.TrimSpace(columns.get(record, "paid_by"))
+	people := strings.TrimSpace(columns.get(record, "split"))
+	if payer == "" && people == "" {
+		return "", nil
+	}
+	if payer == "" {
+		payer = Me
+	}
+
+	payer, split, err := ParseSplit(payer+": "+people, cur, currencies)
+	if err != nil || split == nil {
+		return "", nil
+	}
+	if !IsMe(payer) {
+		split.BillHome, _ = ParseMoney(columns.get(record, "bill_"+strings.ToLower(currencies.Home.Code)), currencies.Home)
+		split.BillLocal, _ = ParseMoney(columns.get(record, "bill_"+strings.ToLower(currencies.Local.Code)), currencies.Local)
+	}
+	return payer, split
+}
+
+// parseTransferRecord reads the account money was moved to and the amount moved
+// (nil if the entry isn't a transfer)
+func parseTransferRecord(record []string, columns csvColumns, currencies Pair) *Transfer {
+	to := strings.TrimSpace(columns.get(record, "transfer_to"))
+	if to == "" {
+		return nil
+	}
+	transfer := &Transfer{To: to}
+	transfer.Home, _ = ParseMoney(columns.get(record, "transfer_"+strings.ToLower(currencies.Home.Code)), currencies.Home)
+	transfer.Local, _ = ParseMoney(columns.get(record, "transfer_"+strings.ToLower(currencies.Local.Code)), currencies.Local)
+	return transfer
+}
+
+// transferRecord formats the account and transfer columns
+func transferRecord(entry *Entry, currencies Pair) []string {
+	if entry.Transfer == nil {
+		return []string{entry.Account, "", "", ""}
+	}
+	t := entry.Transfer
+	return []string{entry.Account, t.To, t.Home.Format(currencies.Home), t.Local.Format(currencies.Local)}
+}
+
+// splitRecord formats the split columns (empty for entries that aren't shared)
+func splitRecord(entry *Entry, currencies Pair) []string {
+	if entry.Split == nil {
+		return []string{"", "", "", ""}
+	}
+	record := []string{entry.Payer, entry.Split.Format(entry.SourceCurrency(currencies)), "", ""}
+	if !IsMe(entry.Payer) {
+		record[2] = entry.Split.BillHome.Format(currencies.Home)
+		record[3] = entry.Split.BillLocal.Format(currencies.Local)
+	}
+	return record
+}
+
+// chargesRecord formats the charge columns (empty without charges)
+func chargesRecord(c *Charges, cur Currency) []string {
+	if c == nil {
+		return []string{"", "", "", ""}
+	}
+	return []string{c.Base.Format(cur), formatPercent(c.Service), formatPercent(c.Tax), c.Tip.Format(cur)}
+}
+
+// SaveDay makes the files on disk match the day: data.csv holds the entries and
+// entry.md the journal. A file is deleted when its part of the day is empty, and
+// the day directory is removed once nothing is left in it. Only a day loaded
+// from disk deletes anything: a day made with NewDay, e.g. after the files
+// failed to load, doesn't know what they held.
+func (m *CSVManager) SaveDay(day *Day) error {
+	if len(day.Entries) > 0 {
+		if err := m.EnsureDayDir(day.Date); err != nil {
+			return fmt.Errorf("failed to create day directory: %w", err)
+		}
+		if err := m.writeEntries(m.GetFilePath(day.Date), []*Day{day}); err != nil {
+			return err
+		}
+	} else if day.loaded {
+		if err := m.DeleteDay(day.Date); err != nil {
+			return err
 		}
 	}
 
-	// Save journal if it exists
 	if day.Journal != "" {
 		if err := m.SaveJournal(day.Date, day.Journal); err != nil {
 			return fmt.Errorf("failed to save journal: %w", err)
 		}
+	} else if day.loaded {
+		if err := m.DeleteJournal(day.Date); err != nil {
+			return err
+		}
 	}
 
+	if day.IsEmpty() && day.loaded {
+		return m.removeEmptyDirs(day.Date)
+	}
+	return nil
+}
+
+// removeEmptyDirs removes the day directory and its month and year directories
+// if they are empty, stopping at the first one that still has contents
+func (m *CSVManager) removeEmptyDirs(date time.Time) error {
+	dirs := []string{
+		m.GetDayDir(date),
+		filepath.Join(m.dataDir, date.Format("2006"), date.Format("01")),
+		filepath.Join(m.dataDir, date.Format("2006")),
+	}
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("failed to read directory: %w", err)
+		}
+		if len(entries) > 0 {
+			return nil
+		}
+		if err := os.Remove(dir); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove empty directory: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -238,23 +457,34 @@ func (m *CSVManager) DeleteDay(date time.Time) error {
 	return nil
 }
 
-// LoadDateRange loads all days within a date range
+// LoadDateRange loads all days within a date range that have entries or a journal
 func (m *CSVManager) LoadDateRange(start, end time.Time) (*DateRange, error) {
 	dateRange := NewDateRange(start, end)
+	dateRange.Currencies = m.currencies
 
-	// Iterate through each day in the range
-	current := start
-	for !current.After(end) {
-		if m.FileExists(current) {
-			day, err := m.LoadDay(current)
-			if err != nil {
-				return nil, fmt.Errorf("failed to load day %s: %w", current.Format(DateFormat), err)
-			}
-			if !day.IsEmpty() {
-				dateRange.AddDay(day)
-			}
+	// Only visit days that exist on disk rather than every calendar day in the range
+	dates, err := m.ListAvailableDates()
+	if err != nil {
+		return nil, err
+	}
+
+	// DateFormat sorts chronologically, so compare the formatted dates to ignore time of day
+	first, last := start.Format(DateFormat), end.Format(DateFormat)
+	for _, date := range dates {
+		key := date.Format(DateFormat)
+		if key < first || key > last {
+			continue
+		}
+
+		// Keep the location of the requested range for the loaded days
+		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, start.Location())
+		day, err := m.LoadDay(date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load day %s: %w", key, err)
+		}
+		if !day.IsEmpty() {
+			dateRange.AddDay(day)
 		}
-		current = current.AddDate(0, 0, 1)
 	}
 
 	return dateRange, nil
@@ -266,41 +496,43 @@ func (m *CSVManager) ExportDateRange(dateRange *DateRange, filename string) erro
 		return fmt.Errorf("failed to create data directory: %w", err)
 	}
 
-	path := filepath.Join(m.dataDir, filename)
-	file, err := os.Create(path)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer file.Close()
+	return m.writeEntries(filepath.Join(m.dataDir, filename), dateRange.Days)
+}
+
+// writeEntries atomically writes the entries of the given days as a CSV file
+func (m *CSVManager) writeEntries(path string, days []*Day) error {
+	return fsutil.WriteFileAtomic(path, 0644, func(w io.Writer) error {
+		return m.WriteCSV(w, days)
+	})
+}
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+// WriteCSV writes the entries of the given days in the day file format
+func (m *CSVManager) WriteCSV(w io.Writer, days []*Day) error {
+	writer := csv.NewWriter(w)
 
 	// Write header
-	if err := writer.Write(strings.Split(CSVHeader, ",")); err != nil {
+	if err := writer.Write(m.csvHeader()); err != nil {
 		return fmt.Errorf("failed to write header: %w", err)
 	}
 
 	// Write entries from all days
-	for _, day := range dateRange.Days {
+	for _, day := range days {
 		for _, entry := range day.Entries {
-			record := []string{
-				entry.DateString(),
-				entry.Description,
-				fmt.Sprintf("%.2f", entry.CAD),
-				fmt.Sprintf("%.0f", entry.IDR),
-				day.ScreenTime,
-			}
+			record := entryRecord(entry, day.ScreenTime, m.currencies)
 			if err := writer.Write(record); err != nil {
 				return fmt.Errorf("failed to write entry: %w", err)
 			}
 		}
 	}
 
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to flush CSV: %w", err)
+	}
 	return nil
 }
 
-// ListAvailableDates returns all dates that have data (CSV or journal)
+// ListAvailableDates returns all dates that have data (CSV or journal) in chronological order
 func (m *CSVManager) ListAvailableDates() ([]time.Time, error) {
 	var dates []time.Time
 
@@ -356,6 +588,82 @@ func (m *CSVManager) ListAvailableDates() ([]time.Time, error) {
 	return dates, nil
 }
 
+// readHeader returns the header row of a date's CSV file (nil if the file is
+// missing or empty)
+func (m *CSVManager) readHeader(date time.Time) ([]string, error) {
+	file, err := os.Open(m.GetFilePath(date))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil // Empty file
+	}
+	return header, nil
+}
+
+// hasIDColumn checks whether a date's CSV file already stores entry IDs
+func (m *CSVManager) hasIDColumn(date time.Time) (bool, error) {
+	header, err := m.readHeader(date)
+	if err != nil {
+		return false, err
+	}
+	if header == nil {
+		return true, nil // Missing or empty file, nothing to migrate
+	}
+	_, ok := parseCSVHeader(header)["id"]
+	return ok, nil
+}
+
+// MigrateIDs assigns persisted IDs to every day file written before the id column existed.
+// It is safe to run repeatedly; files that already have IDs are left untouched, and
+// so are files with rows that can't be read, since rewriting them would drop those
+// rows. Every file that can't be migrated is reported in the error, after the
+// others are migrated. Returns the number of files rewritten.
+func (m *CSVManager) MigrateIDs() (int, error) {
+	dates, err := m.ListAvailableDates()
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	var errs []error
+	for _, date := range dates {
+		ok, err := m.hasIDColumn(date)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if ok {
+			continue
+		}
+
+		day, skipped, err := m.loadDay(date)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to load day %s: %w", date.Format(DateFormat), err))
+			continue
+		}
+		if skipped > 0 {
+			errs = append(errs, fmt.Errorf("%s not migrated: %d rows can't be read", m.GetFilePath(date), skipped))
+			continue
+		}
+		if err := m.SaveDay(day); err != nil {
+			errs = append(errs, fmt.Errorf("failed to save day %s: %w", date.Format(DateFormat), err))
+			continue
+		}
+		migrated++
+	}
+
+	return migrated, errors.Join(errs...)
+}
+
 // GetDataDir returns the data directory path
 func (m *CSVManager) GetDataDir() string {
 	return m.dataDir
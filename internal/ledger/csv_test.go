@@ -0,0 +1,273 @@
+package ledger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// savedDay saves a day with one entry and a journal and returns it as loaded back
+func savedDay(t *testing.T, m *CSVManager, date time.Time) *Day {
+	t.Helper()
+	day := NewDay(date)
+	day.AddEntry(NewEntry(date, "Coffee", -350, -35000, ""))
+	day.Journal = "Quiet day"
+	if err := m.SaveDay(day); err != nil {
+		t.Fatalf("SaveDay: %v", err)
+	}
+	loaded, err := m.LoadDay(date)
+	if err != nil {
+		t.Fatalf("LoadDay: %v", err)
+	}
+	return loaded
+}
+
+// emptied clears a saved day's entries and journal
+func emptied(day *Day) *Day {
+	day.Entries = nil
+	day.Journal = ""
+	return day
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestSaveDayLastEntryDeleted(t *testing.T) {
+	m := NewCSVManagerWithDir(t.TempDir())
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	day := savedDay(t, m, date)
+
+	day.RemoveEntry(day.Entries[0].ID)
+	if err := m.SaveDay(day); err != nil {
+		t.Fatalf("SaveDay: %v", err)
+	}
+	if exists(m.GetFilePath(date)) {
+		t.Error("data.csv is left after the last entry was deleted")
+	}
+	if !exists(m.GetJournalPath(date)) {
+		t.Error("entry.md was removed with the entries")
+	}
+}
+
+func TestSaveDayJournalCleared(t *testing.T) {
+	m := NewCSVManagerWithDir(t.TempDir())
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	day := savedDay(t, m, date)
+
+	day.Journal = ""
+	if err := m.SaveDay(day); err != nil {
+		t.Fatalf("SaveDay: %v", err)
+	}
+	if exists(m.GetJournalPath(date)) {
+		t.Error("entry.md is left after the journal was cleared")
+	}
+	loaded, err := m.LoadDay(date)
+	if err != nil {
+		t.Fatalf("LoadDay: %v", err)
+	}
+	if len(loaded.Entries) != 1 {
+		t.Errorf("loaded %d entries, want 1", len(loaded.Entries))
+	}
+}
+
+func TestSaveDayEmptyRemovesDirs(t *testing.T) {
+	dataDir := t.TempDir()
+	m := NewCSVManagerWithDir(dataDir)
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	day := savedDay(t, m, date)
+
+	if err := m.SaveDay(emptied(day)); err != nil {
+		t.Fatalf("SaveDay: %v", err)
+	}
+	for _, dir := range []string{m.GetDayDir(date), filepath.Join(dataDir, "2024", "03"), filepath.Join(dataDir, "2024")} {
+		if exists(dir) {
+			t.Errorf("%s is left after the day was emptied", dir)
+		}
+	}
+	if !exists(dataDir) {
+		t.Error("the data directory was removed")
+	}
+}
+
+func TestSaveDayEmptyKeepsOtherData(t *testing.T) {
+	m := NewCSVManagerWithDir(t.TempDir())
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	other := date.AddDate(0, 0, 1)
+	day := savedDay(t, m, date)
+	savedDay(t, m, other)
+
+	// A file the ledger doesn't know about keeps the day directory
+	receipt := filepath.Join(m.GetDayDir(date), "receipt.jpg")
+	if err := os.WriteFile(receipt, []byte("jpeg"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.SaveDay(emptied(day)); err != nil {
+		t.Fatalf("SaveDay: %v", err)
+	}
+	if !exists(receipt) {
+		t.Error("an unrelated file in the day directory was removed")
+	}
+	if exists(m.GetFilePath(date)) || exists(m.GetJournalPath(date)) {
+		t.Error("the emptied day's files are left")
+	}
+	if !exists(m.GetFilePath(other)) || !exists(m.GetJournalPath(other)) {
+		t.Error("another day in the same month was touched")
+	}
+}
+
+func TestSaveDayUnloadedKeepsFiles(t *testing.T) {
+	m := NewCSVManagerWithDir(t.TempDir())
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	savedDay(t, m, date)
+
+	// A stray quote makes the whole file unreadable
+	if err := os.WriteFile(m.GetFilePath(date), []byte("date,description\n\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.LoadDay(date); err == nil {
+		t.Fatal("LoadDay read a file with a bare quote")
+	}
+
+	// What a caller falls back to when the day can't be loaded
+	if err := m.SaveDay(NewDay(date)); err != nil {
+		t.Fatalf("SaveDay: %v", err)
+	}
+	if !exists(m.GetFilePath(date)) {
+		t.Error("data.csv was deleted by saving a day that was never loaded")
+	}
+	if !exists(m.GetJournalPath(date)) {
+		t.Error("entry.md was deleted by saving a day that was never loaded")
+	}
+}
+
+func TestMigrateIDsKeepsFilesWithUnreadableRows(t *testing.T) {
+	m := NewCSVManagerWithDir(t.TempDir())
+	good := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	partial := good.AddDate(0, 0, 1)
+	broken := good.AddDate(0, 0, 2)
+	files := map[time.Time]string{
+		good:    "date,description,cad,idr,screen_time\n2024-03-15,Coffee,-3.50,-35000,\n",
+		partial: "date,description,cad,idr,screen_time\n2024-03-16,Lunch,-5.00,-50000,\n16/03/2024,Dinner,-9.00,-90000,\n",
+		broken:  "date,description,cad,idr,screen_time\nnot a date,Taxi,-2.00,-20000,\n",
+	}
+	for date, content := range files {
+		if err := m.EnsureDayDir(date); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(m.GetFilePath(date), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	migrated, err := m.MigrateIDs()
+	if err == nil {
+		t.Error("MigrateIDs didn't report the files it couldn't migrate")
+	}
+	if migrated != 1 {
+		t.Errorf("migrated %d files, want 1", migrated)
+	}
+	if ok, err := m.hasIDColumn(good); err != nil || !ok {
+		t.Errorf("readable file not migrated: %v", err)
+	}
+	for _, date := range []time.Time{partial, broken} {
+		data, err := os.ReadFile(m.GetFilePath(date))
+		if err != nil {
+			t.Fatalf("%s: %v", date.Format(DateFormat), err)
+		}
+		if string(data) != files[date] {
+			t.Errorf("%s was rewritten:\n%s", date.Format(DateFormat), data)
+		}
+	}
+}
+
+func TestSaveDayKeepsRateAndSource(t *testing.T) {
+	m := NewCSVManagerWithDir(t.TempDir())
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	day := NewDay(date)
+	coffee := NewEntry(date, "Coffee", 0, 0, "")
+	coffee.SetAmount(-450, CAD, DefaultPair, 11512.5)
+	lunch := NewEntry(date, "Lunch", 0, 0, "")
+	lunch.SetAmount(-45000, IDR, DefaultPair, 11512.5)
+	pending := NewEntry(date, "Taxi", 0, -30000, "")
+	pending.SetConverted("IDR", 0) // Waiting for the day's rate
+	day.AddEntry(coffee)
+	day.AddEntry(lunch)
+	day.AddEntry(pending)
+	if err := m.SaveDay(day); err != nil {
+		t.Fatalf("SaveDay: %v", err)
+	}
+
+	loaded, err := m.LoadDay(date)
+	if err != nil {
+		t.Fatalf("LoadDay: %v", err)
+	}
+	for i, want := range day.Entries {
+		got := loaded.Entries[i]
+		if got.Rate != want.Rate || got.Source != want.Source || got.Home != want.Home || got.Local != want.Local {
+			t.Errorf("%s loaded as %d, %d from %q at %v, want %d, %d from %q at %v", want.Description,
+				got.Home, got.Local, got.Source, got.Rate, want.Home, want.Local, want.Source, want.Rate)
+		}
+	}
+	if !loaded.Entries[2].NeedsRate() {
+		t.Error("the entry waiting for a rate doesn't need one after loading")
+	}
+}
+
+func TestLoadDayFiveColumnFile(t *testing.T) {
+	m := NewCSVManagerWithDir(t.TempDir())
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	if err := m.EnsureDayDir(date); err != nil {
+		t.Fatal(err)
+	}
+	old := "date,description,cad,idr,screen_time\n2024-03-15,Coffee,-3.50,-35000,1h\n"
+	if err := os.WriteFile(m.GetFilePath(date), []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	day, err := m.LoadDay(date)
+	if err != nil {
+		t.Fatalf("LoadDay: %v", err)
+	}
+	if len(day.Entries) != 1 {
+		t.Fatalf("loaded %d entries, want 1", len(day.Entries))
+	}
+	entry := day.Entries[0]
+	if entry.Home != -350 || entry.Local != -35000 || entry.ScreenTime != "1h" {
+		t.Errorf("entry = %d, %d with screen time %q, want -350, -35000 with 1h", entry.Home, entry.Local, entry.ScreenTime)
+	}
+	// Both amounts were stored, so neither is marked as the one typed
+	if entry.Rate != 0 || entry.Source != "" || entry.NeedsRate() {
+		t.Errorf("entry from %q at %v, want no source or rate", entry.Source, entry.Rate)
+	}
+}
+
+func TestLoadDateRangeIncludesJournalOnlyDays(t *testing.T) {
+	m := NewCSVManagerWithDir(t.TempDir())
+	start := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	savedDay(t, m, start)
+	if err := m.SaveJournal(start.AddDate(0, 0, 2), "Rained all day"); err != nil {
+		t.Fatal(err)
+	}
+	// Outside the range, and a day directory with nothing in it
+	savedDay(t, m, start.AddDate(0, 0, 10))
+	if err := m.EnsureDayDir(start.AddDate(0, 0, 3)); err != nil {
+		t.Fatal(err)
+	}
+
+	dateRange, err := m.LoadDateRange(start, start.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("LoadDateRange: %v", err)
+	}
+	if len(dateRange.Days) != 2 {
+		t.Fatalf("range has %d days, want the day with entries and the journal-only day", len(dateRange.Days))
+	}
+	journal := dateRange.Days[1]
+	if !journal.Date.Equal(start.AddDate(0, 0, 2)) || journal.Journal != "Rained all day" || len(journal.Entries) != 0 {
+		t.Errorf("second day = %s with journal %q and %d entries, want the journal-only day",
+			journal.Date.Format(DateFormat), journal.Journal, len(journal.Entries))
+	}
+}
@@ -1,7 +1,6 @@
 package ledger
 
 import (
-	"fmt"
 	"sort"
 	"strings"
 	"time"
@@ -13,6 +12,9 @@ type Day struct {
 	Entries    []*Entry
 	ScreenTime string
 	Journal    string // Markdown journal entry for the day
+	Currencies Pair   // Home and local currency of the ledger the day belongs to
+
+	loaded bool // Read from disk, so saving it may delete the day's files
 }
 
 // NewDay creates a new Day instance
@@ -22,6 +24,7 @@ func NewDay(date time.Time) *Day {
 		Entries:    make([]*Entry, 0),
 		ScreenTime: "",
 		Journal:    "",
+		Currencies: DefaultPair,
 	}
 }
 
@@ -77,22 +80,36 @@ func (d *Day) SetScreenTime(screenTime string) {
 	}
 }
 
-// TotalCAD returns the sum of all CAD amounts
-func (d *Day) TotalCAD() float64 {
-	var total float64
+// NeedsRate returns true if any entry is waiting for the day's rate
+func (d *Day) NeedsRate() bool {
 	for _, e := range d.Entries {
-		total += e.CAD
+		if e.NeedsRate() {
+			return true
+		}
 	}
-	return total
+	return false
 }
 
-// TotalIDR returns the sum of all IDR amounts
-func (d *Day) TotalIDR() float64 {
-	var total float64
+// FillRate converts the entries waiting for the day's rate and returns how many it filled in
+func (d *Day) FillRate(rate float64) int {
+	filled := 0
 	for _, e := range d.Entries {
-		total += e.IDR
+		if e.NeedsRate() && rate != 0 {
+			e.FillRate(d.Currencies, rate)
+			filled++
+		}
 	}
-	return total
+	return filled
+}
+
+// TotalHome returns the sum of all home currency amounts, leaving out bookkeeping
+func (d *Day) TotalHome() Money {
+	return d.FilteredTotalHome("")
+}
+
+// TotalLocal returns the sum of all local currency amounts, leaving out bookkeeping
+func (d *Day) TotalLocal() Money {
+	return d.FilteredTotalLocal("")
 }
 
 // DateString returns the date formatted as YYYY-MM-DD (for CSV)
@@ -111,8 +128,8 @@ func (d *Day) FormatDateDisplay() string {
 }
 
 // EntryMatchesQuery checks if an entry matches the search query (vim-style)
-// Searches across all fields: date, description, CAD, IDR
-func EntryMatchesQuery(entry *Entry, query string) bool {
+// Searches across all fields: date, description, category, tags, home and local amounts
+func EntryMatchesQuery(entry *Entry, query string, currencies Pair) bool {
 	if query == "" {
 		return true
 	}
@@ -124,6 +141,11 @@ func EntryMatchesQuery(entry *Entry, query string) bool {
 		return true
 	}
 
+	// Search in category and tags (e.g., "food" or "#dinner")
+	if strings.Contains(entry.CategoryLabel(), query) {
+		return true
+	}
+
 	// Search in date (multiple formats)
 	dateStr := entry.Date.Format("01/02/2006")
 	if strings.Contains(dateStr, query) {
@@ -142,15 +164,15 @@ func EntryMatchesQuery(entry *Entry, query string) bool {
 		return true
 	}
 
-	// Search in CAD amount
-	cadStr := fmt.Sprintf("%.2f", entry.CAD)
-	if strings.Contains(cadStr, query) {
+	// Search in home amount
+	homeStr := entry.Home.Format(currencies.Home)
+	if strings.Contains(homeStr, query) {
 		return true
 	}
 
-	// Search in IDR amount
-	idrStr := fmt.Sprintf("%.0f", entry.IDR)
-	if strings.Contains(idrStr, query) {
+	// Search in local amount
+	localStr := entry.Local.Format(currencies.Local)
+	if strings.Contains(localStr, query) {
 		return true
 	}
 
@@ -165,27 +187,40 @@ func (d *Day) Filter(query string) []*Entry {
 
 	var filtered []*Entry
 	for _, e := range d.Entries {
-		if EntryMatchesQuery(e, query) {
+		if EntryMatchesQuery(e, query, d.Currencies) {
 			filtered = append(filtered, e)
 		}
 	}
 	return filtered
 }
 
-// FilteredTotalCAD returns the sum of CAD for filtered entries
-func (d *Day) FilteredTotalCAD(query string) float64 {
-	var total float64
+// Counted returns the filtered entries that count toward totals: transfers,
+// settlements and adjustments move money without spending or earning it
+// (see Entry.IsBookkeeping)
+func (d *Day) Counted(query string) []*Entry {
+	var counted []*Entry
 	for _, e := range d.Filter(query) {
-		total += e.CAD
+		if !e.IsBookkeeping() {
+			counted = append(counted, e)
+		}
+	}
+	return counted
+}
+
+// FilteredTotalHome returns the sum of home amounts for filtered entries, leaving out bookkeeping
+func (d *Day) FilteredTotalHome(query string) Money {
+	var total Money
+	for _, e := range d.Counted(query) {
+		total += e.Home
 	}
 	return total
 }
 
-// FilteredTotalIDR returns the sum of IDR for filtered entries
-func (d *Day) FilteredTotalIDR(query string) float64 {
-	var total float64
-	for _, e := range d.Filter(query) {
-		total += e.IDR
+// FilteredTotalLocal returns the sum of local amounts for filtered entries, leaving out bookkeeping
+func (d *Day) FilteredTotalLocal(query string) Money {
+	var total Money
+	for _, e := range d.Counted(query) {
+		total += e.Local
 	}
 	return total
 }
@@ -197,17 +232,19 @@ func (d *Day) IsEmpty() bool {
 
 // DateRange represents a range of days
 type DateRange struct {
-	Start time.Time
-	End   time.Time
-	Days  []*Day
+	Start      time.Time
+	End        time.Time
+	Days       []*Day
+	Currencies Pair // Home and local currency of the ledger
 }
 
 // NewDateRange creates a new DateRange
 func NewDateRange(start, end time.Time) *DateRange {
 	return &DateRange{
-		Start: start,
-		End:   end,
-		Days:  make([]*Day, 0),
+		Start:      start,
+		End:        end,
+		Days:       make([]*Day, 0),
+		Currencies: DefaultPair,
 	}
 }
 
@@ -219,20 +256,20 @@ func (dr *DateRange) AddDay(day *Day) {
 	})
 }
 
-// TotalCAD returns the sum of CAD for all days in the range
-func (dr *DateRange) TotalCAD() float64 {
-	var total float64
+// TotalHome returns the sum of home amounts for all days in the range, leaving out bookkeeping
+func (dr *DateRange) TotalHome() Money {
+	var total Money
 	for _, day := range dr.Days {
-		total += day.TotalCAD()
+		total += day.TotalHome()
 	}
 	return total
 }
 
-// TotalIDR returns the sum of IDR for all days in the range
-func (dr *DateRange) TotalIDR() float64 {
-	var total float64
+// TotalLocal returns the sum of local amounts for all days in the range, leaving out bookkeeping
+func (dr *DateRange) TotalLocal() Money {
+	var total Money
 	for _, day := range dr.Days {
-		total += day.TotalIDR()
+		total += day.TotalLocal()
 	}
 	return total
 }
@@ -246,20 +283,31 @@ func (dr *DateRange) AllEntries(query string) []*Entry {
 	return entries
 }
 
-// FilteredTotalCAD returns the sum of CAD for filtered entries across all days
-func (dr *DateRange) FilteredTotalCAD(query string) float64 {
-	var total float64
+// Counted returns the filtered entries from all days that count toward totals (see Day.Counted)
+func (dr *DateRange) Counted(query string) []*Entry {
+	var entries []*Entry
+	for _, day := range dr.Days {
+		entries = append(entries, day.Counted(query)...)
+	}
+	return entries
+}
+
+// FilteredTotalHome returns the sum of home amounts for filtered entries across all days,
+// leaving out bookkeeping
+func (dr *DateRange) FilteredTotalHome(query string) Money {
+	var total Money
 	for _, day := range dr.Days {
-		total += day.FilteredTotalCAD(query)
+		total += day.FilteredTotalHome(query)
 	}
 	return total
 }
 
-// FilteredTotalIDR returns the sum of IDR for filtered entries across all days
-func (dr *DateRange) FilteredTotalIDR(query string) float64 {
-	var total float64
+// FilteredTotalLocal returns the sum of local amounts for filtered entries across all days,
+// leaving out bookkeeping
+func (dr *DateRange) FilteredTotalLocal(query string) Money {
+	var total Money
 	for _, day := range dr.Days {
-		total += day.FilteredTotalIDR(query)
+		total += day.FilteredTotalLocal(query)
 	}
 	return total
 }
@@ -298,7 +346,7 @@ func DayMatchesQuery(day *Day, query string) bool {
 
 	// Check if any entry matches
 	for _, e := range day.Entries {
-		if EntryMatchesQuery(e, query) {
+		if EntryMatchesQuery(e, query, day.Currencies) {
 			return true
 		}
 	}
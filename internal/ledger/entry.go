@@ -1,58 +1,200 @@
 package ledger
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"time"
 )
 
-// Entry represents a single ledger entry (transaction)
+// Entry represents a single ledger entry (transaction). The JSON tags are the
+// entry's form in the undo history file.
 type Entry struct {
-	ID          string    // Unique identifier for undo operations
-	Date        time.Time // Date of the entry
-	Description string    // Description of the transaction
-	CAD         float64   // Cash flow in CAD
-	IDR         float64   // Cash flow in IDR
-	ScreenTime  string    // Screen time for the day (e.g., "3h45m")
+	ID          string    `json:"id"`                    // Unique identifier for undo operations
+	Date        time.Time `json:"date"`                  // Date of the entry
+	Description string    `json:"description"`           // Description of the transaction
+	Category    string    `json:"category,omitempty"`    // Spending category (e.g., "food"), "" if uncategorized
+	Tags        []string  `json:"tags,omitempty"`        // Free-form tags without the leading '#'
+	Home        Money     `json:"home"`                  // Cash flow in the ledger's home currency (e.g., CAD cents)
+	Local       Money     `json:"local"`                 // Cash flow in the ledger's local currency (e.g., IDR rupiah)
+	ScreenTime  string    `json:"screen_time,omitempty"` // Screen time for the day (e.g., "3h45m")
+	Rate        float64   `json:"rate,omitempty"`        // Home to local rate used to derive the other amount (0 if unknown)
+	Source      string    `json:"source,omitempty"`      // Code of the currency the amount was entered in ("" if unknown)
+	Charges     *Charges  `json:"charges,omitempty"`     // Service charge, tax and tip breakdown in the source currency (nil if none)
+	Payer       string    `json:"payer,omitempty"`       // Who paid, if not you ("" for you)
+	Split       *Split    `json:"split,omitempty"`       // How the bill is shared (nil if it isn't)
+	Account     string    `json:"account,omitempty"`     // Account it was paid from ("" if not recorded)
+	Transfer    *Transfer `json:"transfer,omitempty"`    // Money moved to another account (nil if not a transfer)
 }
 
 // NewEntry creates a new entry with a unique ID
-func NewEntry(date time.Time, description string, cad, idr float64, screenTime string) *Entry {
+func NewEntry(date time.Time, description string, home, local Money, screenTime string) *Entry {
 	return &Entry{
-		ID:          fmt.Sprintf("%d-%s", time.Now().UnixNano(), description[:min(8, len(description))]),
+		ID:          NewEntryID(),
 		Date:        date,
 		Description: description,
-		CAD:         cad,
-		IDR:         idr,
+		Home:        home,
+		Local:       local,
 		ScreenTime:  screenTime,
 	}
 }
 
+// NewEntryID returns a random identifier that is unique across reloads and sessions
+func NewEntryID() string {
+	var b [12]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand never fails on supported platforms; fall back to the clock just in case
+		return fmt.Sprintf("t%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b[:])
+}
+
 // Clone creates a deep copy of the entry
 func (e *Entry) Clone() *Entry {
 	return &Entry{
 		ID:          e.ID,
 		Date:        e.Date,
 		Description: e.Description,
-		CAD:         e.CAD,
-		IDR:         e.IDR,
+		Category:    e.Category,
+		Tags:        append([]string(nil), e.Tags...),
+		Home:        e.Home,
+		Local:       e.Local,
 		ScreenTime:  e.ScreenTime,
+		Rate:        e.Rate,
+		Source:      e.Source,
+		Charges:     e.Charges.Clone(),
+		Payer:       e.Payer,
+		Split:       e.Split.Clone(),
+		Account:     e.Account,
+		Transfer:    e.Transfer.Clone(),
 	}
 }
 
-// FormatCAD returns the CAD amount formatted with currency symbol
-func (e *Entry) FormatCAD() string {
-	if e.CAD >= 0 {
-		return fmt.Sprintf("$%.2f", e.CAD)
+// SetConverted records the rate and source currency used to fill in the amounts
+func (e *Entry) SetConverted(source string, rate float64) {
+	e.Source = source
+	e.Rate = rate
+}
+
+// SetAmount sets the amount in one of the pair's currencies and converts it to the
+// other at rate (local units per home unit)
+func (e *Entry) SetAmount(amount Money, cur Currency, currencies Pair, rate float64) {
+	if cur.Code == currencies.Home.Code {
+		e.Home = amount
+		e.Local = amount.MulRate(currencies.Home, currencies.Local, rate)
+	} else {
+		e.Local = amount
+		e.Home = amount.DivRate(currencies.Local, currencies.Home, rate)
 	}
-	return fmt.Sprintf("-$%.2f", -e.CAD)
+	e.SetConverted(cur.Code, rate)
 }
 
-// FormatIDR returns the IDR amount formatted with currency symbol
-func (e *Entry) FormatIDR() string {
-	if e.IDR >= 0 {
-		return fmt.Sprintf("Rp %.0f", e.IDR)
+// NeedsRate returns true if the amount was entered in one currency but has no
+// rate yet to fill in the other, because none was known for the entry's date
+func (e *Entry) NeedsRate() bool {
+	return e.Source != "" && e.Rate == 0
+}
+
+// FillRate converts the amounts of an entry that needs a rate at the given rate.
+// Only amounts still missing in the other currency are filled in, so the amounts
+// entered (and an exact amount debited for a transfer) are kept.
+func (e *Entry) FillRate(currencies Pair, rate float64) {
+	if !e.NeedsRate() || rate == 0 {
+		return
+	}
+	fromHome := e.Source == currencies.Home.Code
+	fill := func(home, local *Money) {
+		switch {
+		case fromHome && *local == 0:
+			*local = home.MulRate(currencies.Home, currencies.Local, rate)
+		case !fromHome && *home == 0:
+			*home = local.DivRate(currencies.Local, currencies.Home, rate)
+		}
+	}
+	fill(&e.Home, &e.Local)
+	if e.Transfer != nil {
+		fill(&e.Transfer.Home, &e.Transfer.Local)
+	}
+	if e.Split != nil {
+		fill(&e.Split.BillHome, &e.Split.BillLocal)
 	}
-	return fmt.Sprintf("-Rp %.0f", -e.IDR)
+	e.Rate = rate
+}
+
+// SourceCurrency returns the currency the amount was entered in, taking the local
+// currency if it's unknown
+func (e *Entry) SourceCurrency(currencies Pair) Currency {
+	if e.Source == currencies.Home.Code {
+		return currencies.Home
+	}
+	return currencies.Local
+}
+
+// SourceAmount returns the amount in the currency it was entered in
+func (e *Entry) SourceAmount(currencies Pair) Money {
+	if e.Source == currencies.Home.Code {
+		return e.Home
+	}
+	return e.Local
+}
+
+// ToggleCharges adds the profile's service charge and tax to the entry's amount,
+// or takes them off if the entry already has them. The tip is kept either way.
+// Rate is used if the entry has none of its own.
+func (e *Entry) ToggleCharges(profile ChargeProfile, currencies Pair, rate float64) {
+	if e.Rate != 0 {
+		rate = e.Rate
+	}
+	cur := e.SourceCurrency(currencies)
+
+	charges := e.Charges.Clone()
+	switch {
+	case charges == nil:
+		charges = NewCharges(e.SourceAmount(currencies), profile)
+	case charges.HasPercentages():
+		charges.Service, charges.Tax = 0, 0
+	default:
+		charges.Service, charges.Tax = profile.Service, profile.Tax
+	}
+
+	e.SetAmount(charges.Total(), cur, currencies, rate)
+	if charges.HasPercentages() || charges.Tip != 0 {
+		e.Charges = charges
+	} else {
+		e.Charges = nil
+	}
+}
+
+// IsDerived returns true if the amount in the given currency was computed
+// from the other currency rather than typed by the user
+func (e *Entry) IsDerived(currency string) bool {
+	return e.Source != "" && e.Source != currency
+}
+
+// IsIncome returns true for money in. Amounts are cash flow, so the home amount's
+// sign decides, or the local amount's if the entry has no home amount.
+func (e *Entry) IsIncome() bool {
+	if e.Home != 0 {
+		return e.Home > 0
+	}
+	return e.Local > 0
+}
+
+// IsBookkeeping returns true if the entry moves money without spending or
+// earning it: a transfer between accounts, settling up with someone, or an
+// adjustment bringing an account in line with what is in it
+func (e *Entry) IsBookkeeping() bool {
+	return e.IsTransfer() || e.Category == SettlementCategory || e.Category == AdjustmentCategory
+}
+
+// FormatHome returns the home amount formatted with currency symbol
+func (e *Entry) FormatHome(pair Pair) string {
+	return e.Home.FormatSymbol(pair.Home)
+}
+
+// FormatLocal returns the local amount formatted with currency symbol
+func (e *Entry) FormatLocal(pair Pair) string {
+	return e.Local.FormatSymbol(pair.Local)
 }
 
 // DateString returns the date formatted as YYYY-MM-DD (for CSV storage)
@@ -69,10 +211,3 @@ func (e *Entry) DateDisplay() string {
 func (e *Entry) FormatDateDisplay() string {
 	return e.Date.Format("January 2, 2006")
 }
-
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
@@ -0,0 +1,42 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEntryFillRate(t *testing.T) {
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+
+	entry := NewEntry(date, "Dinner", 0, 0, "")
+	entry.SetAmount(-150000, IDR, DefaultPair, 0)
+	if !entry.NeedsRate() {
+		t.Fatal("entry converted without a rate doesn't need one")
+	}
+	if entry.Home != 0 {
+		t.Errorf("Home = %d without a rate, want 0", entry.Home)
+	}
+
+	entry.FillRate(DefaultPair, 10000)
+	if entry.NeedsRate() {
+		t.Error("entry still needs a rate after FillRate")
+	}
+	if entry.Local != -150000 || entry.Home != -1500 {
+		t.Errorf("amounts = %d, %d, want -1500, -150000", entry.Home, entry.Local)
+	}
+	if entry.Rate != 10000 {
+		t.Errorf("Rate = %v, want 10000", entry.Rate)
+	}
+
+	// A transfer keeps the amount debited if it was typed in
+	transfer := NewEntry(date, "ATM", 0, 0, "")
+	transfer.SetAmount(-1000000, IDR, DefaultPair, 0)
+	if err := transfer.SetAccount("wise", "cash"); err != nil {
+		t.Fatal(err)
+	}
+	transfer.Transfer.Home = 10500
+	transfer.FillRate(DefaultPair, 10000)
+	if transfer.Transfer.Home != 10500 || transfer.Transfer.Local != 1000000 {
+		t.Errorf("transfer = %d, %d, want 10500, 1000000", transfer.Transfer.Home, transfer.Transfer.Local)
+	}
+}
@@ -0,0 +1,276 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// amountSuffixes are the shorthand multipliers accepted after a number
+// ("45k", "50rb", "1.2jt", "2m")
+var amountSuffixes = map[string]int64{
+	"k":  1_000,
+	"rb": 1_000, // ribu
+	"jt": 1_000_000,
+	"m":  1_000_000,
+}
+
+// ParseAmount evaluates an amount the way prices are written: shorthand suffixes
+// (k and rb for thousands, jt and m for millions), + - * / (or x for times),
+// parentheses and an optional trailing currency code ("2 x 35k", "1.2jt",
+// "(45k + 30k) / 2", "20 cad"). Commas are read as thousands separators.
+// The result is in the named currency if there is one, which must belong to
+// the pair, and in cur otherwise. In a currency without decimals, a number with
+// exactly three digits after the point ("45.000") is rejected, since it is
+// likely written with a dot for thousands.
+func ParseAmount(input string, cur Currency, currencies Pair) (Money, Currency, error) {
+	tokens, err := tokenizeAmount(input)
+	if err != nil {
+		return 0, cur, err
+	}
+	if len(tokens) == 0 {
+		return 0, cur, errors.New("amount is empty")
+	}
+
+	// A trailing word other than a suffix is the currency
+	if last := tokens[len(tokens)-1]; last.kind == tokenWord && !isAmountOperatorWord(last.text) && amountSuffixes[last.text] == 0 {
+		c, ok := currencies.Currency(last.text)
+		if !ok {
+			return 0, cur, fmt.Errorf("unknown currency or suffix %q", last.text)
+		}
+		cur = c
+		tokens = tokens[:len(tokens)-1]
+	}
+	if err := checkThousandsDots(tokens, cur); err != nil {
+		return 0, cur, err
+	}
+
+	p := &amountParser{tokens: tokens}
+	value, err := p.parseSum()
+	if err != nil {
+		return 0, cur, err
+	}
+	if p.pos < len(p.tokens) {
+		return 0, cur, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return moneyFromRat(value, cur), cur, nil
+}
+
+// checkThousandsDots rejects numbers that may use a dot as the thousands
+// separator ("45.000") in a currency without decimals. A suffix makes the
+// point a decimal point ("1.500jt").
+func checkThousandsDots(tokens []amountToken, cur Currency) error {
+	if cur.Decimals != 0 {
+		return nil
+	}
+	for i, tok := range tokens {
+		if tok.kind != tokenNumber {
+			continue
+		}
+		if i+1 < len(tokens) && tokens[i+1].kind == tokenWord && amountSuffixes[tokens[i+1].text] != 0 {
+			continue
+		}
+		if _, decimals, ok := strings.Cut(tok.text, "."); ok && len(decimals) == 3 {
+			return fmt.Errorf("%q is ambiguous in %s, which has no decimals: write %s for thousands",
+				tok.text, cur.Code, strings.Replace(tok.text, ".", "", 1))
+		}
+	}
+	return nil
+}
+
+type amountTokenKind int
+
+const (
+	tokenNumber amountTokenKind = iota
+	tokenOperator
+	tokenWord
+)
+
+type amountToken struct {
+	kind  amountTokenKind
+	text  string
+	value *big.Rat // For numbers
+}
+
+// isAmountOperatorWord returns true for words that stand for an operator ("2 x 35k")
+func isAmountOperatorWord(word string) bool {
+	return word == "x"
+}
+
+// tokenizeAmount splits an amount expression into numbers, operators and words
+func tokenizeAmount(input string) ([]amountToken, error) {
+	runes := []rune(strings.ToLower(strings.ReplaceAll(input, ",", "")))
+	var tokens []amountToken
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			text := string(runes[start:i])
+			value, ok := new(big.Rat).SetString(text)
+			if !ok || strings.Count(text, ".") > 1 {
+				return nil, fmt.Errorf("invalid number %q", text)
+			}
+			tokens = append(tokens, amountToken{kind: tokenNumber, text: text, value: value})
+		case strings.ContainsRune("+-*/()×", r):
+			op := string(r)
+			if r == '×' {
+				op = "*"
+			}
+			tokens = append(tokens, amountToken{kind: tokenOperator, text: op})
+			i++
+		case unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && unicode.IsLetter(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, amountToken{kind: tokenWord, text: string(runes[start:i])})
+		default:
+			return nil, fmt.Errorf("unexpected %q", string(r))
+		}
+	}
+	return tokens, nil
+}
+
+// amountParser evaluates tokens by recursive descent:
+//
+//	sum     = product { ("+" | "-") product }
+//	product = unary { ("*" | "x" | "/") unary }
+//	unary   = ("-" | "+") unary | primary
+//	primary = (number | "(" sum ")") [suffix]
+type amountParser struct {
+	tokens []amountToken
+	pos    int
+}
+
+// peek returns the next token, or false at the end
+func (p *amountParser) peek() (amountToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return amountToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// acceptOperator consumes the next token if it is one of the operators
+func (p *amountParser) acceptOperator(ops ...string) (string, bool) {
+	tok, ok := p.peek()
+	if !ok {
+		return "", false
+	}
+	if tok.kind == tokenWord && isAmountOperatorWord(tok.text) {
+		tok.text = "*"
+	} else if tok.kind != tokenOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *amountParser) parseSum() (*big.Rat, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		if op == "+" {
+			left.Add(left, right)
+		} else {
+			left.Sub(left, right)
+		}
+	}
+}
+
+func (p *amountParser) parseProduct() (*big.Rat, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator("*", "/")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "*" {
+			left.Mul(left, right)
+		} else {
+			if right.Sign() == 0 {
+				return nil, errors.New("division by zero")
+			}
+			left.Quo(left, right)
+		}
+	}
+}
+
+func (p *amountParser) parseUnary() (*big.Rat, error) {
+	if op, ok := p.acceptOperator("-", "+"); ok {
+		value, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "-" {
+			value.Neg(value)
+		}
+		return value, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *amountParser) parsePrimary() (*big.Rat, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return nil, errors.New("amount ends early")
+	}
+
+	var value *big.Rat
+	switch {
+	case tok.kind == tokenNumber:
+		p.pos++
+		value = new(big.Rat).Set(tok.value)
+	case tok.kind == tokenOperator && tok.text == "(":
+		p.pos++
+		inner, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.acceptOperator(")"); !ok {
+			return nil, errors.New("missing )")
+		}
+		value = inner
+	default:
+		return nil, fmt.Errorf("unexpected %q", tok.text)
+	}
+
+	// Shorthand multiplier ("45k")
+	if next, ok := p.peek(); ok && next.kind == tokenWord {
+		if multiplier, ok := amountSuffixes[next.text]; ok {
+			p.pos++
+			value.Mul(value, new(big.Rat).SetInt64(multiplier))
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,97 @@
+package ledger
+
+import "testing"
+
+func TestParseAmountThousandsDot(t *testing.T) {
+	for _, in := range []string{"45.000", "1.500 + 2k", "(12.500) / 2", "45.000 idr"} {
+		if m, _, err := ParseAmount(in, IDR, DefaultPair); err == nil {
+			t.Errorf("ParseAmount(%q) = %d, want an error", in, m)
+		}
+	}
+	if _, _, err := ParseAmount("45.000 idr", CAD, DefaultPair); err == nil {
+		t.Error("ParseAmount read 45.000 IDR typed with a CAD default")
+	}
+
+	tests := []struct {
+		in   string
+		cur  Currency
+		want Money
+	}{
+		{"45000", IDR, 45000},
+		{"45,000", IDR, 45000},
+		{"1.500jt", IDR, 1500000},
+		{"2.5k", IDR, 2500},
+		{"45.000", CAD, 4500},
+		{"45.000 cad", IDR, 4500},
+	}
+	for _, tt := range tests {
+		m, _, err := ParseAmount(tt.in, tt.cur, DefaultPair)
+		if err != nil {
+			t.Errorf("ParseAmount(%q, %s): %v", tt.in, tt.cur.Code, err)
+			continue
+		}
+		if m != tt.want {
+			t.Errorf("ParseAmount(%q, %s) = %d, want %d", tt.in, tt.cur.Code, m, tt.want)
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in      string
+		cur     Currency
+		want    Money
+		wantCur Currency
+	}{
+		{"45k", IDR, 45000, IDR},
+		{"50rb", IDR, 50000, IDR},
+		{"1.2jt", IDR, 1200000, IDR},
+		{"2m", IDR, 2000000, IDR},
+		{"2 x 35k", IDR, 70000, IDR},
+		{"2x35k", IDR, 70000, IDR},
+		{"3 × 10k", IDR, 30000, IDR},
+		{"10k + 2 * 5k", IDR, 20000, IDR},
+		{"(45k + 30k) / 2", IDR, 37500, IDR},
+		{"(10 + 5)k", IDR, 15000, IDR},
+		{"-(10k - 2k)", IDR, -8000, IDR},
+		{"100 / 3", CAD, 3333, CAD},
+		{"-100 / 3", CAD, -3333, CAD},
+		{"10 / 4", IDR, 3, IDR},
+		{"20 cad", IDR, 2000, CAD},
+		{"20 CAD", IDR, 2000, CAD},
+		{"45k idr", CAD, 45000, IDR},
+	}
+	for _, tt := range tests {
+		got, cur, err := ParseAmount(tt.in, tt.cur, DefaultPair)
+		if err != nil {
+			t.Errorf("ParseAmount(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want || cur.Code != tt.wantCur.Code {
+			t.Errorf("ParseAmount(%q) = %d %s, want %d %s", tt.in, got, cur.Code, tt.want, tt.wantCur.Code)
+		}
+	}
+}
+
+func TestParseAmountErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"10 / 0",
+		"10k / (5 - 5)",
+		"45q",    // Unknown suffix
+		"20 eur", // Not in the ledger's pair
+		"(10 + 5",
+		"10 +",
+		"10 10",
+		"1.2.3",
+		"10 $",
+	} {
+		if got, _, err := ParseAmount(in, IDR, DefaultPair); err == nil {
+			t.Errorf("ParseAmount(%q) = %d, want an error", in, got)
+		}
+	}
+
+	if _, _, err := ParseAmount("10 / 0", IDR, DefaultPair); err == nil || err.Error() != "division by zero" {
+		t.Errorf("ParseAmount(\"10 / 0\") error = %v, want division by zero", err)
+	}
+}
@@ -0,0 +1,150 @@
+package ledger
+
+import (
+	"math/big"
+	"strings"
+	"time"
+)
+
+// CashBatch is money that came into a local-currency account in one go, such as
+// the cash from an ATM withdrawal, with what it cost in the home currency.
+// Spending from the account uses up its batches first in, first out.
+type CashBatch struct {
+	Date    time.Time
+	Account string
+	Local   Money // Amount received
+	Home    Money // What it cost, fees and markup included (0 if unknown)
+	Left    Money // Amount not spent yet
+
+	taken Money // Cost of the amount spent so far
+}
+
+// EffectiveRate returns the local units received per home unit paid, or 0 if the cost is unknown
+func (b CashBatch) EffectiveRate(currencies Pair) float64 {
+	if b.Home == 0 {
+		return 0
+	}
+	return b.Local.Float(currencies.Local) / b.Home.Float(currencies.Home)
+}
+
+// CashValue is what a cash expense cost in the home currency at the market rate
+// and at the effective rates of the cash it was paid with (both positive)
+type CashValue struct {
+	Market    Money
+	Effective Money
+}
+
+// FXCost returns what the exchange cost on top of the market rate
+func (v CashValue) FXCost() Money {
+	return v.Effective - v.Market
+}
+
+// TransferFXCost returns what a transfer from a home-currency account cost on top
+// of the market rate: the amount debited minus the amount received at the entry's
+// rate. Returns false if the entry isn't a transfer or has no rate.
+func (e *Entry) TransferFXCost(currencies Pair) (Money, bool) {
+	t := e.Transfer
+	if t == nil || e.Rate == 0 || t.Home == 0 {
+		return 0, false
+	}
+	return t.Home - t.Local.DivRate(currencies.Local, currencies.Home, e.Rate), true
+}
+
+// ValueCash follows the local-currency accounts through the entries, oldest
+// first, and values each expense paid from them at the effective rates of the
+// cash it used. A transfer into one of these accounts starts a batch costing
+// what was paid for it (the amount debited from a home-currency account, or the
+// cost of the cash moved from another local one). Other money coming in, and
+// the opening balance, is valued at the market rate. Returns the values of the
+// expenses by entry ID and every batch with what is left of it.
+func ValueCash(accounts []Account, entries []*Entry, currencies Pair) (map[string]CashValue, []*CashBatch) {
+	queues := make(map[string][]*CashBatch)
+	var batches []*CashBatch
+	addBatch := func(date time.Time, account string, local, home Money) {
+		batch := &CashBatch{Date: date, Account: account, Local: local, Home: home, Left: local}
+		key := strings.ToLower(account)
+		queues[key] = append(queues[key], batch)
+		batches = append(batches, batch)
+	}
+	isCash := func(name string) bool {
+		a, ok := FindAccount(accounts, name)
+		return ok && a.Currency.Code == currencies.Local.Code
+	}
+
+	for _, a := range accounts {
+		if a.Currency.Code == currencies.Local.Code && a.Opening > 0 {
+			addBatch(time.Time{}, a.Name, a.Opening, 0)
+		}
+	}
+
+	values := make(map[string]CashValue)
+	for _, e := range entries {
+		if e.Account == "" {
+			continue
+		}
+		if isCash(e.Account) {
+			switch {
+			case e.Local > 0:
+				addBatch(e.Date, e.Account, e.Local, e.Home)
+			case e.Local < 0:
+				cost, unknown := consumeBatches(queues[strings.ToLower(e.Account)], -e.Local)
+				market := -e.Home
+				values[e.ID] = CashValue{Market: market, Effective: cost + scaleMoney(unknown, market, -e.Local)}
+			}
+		}
+
+		t := e.Transfer
+		if t == nil {
+			continue
+		}
+		cost := t.Home
+		if isCash(e.Account) {
+			// Cash moved between local accounts keeps what it cost
+			var unknown Money
+			cost, unknown = consumeBatches(queues[strings.ToLower(e.Account)], t.Local)
+			cost += scaleMoney(unknown, t.Home, t.Local)
+		}
+		if isCash(t.To) && t.Local > 0 {
+			addBatch(e.Date, t.To, t.Local, cost)
+		}
+	}
+	return values, batches
+}
+
+// consumeBatches spends an amount from the oldest batches with money left and
+// returns what that part cost, along with the amount paid from batches of unknown
+// cost or beyond the money recorded. Each take costs the batch's cost of all it
+// has spent minus what earlier takes cost, so the rounding never adds up to more
+// or less than the batch cost once it is used up.
+func consumeBatches(queue []*CashBatch, amount Money) (Money, Money) {
+	var cost, unknown Money
+	for _, batch := range queue {
+		if amount == 0 {
+			break
+		}
+		take := min(amount, batch.Left)
+		if take <= 0 {
+			continue
+		}
+		batch.Left -= take
+		amount -= take
+		if batch.Home == 0 {
+			unknown += take
+		} else {
+			taken := scaleMoney(batch.Local-batch.Left, batch.Home, batch.Local)
+			cost += taken - batch.taken
+			batch.taken = taken
+		}
+	}
+	return cost, unknown + amount
+}
+
+// scaleMoney returns m * num / den, rounded half away from zero (0 if den is 0)
+func scaleMoney(m, num, den Money) Money {
+	if den == 0 {
+		return 0
+	}
+	r := new(big.Rat).SetFrac(big.NewInt(int64(m)), big.NewInt(int64(den)))
+	r.Mul(r, new(big.Rat).SetInt64(int64(num)))
+	return Money(roundRat(r))
+}
@@ -0,0 +1,107 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+)
+
+// cashAccounts is a home-currency bank account and a local-currency wallet
+var cashAccounts = []Account{
+	{Name: "wise", Currency: CAD},
+	{Name: "cash", Currency: IDR},
+}
+
+// withdrawal moves cash into the wallet at a cost
+func withdrawal(t *testing.T, date time.Time, home, local Money) *Entry {
+	t.Helper()
+	entry := NewEntry(date, "ATM", -home, -local, "")
+	if err := entry.SetAccount("wise", "cash"); err != nil {
+		t.Fatal(err)
+	}
+	return entry
+}
+
+// cashExpense pays from the wallet, with the home amount at the market rate
+func cashExpense(date time.Time, description string, home, local Money) *Entry {
+	entry := NewEntry(date, description, -home, -local, "")
+	entry.Account = "cash"
+	return entry
+}
+
+func TestValueCashFIFO(t *testing.T) {
+	date := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	hotel := cashExpense(date.AddDate(0, 0, 2), "Hotel", 15000, 1500000)
+	dinner := cashExpense(date.AddDate(0, 0, 3), "Dinner", 5000, 500000)
+	entries := []*Entry{
+		withdrawal(t, date, 10500, 1000000),
+		withdrawal(t, date.AddDate(0, 0, 1), 11000, 1000000),
+		hotel,
+		dinner,
+	}
+
+	values, batches := ValueCash(cashAccounts, entries, DefaultPair)
+
+	// The hotel uses all of the first withdrawal and half of the second
+	if want := (CashValue{Market: 15000, Effective: 16000}); values[hotel.ID] != want {
+		t.Errorf("hotel = %+v, want %+v", values[hotel.ID], want)
+	}
+	if want := (CashValue{Market: 5000, Effective: 5500}); values[dinner.ID] != want {
+		t.Errorf("dinner = %+v, want %+v", values[dinner.ID], want)
+	}
+	if values[hotel.ID].FXCost() != 1000 {
+		t.Errorf("hotel FX cost = %d, want 1000", values[hotel.ID].FXCost())
+	}
+	if len(batches) != 2 || batches[0].Left != 0 || batches[1].Left != 0 {
+		t.Errorf("batches = %+v, want two used up", batches)
+	}
+}
+
+func TestValueCashTakesAddUpToBatchCost(t *testing.T) {
+	date := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	entries := []*Entry{withdrawal(t, date, 1000, 300000)}
+	var expenses []*Entry
+	for i := 0; i < 3; i++ {
+		expense := cashExpense(date, "Snack", 1000, 100000)
+		expenses = append(expenses, expense)
+		entries = append(entries, expense)
+	}
+
+	values, _ := ValueCash(cashAccounts, entries, DefaultPair)
+
+	// A third of the batch each costs 3.33, 3.34 and 3.33, not 3.33 three times
+	var total Money
+	for _, expense := range expenses {
+		total += values[expense.ID].Effective
+	}
+	if total != 1000 {
+		t.Errorf("expenses cost %d in total, want the batch cost 1000", total)
+	}
+}
+
+func TestValueCashUnknownCost(t *testing.T) {
+	accounts := []Account{cashAccounts[0], {Name: "cash", Currency: IDR, Opening: 200000}}
+	date := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name    string
+		expense *Entry
+		want    CashValue
+	}{
+		// The opening balance is valued at the market rate, the withdrawal at its cost
+		{"opening balance first", cashExpense(date, "Market", 3000, 300000), CashValue{Market: 3000, Effective: 3200}},
+		// Spending beyond the recorded cash is valued at the market rate too
+		{"overspending", cashExpense(date, "Market", 4000, 400000), CashValue{Market: 4000, Effective: 4200}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := []*Entry{withdrawal(t, date, 1200, 100000), tt.expense}
+			values, batches := ValueCash(accounts, entries, DefaultPair)
+			if values[tt.expense.ID] != tt.want {
+				t.Errorf("value = %+v, want %+v", values[tt.expense.ID], tt.want)
+			}
+			if len(batches) != 2 || batches[0].Home != 0 || batches[0].Left != 0 || batches[1].Left != 0 {
+				t.Errorf("batches = %+v, want the opening balance and the withdrawal used up", batches)
+			}
+		})
+	}
+}
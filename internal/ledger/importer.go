@@ -0,0 +1,499 @@
+package ledger
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"ledger-a/internal/fsutil"
+)
+
+// ImportProfilesFileName is the name of the saved statement column mappings in the data directory
+const ImportProfilesFileName = "import_profiles.json"
+
+// ImportProfile maps the columns of a bank or card statement CSV onto entries.
+// Columns are named by their header (case-insensitive) or by 1-based position.
+// Amounts are imported as the statement's cash flow: money out is negative.
+type ImportProfile struct {
+	Name              string `json:"name"`
+	Delimiter         string `json:"delimiter,omitempty"`       // Field separator ("" = comma)
+	SkipRows          int    `json:"skip_rows,omitempty"`       // Rows before the header (e.g., account details)
+	NoHeader          bool   `json:"no_header,omitempty"`       // Rows start on the first line; columns are positions
+	DateColumn        string `json:"date_column"`               // Transaction date
+	DateFormat        string `json:"date_format"`               // Go layout (e.g., "2006-01-02" or "01/02/2006")
+	DescriptionColumn string `json:"description_column"`        // Merchant or payee
+	AmountColumn      string `json:"amount_column,omitempty"`   // Signed amount
+	DebitColumn       string `json:"debit_column,omitempty"`    // Money out, for statements that split debits and credits
+	CreditColumn      string `json:"credit_column,omitempty"`   // Money in
+	CurrencyColumn    string `json:"currency_column,omitempty"` // Currency code of each row
+	Currency          string `json:"currency,omitempty"`        // Currency of every row when there is no currency column
+	Negate            bool   `json:"negate,omitempty"`          // Flip signs (e.g., card statements listing purchases as positive)
+	DecimalComma      bool   `json:"decimal_comma,omitempty"`   // Amounts are written like "1.234,56"
+	Category          string `json:"category,omitempty"`        // Category and tags for imported entries (e.g., "card #visa")
+}
+
+// Validate checks that the profile maps every field an entry needs
+func (p ImportProfile) Validate() error {
+	switch {
+	case p.Name == "":
+		return errors.New("import profile has no name")
+	case p.DateColumn == "" || p.DateFormat == "":
+		return fmt.Errorf("import profile %q needs date_column and date_format", p.Name)
+	case p.DescriptionColumn == "":
+		return fmt.Errorf("import profile %q needs description_column", p.Name)
+	case p.AmountColumn == "" && p.DebitColumn == "" && p.CreditColumn == "":
+		return fmt.Errorf("import profile %q needs amount_column or debit_column/credit_column", p.Name)
+	case p.Currency == "" && p.CurrencyColumn == "":
+		return fmt.Errorf("import profile %q needs currency or currency_column", p.Name)
+	case utf8.RuneCountInString(p.Delimiter) > 1:
+		return fmt.Errorf("import profile %q delimiter must be a single character", p.Name)
+	}
+	return nil
+}
+
+// LoadImportProfiles reads the saved import profiles from a data directory.
+// A missing file means no profiles.
+func LoadImportProfiles(dataDir string) ([]ImportProfile, error) {
+	data, err := os.ReadFile(filepath.Join(dataDir, ImportProfilesFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read import profiles: %w", err)
+	}
+
+	var profiles []ImportProfile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to parse import profiles: %w", err)
+	}
+	for _, p := range profiles {
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return profiles, nil
+}
+
+// SaveImportProfiles writes the import profiles to a data directory
+func SaveImportProfiles(dataDir string, profiles []ImportProfile) error {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(profiles, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal import profiles: %w", err)
+	}
+
+	if err := fsutil.WriteFile(filepath.Join(dataDir, ImportProfilesFileName), data, 0644); err != nil {
+		return fmt.Errorf("failed to write import profiles: %w", err)
+	}
+	return nil
+}
+
+// ImportRow is one statement row and the entry it becomes
+type ImportRow struct {
+	Line      int    // Line number in the statement
+	Entry     *Entry // nil if the row couldn't be read
+	Duplicate bool   // The day already has an entry with this description and amount
+	Err       error  // Why the row can't be imported
+	Accepted  bool   // Whether the row will be imported; new valid rows start accepted
+}
+
+// Importer turns statement rows into entries, converting each amount at the
+// rate for its date
+type Importer struct {
+	service *Service
+	profile ImportProfile
+	rateOn  func(date time.Time) float64
+}
+
+// NewImporter creates an importer for a ledger. rateOn returns the home to local
+// rate for a date, or 0 if it isn't known yet (the entry then waits for it, see
+// Entry.NeedsRate).
+func NewImporter(service *Service, profile ImportProfile, rateOn func(date time.Time) float64) *Importer {
+	return &Importer{
+		service: service,
+		profile: profile,
+		rateOn:  rateOn,
+	}
+}
+
+// importColumns holds the resolved column indexes of a statement (-1 if unmapped)
+type importColumns struct {
+	date, description, amount, debit, credit, currency int
+}
+
+// Preview reads a statement and returns its rows without changing the ledger.
+// Rows that can't be read carry an error, and rows matching an existing entry
+// are marked as duplicates; neither starts accepted.
+func (im *Importer) Preview(r io.Reader) ([]*ImportRow, error) {
+	p := im.profile
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Preambles and footers often have a different shape
+	reader.LazyQuotes = true
+	if p.Delimiter != "" {
+		reader.Comma, _ = utf8.DecodeRuneInString(p.Delimiter)
+	}
+
+	for i := 0; i < p.SkipRows; i++ {
+		if _, err := reader.Read(); err != nil {
+			return nil, fmt.Errorf("failed to skip statement rows: %w", err)
+		}
+	}
+
+	var header []string
+	if !p.NoHeader {
+		record, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read statement header: %w", err)
+		}
+		header = record
+	}
+
+	cols, err := p.resolveColumns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*ImportRow
+	dedup := newDuplicateFinder(im.service)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read statement: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := &ImportRow{Line: line}
+		row.Entry, row.Err = im.parseRow(record, cols)
+		if row.Err == nil {
+			row.Duplicate, row.Err = dedup.claim(row.Entry)
+		}
+		row.Accepted = row.Err == nil && !row.Duplicate
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Import adds the accepted rows to their days and returns how many were imported
+func (im *Importer) Import(rows []*ImportRow) (int, error) {
+	byDate := make(map[string][]*Entry)
+	var dates []time.Time
+	for _, row := range rows {
+		if !row.Accepted || row.Entry == nil {
+			continue
+		}
+		key := row.Entry.DateString()
+		if _, ok := byDate[key]; !ok {
+			dates = append(dates, row.Entry.Date)
+		}
+		byDate[key] = append(byDate[key], row.Entry)
+	}
+
+	imported := 0
+	for _, date := range dates {
+		day, err := im.service.GetDay(date)
+		if err != nil {
+			return imported, err
+		}
+		entries := byDate[date.Format(DateFormat)]
+		for _, entry := range entries {
+			day.AddEntry(entry)
+		}
+		if err := im.service.SaveDay(day); err != nil {
+			return imported, fmt.Errorf("failed to save %s: %w", date.Format(DateFormat), err)
+		}
+		imported += len(entries)
+	}
+	return imported, nil
+}
+
+// resolveColumns finds the mapped columns in the header
+func (p ImportProfile) resolveColumns(header []string) (importColumns, error) {
+	var cols importColumns
+	specs := []struct {
+		spec string
+		idx  *int
+	}{
+		{p.DateColumn, &cols.date},
+		{p.DescriptionColumn, &cols.description},
+		{p.AmountColumn, &cols.amount},
+		{p.DebitColumn, &cols.debit},
+		{p.CreditColumn, &cols.credit},
+		{p.CurrencyColumn, &cols.currency},
+	}
+
+	for _, s := range specs {
+		idx, err := findColumn(s.spec, header)
+		if err != nil {
+			return cols, err
+		}
+		*s.idx = idx
+	}
+	return cols, nil
+}
+
+// findColumn returns the index of a column named by header or 1-based position (-1 if unmapped)
+func findColumn(spec string, header []string) (int, error) {
+	if spec == "" {
+		return -1, nil
+	}
+	if n, err := strconv.Atoi(spec); err == nil {
+		if n < 1 {
+			return -1, fmt.Errorf("invalid column number %d", n)
+		}
+		return n - 1, nil
+	}
+
+	for i, name := range header {
+		// Spreadsheet exports often start with a byte order mark
+		name = strings.TrimPrefix(name, "\ufeff")
+		if strings.EqualFold(strings.TrimSpace(name), spec) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("statement has no %q column", spec)
+}
+
+// field returns a trimmed field, or "" if the column is unmapped or missing from the row
+func field(record []string, idx int) string {
+	if idx < 0 || idx >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[idx])
+}
+
+// parseRow builds an entry from a statement row
+func (im *Importer) parseRow(record []string, cols importColumns) (*Entry, error) {
+	p := im.profile
+	currencies := im.service.GetCurrencies()
+
+	dateStr := field(record, cols.date)
+	parsed, err := time.ParseInLocation(p.DateFormat, dateStr, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q", dateStr)
+	}
+	date := time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, time.Local)
+
+	description := strings.Join(strings.Fields(field(record, cols.description)), " ")
+	if description == "" {
+		return nil, errors.New("no description")
+	}
+
+	code := p.Currency
+	if cols.currency >= 0 {
+		code = field(record, cols.currency)
+	}
+	cur, ok := currencies.Currency(code)
+	if !ok {
+		return nil, fmt.Errorf("currency %q is not part of this ledger (%s)", code, currencies)
+	}
+
+	amount, err := p.parseAmount(record, cols, cur)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := NewEntry(date, description, 0, 0, "")
+	entry.SetCategoryInput(p.Category)
+	rate := im.rateOn(date)
+	if cur.Code == currencies.Home.Code {
+		entry.Home = amount
+		entry.Local = amount.MulRate(currencies.Home, currencies.Local, rate)
+	} else {
+		entry.Local = amount
+		entry.Home = amount.DivRate(currencies.Local, currencies.Home, rate)
+	}
+	entry.SetConverted(cur.Code, rate)
+	return entry, nil
+}
+
+// parseAmount reads the row's cash flow from the amount column, or from the
+// credit column minus the debit column
+func (p ImportProfile) parseAmount(record []string, cols importColumns, cur Currency) (Money, error) {
+	var amount Money
+	if cols.amount >= 0 {
+		m, err := p.parseMoney(field(record, cols.amount), cur)
+		if err != nil {
+			return 0, err
+		}
+		amount = m
+	} else {
+		debit, err := p.parseMoney(field(record, cols.debit), cur)
+		if err != nil {
+			return 0, err
+		}
+		credit, err := p.parseMoney(field(record, cols.credit), cur)
+		if err != nil {
+			return 0, err
+		}
+		amount = credit - debit.Abs()
+	}
+
+	if amount == 0 {
+		return 0, errors.New("no amount")
+	}
+	if p.Negate {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// parseMoney parses a statement amount such as "$1,234.56", "-45.00", "$-12.00",
+// "-$12.00", "12.00-", "(12.00)" or "12.00 CAD". The sign may come before or
+// after the currency or after the number. Anything around the number other than
+// a currency code or symbol makes the amount invalid; an empty cell is zero.
+func (p ImportProfile) parseMoney(s string, cur Currency) (Money, error) {
+	rest := strings.TrimSpace(s)
+	if rest == "" {
+		return 0, nil
+	}
+	invalid := fmt.Errorf("invalid amount %q", s)
+
+	parens := strings.HasPrefix(rest, "(") && strings.HasSuffix(rest, ")")
+	if parens {
+		rest = rest[1 : len(rest)-1]
+	}
+
+	var digits, marker strings.Builder
+	var signs int
+	negative := parens
+	afterNumber := false
+	endMarker := func() bool {
+		m := marker.String()
+		marker.Reset()
+		return m == "" || isCurrencyMarker(m, cur)
+	}
+	for _, r := range rest {
+		switch {
+		case r >= '0' && r <= '9' || r == '.' || r == ',':
+			if afterNumber || !endMarker() {
+				return 0, invalid
+			}
+			digits.WriteRune(r)
+		case r == '-' || r == '+' || unicode.IsSpace(r):
+			if !endMarker() {
+				return 0, invalid
+			}
+			afterNumber = digits.Len() > 0
+			if r != '-' && r != '+' {
+				continue
+			}
+			signs++
+			negative = negative || r == '-'
+		default:
+			afterNumber = digits.Len() > 0
+			marker.WriteRune(r)
+		}
+	}
+	if !endMarker() || digits.Len() == 0 || signs > 1 || parens && signs > 0 {
+		return 0, invalid
+	}
+
+	cleaned := digits.String()
+	if p.DecimalComma {
+		cleaned = strings.ReplaceAll(cleaned, ".", "")
+		cleaned = strings.ReplaceAll(cleaned, ",", ".")
+	}
+
+	m, err := ParseMoney(cleaned, cur)
+	if err != nil {
+		return 0, invalid
+	}
+	if negative {
+		m = -m
+	}
+	return m, nil
+}
+
+// isCurrencyMarker returns true if s is a currency code or symbol that may
+// surround a statement amount, such as "CAD", "$", "Rp" or "CA$"
+func isCurrencyMarker(s string, cur Currency) bool {
+	if strings.EqualFold(s, cur.Code) || s == strings.TrimSpace(cur.Symbol) {
+		return true
+	}
+	for _, known := range knownCurrencies {
+		if strings.EqualFold(s, known.Code) || s == strings.TrimSpace(known.Symbol) {
+			return true
+		}
+	}
+
+	// A dollar or other currency sign after a country prefix ("US$")
+	prefix := strings.TrimRightFunc(s, func(r rune) bool { return unicode.Is(unicode.Sc, r) })
+	if prefix == s || len(prefix) > 3 {
+		return false
+	}
+	for _, r := range prefix {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// duplicateFinder matches statement rows against the entries already in the ledger.
+// Each existing entry matches at most one row, so re-importing a statement skips
+// every row while two identical purchases on a new day are both kept.
+type duplicateFinder struct {
+	service *Service
+	days    map[string][]*Entry // Unmatched existing entries by date
+}
+
+// newDuplicateFinder creates a finder that loads each day's entries on first use
+func newDuplicateFinder(service *Service) *duplicateFinder {
+	return &duplicateFinder{
+		service: service,
+		days:    make(map[string][]*Entry),
+	}
+}
+
+// claim reports whether the entry duplicates an unmatched existing entry on its day
+func (f *duplicateFinder) claim(entry *Entry) (bool, error) {
+	key := entry.DateString()
+	existing, ok := f.days[key]
+	if !ok {
+		day, err := f.service.GetDay(entry.Date)
+		if err != nil {
+			return false, err
+		}
+		existing = append([]*Entry(nil), day.Entries...)
+	}
+
+	for i, e := range existing {
+		if sameImportedEntry(e, entry, f.service.GetCurrencies()) {
+			f.days[key] = append(existing[:i], existing[i+1:]...)
+			return true, nil
+		}
+	}
+	f.days[key] = existing
+	return false, nil
+}
+
+// sameImportedEntry compares descriptions and the amount in the row's own currency,
+// since the converted amount depends on the rate at the time of import
+func sameImportedEntry(existing, row *Entry, currencies Pair) bool {
+	if !strings.EqualFold(strings.Join(strings.Fields(existing.Description), " "), row.Description) {
+		return false
+	}
+	if row.Source == currencies.Home.Code {
+		return existing.Home == row.Home
+	}
+	return existing.Local == row.Local
+}
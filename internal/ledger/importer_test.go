@@ -0,0 +1,43 @@
+package ledger
+
+import "testing"
+
+func TestParseStatementMoney(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+	}{
+		{"", 0},
+		{"$1,234.56", 123456},
+		{"-45.00", -4500},
+		{"$-12.00", -1200},
+		{"-$12.00", -1200},
+		{"12.00-", -1200},
+		{"(12.00)", -1200},
+		{"($12.00)", -1200},
+		{"+12.00", 1200},
+		{"12.00 CAD", 1200},
+		{"CA$ 12.00", 1200},
+	}
+	for _, tt := range tests {
+		got, err := ImportProfile{}.parseMoney(tt.in, CAD)
+		if err != nil {
+			t.Errorf("parseMoney(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseMoney(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"N/A", "pending", "12.00 CR", "--12", "-12.00-", "(-12.00)", "12-34", "1.2.3", "$", "12 34"} {
+		if got, err := (ImportProfile{}).parseMoney(in, CAD); err == nil {
+			t.Errorf("parseMoney(%q) = %d, want an error", in, got)
+		}
+	}
+
+	got, err := ImportProfile{DecimalComma: true}.parseMoney("-1.234,56 €", EUR)
+	if err != nil || got != -123456 {
+		t.Errorf("parseMoney with a decimal comma = %d, %v, want -123456", got, err)
+	}
+}
@@ -0,0 +1,187 @@
+package ledger
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Currency describes how amounts in a currency are stored and displayed
+type Currency struct {
+	Code     string // ISO 4217 code (e.g., "CAD")
+	Symbol   string // Display prefix (e.g., "$" or "Rp ")
+	Decimals int    // Digits of the minor unit (2 for cents, 0 for rupiah)
+}
+
+// Common currencies
+var (
+	CAD = Currency{Code: "CAD", Symbol: "$", Decimals: 2}
+	IDR = Currency{Code: "IDR", Symbol: "Rp ", Decimals: 0}
+	USD = Currency{Code: "USD", Symbol: "$", Decimals: 2}
+	EUR = Currency{Code: "EUR", Symbol: "€", Decimals: 2}
+)
+
+// knownCurrencies holds display settings for currencies supported by the rate API.
+// Codes not listed here fall back to two decimals with the code as the symbol.
+var knownCurrencies = map[string]Currency{
+	"AUD": {Code: "AUD", Symbol: "A$", Decimals: 2},
+	"CAD": CAD,
+	"CHF": {Code: "CHF", Symbol: "CHF ", Decimals: 2},
+	"CNY": {Code: "CNY", Symbol: "¥", Decimals: 2},
+	"EUR": EUR,
+	"GBP": {Code: "GBP", Symbol: "£", Decimals: 2},
+	"HKD": {Code: "HKD", Symbol: "HK$", Decimals: 2},
+	"IDR": IDR,
+	"INR": {Code: "INR", Symbol: "₹", Decimals: 2},
+	"ISK": {Code: "ISK", Symbol: "kr ", Decimals: 0},
+	"JPY": {Code: "JPY", Symbol: "¥", Decimals: 0},
+	"KRW": {Code: "KRW", Symbol: "₩", Decimals: 0},
+	"MYR": {Code: "MYR", Symbol: "RM ", Decimals: 2},
+	"NZD": {Code: "NZD", Symbol: "NZ$", Decimals: 2},
+	"PHP": {Code: "PHP", Symbol: "₱", Decimals: 2},
+	"SGD": {Code: "SGD", Symbol: "S$", Decimals: 2},
+	"THB": {Code: "THB", Symbol: "฿", Decimals: 2},
+	"USD": USD,
+}
+
+// LookupCurrency returns the currency for an ISO 4217 code (case-insensitive)
+func LookupCurrency(code string) Currency {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if cur, ok := knownCurrencies[code]; ok {
+		return cur
+	}
+	return Currency{Code: code, Symbol: code + " ", Decimals: 2}
+}
+
+// scale returns the number of minor units in one major unit
+func (c Currency) scale() int64 {
+	scale := int64(1)
+	for i := 0; i < c.Decimals; i++ {
+		scale *= 10
+	}
+	return scale
+}
+
+// Money is an exact amount in a currency's minor units (e.g., cents for CAD, rupiah for IDR).
+// Sums of Money never drift; rounding only happens when parsing or converting.
+//
+// Rounding rule: whenever a value has more precision than the currency's minor unit
+// (typed input, legacy float values, currency conversion), it is rounded half away
+// from zero, so 0.005 CAD becomes 0.01 and -0.005 CAD becomes -0.01.
+type Money int64
+
+// ParseMoney parses a decimal string such as "1234.56", "-45000" or "1,234.5" exactly
+func ParseMoney(s string, cur Currency) (Money, error) {
+	cleaned := strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	if cleaned == "" {
+		return 0, nil
+	}
+
+	r, ok := new(big.Rat).SetString(cleaned)
+	if !ok {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	return moneyFromRat(r, cur), nil
+}
+
+// MoneyFromFloat converts a float amount in major units, rounding to the minor unit
+func MoneyFromFloat(f float64, cur Currency) Money {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'f', -1, 64))
+	if !ok {
+		return 0
+	}
+	return moneyFromRat(r, cur)
+}
+
+// moneyFromRat scales a major-unit value to minor units and rounds half away from zero
+func moneyFromRat(r *big.Rat, cur Currency) Money {
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt64(cur.scale()))
+	return Money(roundRat(scaled))
+}
+
+// roundRat rounds a rational half away from zero
+func roundRat(r *big.Rat) int64 {
+	num := new(big.Int).Abs(r.Num())
+	den := r.Denom()
+
+	// floor(|r| + 1/2) = floor((2|num| + den) / 2den)
+	twice := new(big.Int).Mul(num, big.NewInt(2))
+	twice.Add(twice, den)
+	q := new(big.Int).Quo(twice, new(big.Int).Mul(den, big.NewInt(2)))
+
+	if r.Sign() < 0 {
+		q.Neg(q)
+	}
+	return q.Int64()
+}
+
+// rateRat converts a float exchange rate to the exact decimal it prints as
+func rateRat(rate float64) *big.Rat {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(rate, 'f', -1, 64))
+	if !ok {
+		return new(big.Rat)
+	}
+	return r
+}
+
+// majorRat returns the amount as an exact rational in major units
+func (m Money) majorRat(cur Currency) *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(int64(m)), big.NewInt(cur.scale()))
+}
+
+// MulRate converts an amount in from into to, where rate is the number of to units
+// per one from unit (e.g., CAD to IDR with 1 CAD = 11800 IDR)
+func (m Money) MulRate(from, to Currency, rate float64) Money {
+	r := new(big.Rat).Mul(m.majorRat(from), rateRat(rate))
+	return moneyFromRat(r, to)
+}
+
+// DivRate converts an amount in from into to, where rate is the number of from units
+// per one to unit (e.g., IDR to CAD with 1 CAD = 11800 IDR)
+func (m Money) DivRate(from, to Currency, rate float64) Money {
+	rr := rateRat(rate)
+	if rr.Sign() == 0 {
+		return 0
+	}
+	r := new(big.Rat).Quo(m.majorRat(from), rr)
+	return moneyFromRat(r, to)
+}
+
+// Float returns the amount in major units (for display math only, never for sums)
+func (m Money) Float(cur Currency) float64 {
+	f, _ := m.majorRat(cur).Float64()
+	return f
+}
+
+// Format returns the amount as a plain decimal string (e.g., "-12.30" or "45000")
+func (m Money) Format(cur Currency) string {
+	sign := ""
+	v := int64(m)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+
+	scale := cur.scale()
+	if cur.Decimals == 0 {
+		return sign + strconv.FormatInt(v, 10)
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, v/scale, cur.Decimals, v%scale)
+}
+
+// FormatSymbol returns the amount with the currency symbol (e.g., "-$12.30" or "Rp 45000")
+func (m Money) FormatSymbol(cur Currency) string {
+	if m >= 0 {
+		return cur.Symbol + m.Format(cur)
+	}
+	return "-" + cur.Symbol + (-m).Format(cur)
+}
+
+// Abs returns the absolute value of the amount
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
@@ -0,0 +1,161 @@
+package ledger
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// jpy is a second currency without decimals
+var jpy = LookupCurrency("JPY")
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in   string
+		cur  Currency
+		want Money
+	}{
+		{"1234.56", CAD, 123456},
+		{"-45000", IDR, -45000},
+		{"1,234.5", CAD, 123450},
+		{" 12 ", CAD, 1200},
+		{"", CAD, 0},
+		{"0.005", CAD, 1},
+		{"-0.005", CAD, -1},
+		{"0.0049", CAD, 0},
+		{"-0.0049", CAD, 0},
+		{"2.5", IDR, 3},
+		{"-2.5", IDR, -3},
+		{"1.4999", IDR, 1},
+		{"12.345", jpy, 12},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in, tt.cur)
+		if err != nil {
+			t.Errorf("ParseMoney(%q, %s): %v", tt.in, tt.cur.Code, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMoney(%q, %s) = %d, want %d", tt.in, tt.cur.Code, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"abc", "12..5", "$12", "1.2.3"} {
+		if _, err := ParseMoney(in, CAD); err == nil {
+			t.Errorf("ParseMoney(%q) succeeded, want an error", in)
+		}
+	}
+}
+
+func TestMoneyFromFloat(t *testing.T) {
+	tests := []struct {
+		in   float64
+		cur  Currency
+		want Money
+	}{
+		{0.1 + 0.2, CAD, 30},
+		{-12.345, CAD, -1235},
+		{12.344999, CAD, 1234},
+		{45000.5, IDR, 45001},
+		{-45000.5, IDR, -45001},
+	}
+	for _, tt := range tests {
+		if got := MoneyFromFloat(tt.in, tt.cur); got != tt.want {
+			t.Errorf("MoneyFromFloat(%v, %s) = %d, want %d", tt.in, tt.cur.Code, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyConversion(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   Money
+		from, to Currency
+		rate     float64
+		mul      bool
+		want     Money
+	}{
+		{"cents to rupiah", 350, CAD, IDR, 11800, true, 41300},
+		{"one cent to rupiah", -1, CAD, IDR, 11800, true, -118},
+		{"cents to cents", 1000, CAD, USD, 0.73, true, 730},
+		{"half cent rounds up", 5, CAD, USD, 0.1, true, 1},
+		{"negative half cent rounds down", -5, CAD, USD, 0.1, true, -1},
+		{"rupiah to yen rounds half up", 1000, IDR, jpy, 0.0095, true, 10},
+		{"negative rupiah to yen", -1000, IDR, jpy, 0.0095, true, -10},
+		{"rupiah to cents", 45000, IDR, CAD, 11800, false, 381},
+		{"negative rupiah to cents", -45000, IDR, CAD, 11800, false, -381},
+		{"half cent from rupiah", 59, IDR, CAD, 11800, false, 1},
+		{"negative half cent from rupiah", -59, IDR, CAD, 11800, false, -1},
+		{"cents to rupiah by division", 350, CAD, IDR, 0.0001, false, 35000},
+		{"no rate", 45000, IDR, CAD, 0, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			if tt.mul {
+				got = tt.amount.MulRate(tt.from, tt.to, tt.rate)
+			} else {
+				got = tt.amount.DivRate(tt.from, tt.to, tt.rate)
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyFormat(t *testing.T) {
+	tests := []struct {
+		amount     Money
+		cur        Currency
+		want       string
+		wantSymbol string
+	}{
+		{-1230, CAD, "-12.30", "-$12.30"},
+		{5, CAD, "0.05", "$0.05"},
+		{-5, CAD, "-0.05", "-$0.05"},
+		{0, CAD, "0.00", "$0.00"},
+		{45000, IDR, "45000", "Rp 45000"},
+		{-45000, IDR, "-45000", "-Rp 45000"},
+		{0, IDR, "0", "Rp 0"},
+		{-1500, jpy, "-1500", "-¥1500"},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.Format(tt.cur); got != tt.want {
+			t.Errorf("Format(%d %s) = %q, want %q", tt.amount, tt.cur.Code, got, tt.want)
+		}
+		if got := tt.amount.FormatSymbol(tt.cur); got != tt.wantSymbol {
+			t.Errorf("FormatSymbol(%d %s) = %q, want %q", tt.amount, tt.cur.Code, got, tt.wantSymbol)
+		}
+	}
+}
+
+func TestLoadLegacyFloatAmounts(t *testing.T) {
+	m := NewCSVManagerWithDir(t.TempDir())
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	if err := m.EnsureDayDir(date); err != nil {
+		t.Fatal(err)
+	}
+	// Older versions wrote the amounts with %.2f and %.0f
+	legacy := "date,description,cad,idr,screen_time\n" +
+		"2024-03-15,Dinner,-12.35,-123500,\n" +
+		"2024-03-15,Refund,4.50,45000,\n" +
+		"2024-03-15,Rounded away,-0.00,-0,\n"
+	if err := os.WriteFile(m.GetFilePath(date), []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	day, err := m.LoadDay(date)
+	if err != nil {
+		t.Fatalf("LoadDay: %v", err)
+	}
+	want := [][2]Money{{-1235, -123500}, {450, 45000}, {0, 0}}
+	if len(day.Entries) != len(want) {
+		t.Fatalf("loaded %d entries, want %d", len(day.Entries), len(want))
+	}
+	for i, entry := range day.Entries {
+		if entry.Home != want[i][0] || entry.Local != want[i][1] {
+			t.Errorf("%s = %d, %d, want %d, %d", entry.Description, entry.Home, entry.Local, want[i][0], want[i][1])
+		}
+	}
+}
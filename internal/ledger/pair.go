@@ -0,0 +1,201 @@
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ledger-a/internal/fsutil"
+)
+
+// ConfigFileName is the name of the per-ledger currency config in the data directory
+const ConfigFileName = "ledger.json"
+
+// Pair is the currency configuration of a ledger: the home currency the card is
+// billed in and the local currency prices are quoted in
+type Pair struct {
+	Home  Currency
+	Local Currency
+}
+
+// DefaultPair is used by ledgers without a config file, so existing data loads as CAD/IDR
+var DefaultPair = Pair{Home: CAD, Local: IDR}
+
+// pairConfig is the on-disk form of a Pair
+type pairConfig struct {
+	HomeCurrency  string `json:"home_currency"`
+	LocalCurrency string `json:"local_currency"`
+}
+
+// NewPair builds a pair from two ISO 4217 codes (e.g., "USD", "THB")
+func NewPair(home, local string) (Pair, error) {
+	homeCur := LookupCurrency(home)
+	localCur := LookupCurrency(local)
+
+	if !isCurrencyCode(homeCur.Code) {
+		return Pair{}, fmt.Errorf("invalid home currency %q", home)
+	}
+	if !isCurrencyCode(localCur.Code) {
+		return Pair{}, fmt.Errorf("invalid local currency %q", local)
+	}
+	if homeCur.Code == localCur.Code {
+		return Pair{}, fmt.Errorf("home and local currency are both %s", homeCur.Code)
+	}
+
+	return Pair{Home: homeCur, Local: localCur}, nil
+}
+
+// isCurrencyCode checks for a three-letter uppercase code
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the pair as "HOME/LOCAL" (e.g., "CAD/IDR")
+func (p Pair) String() string {
+	return p.Home.Code + "/" + p.Local.Code
+}
+
+// Currency returns the pair's currency with the given code, and whether it belongs to the pair
+func (p Pair) Currency(code string) (Currency, bool) {
+	switch strings.ToUpper(code) {
+	case p.Home.Code:
+		return p.Home, true
+	case p.Local.Code:
+		return p.Local, true
+	}
+	return Currency{}, false
+}
+
+// LoadPair reads the currency config from a data directory.
+// Ledgers without a config file use DefaultPair.
+func LoadPair(dataDir string) (Pair, error) {
+	return LoadPairWithDefault(dataDir, DefaultPair)
+}
+
+// LoadPairWithDefault reads the currency config from a data directory,
+// returning fallback if the directory has no config file
+func LoadPairWithDefault(dataDir string, fallback Pair) (Pair, error) {
+	data, err := os.ReadFile(filepath.Join(dataDir, ConfigFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fallback, nil
+		}
+		return fallback, fmt.Errorf("failed to read ledger config: %w", err)
+	}
+
+	var cfg pairConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fallback, fmt.Errorf("failed to parse ledger config: %w", err)
+	}
+
+	pair, err := NewPair(cfg.HomeCurrency, cfg.LocalCurrency)
+	if err != nil {
+		return fallback, fmt.Errorf("invalid ledger config: %w", err)
+	}
+	return pair, nil
+}
+
+// OpenPair returns the currency pair of the ledger in a data directory, making sure
+// the directory's files are read in the currencies they were written in. A
+// directory with a config file uses it. One without (a new ledger, or one written
+// before the config file existed) gets the configured pair written to it, unless
+// its day files were written in other currencies: reading them with the wrong
+// pair would drop their amounts on the next save, so that is an error.
+func OpenPair(dataDir string, configured Pair) (Pair, error) {
+	pair, saved, err := resolvePair(dataDir, configured)
+	if err != nil || saved {
+		return pair, err
+	}
+	if err := SavePair(dataDir, pair); err != nil {
+		return Pair{}, err
+	}
+	return pair, nil
+}
+
+// ResolvePair returns the currency pair OpenPair would without writing the config
+// file, for reading a ledger without changing its data directory
+func ResolvePair(dataDir string, configured Pair) (Pair, error) {
+	pair, _, err := resolvePair(dataDir, configured)
+	return pair, err
+}
+
+// resolvePair returns the pair of the ledger in a data directory and whether it
+// comes from the directory's config file
+func resolvePair(dataDir string, configured Pair) (Pair, bool, error) {
+	pair, err := LoadPairWithDefault(dataDir, Pair{})
+	if err != nil {
+		return Pair{}, false, err
+	}
+	if pair.Home.Code != "" {
+		return pair, true, nil
+	}
+
+	written, ok, err := pairFromDayFiles(dataDir)
+	if err != nil {
+		return Pair{}, false, err
+	}
+	if ok && written.String() != configured.String() {
+		return Pair{}, false, fmt.Errorf("%s holds a %s ledger but the currencies are set to %s; set home_currency and local_currency to match or use another data directory", dataDir, written, configured)
+	}
+	return configured, false, nil
+}
+
+// pairFromDayFiles returns the currencies of the amount columns in the first day
+// file of a data directory, which follow the description column in every version
+// of the file. Returns false if the directory has no day files.
+func pairFromDayFiles(dataDir string) (Pair, bool, error) {
+	m := NewCSVManagerWithDir(dataDir)
+	dates, err := m.ListAvailableDates()
+	if err != nil {
+		return Pair{}, false, err
+	}
+
+	for _, date := range dates {
+		header, err := m.readHeader(date)
+		if err != nil {
+			return Pair{}, false, err
+		}
+		i, ok := parseCSVHeader(header)["description"]
+		if !ok || i+2 >= len(header) {
+			continue
+		}
+		pair, err := NewPair(header[i+1], header[i+2])
+		if err != nil {
+			return Pair{}, false, fmt.Errorf("failed to read the currencies of %s: %w", m.GetFilePath(date), err)
+		}
+		return pair, true, nil
+	}
+	return Pair{}, false, nil
+}
+
+// SavePair writes the currency config to a data directory.
+// A ledger's currencies should not change once it has entries; start a new data
+// directory for a trip with a different pair.
+func SavePair(dataDir string, pair Pair) error {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(pairConfig{
+		HomeCurrency:  pair.Home.Code,
+		LocalCurrency: pair.Local.Code,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal ledger config: %w", err)
+	}
+
+	if err := fsutil.WriteFile(filepath.Join(dataDir, ConfigFileName), data, 0644); err != nil {
+		return fmt.Errorf("failed to write ledger config: %w", err)
+	}
+	return nil
+}
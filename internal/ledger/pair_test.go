@@ -0,0 +1,75 @@
+package ledger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOpenPairWritesConfiguredPair(t *testing.T) {
+	dataDir := t.TempDir()
+	usdthb, err := NewPair("USD", "THB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pair, err := OpenPair(dataDir, usdthb)
+	if err != nil {
+		t.Fatalf("OpenPair: %v", err)
+	}
+	if pair != usdthb {
+		t.Errorf("OpenPair = %s, want USD/THB", pair)
+	}
+
+	// The directory keeps its pair when the default changes
+	pair, err = OpenPair(dataDir, DefaultPair)
+	if err != nil {
+		t.Fatalf("OpenPair after saving: %v", err)
+	}
+	if pair != usdthb {
+		t.Errorf("OpenPair after saving = %s, want USD/THB", pair)
+	}
+}
+
+func TestOpenPairLegacyDirectory(t *testing.T) {
+	dataDir := t.TempDir()
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	day := NewDay(date)
+	day.AddEntry(NewEntry(date, "Coffee", -350, -35000, ""))
+	if err := NewCSVManagerWithDir(dataDir).SaveDay(day); err != nil {
+		t.Fatal(err)
+	}
+	usdthb, err := NewPair("USD", "THB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := OpenPair(dataDir, usdthb); err == nil {
+		t.Fatal("OpenPair read a CAD/IDR ledger as USD/THB")
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, ConfigFileName)); err == nil {
+		t.Error("a mismatched pair was saved")
+	}
+
+	pair, err := OpenPair(dataDir, DefaultPair)
+	if err != nil {
+		t.Fatalf("OpenPair: %v", err)
+	}
+	if pair != DefaultPair {
+		t.Errorf("OpenPair = %s, want CAD/IDR", pair)
+	}
+	if saved, err := LoadPairWithDefault(dataDir, Pair{}); err != nil || saved != DefaultPair {
+		t.Errorf("saved pair = %s, %v, want CAD/IDR", saved, err)
+	}
+}
+
+func TestNewServiceWithDirReportsConfigErrors(t *testing.T) {
+	dataDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dataDir, ConfigFileName), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewServiceWithDir(dataDir); err == nil {
+		t.Error("NewServiceWithDir ignored a damaged ledger config")
+	}
+}
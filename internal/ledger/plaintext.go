@@ -0,0 +1,366 @@
+package ledger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Accounts used in plain-text accounting exports. Spending goes to an expense
+// account named after the entry's category and money in to an income account,
+// against an asset account named after the account the entry was paid from
+// (FundingAccount for entries without one). What people owe you for bills you
+// shared goes to a receivable account named after them, and what you owe them
+// to a liability account.
+const (
+	FundingAccount  = "Assets:Cash"
+	AssetsRoot      = "Assets"
+	ReceivableRoot  = "Assets:Receivable"
+	LiabilitiesRoot = "Liabilities"
+	ExpensesRoot    = "Expenses"
+	IncomeRoot      = "Income"
+)
+
+// plainPosting is one leg of a plain-text accounting transaction
+type plainPosting struct {
+	account string
+	amount  string // e.g., "3.81 CAD @ 11800 IDR"
+}
+
+// plainTransaction is an entry in the shape both hledger and Beancount expect
+type plainTransaction struct {
+	entry    *Entry
+	postings []plainPosting // Empty if the entry is left out of the export
+}
+
+// newPlainTransaction turns an entry into a balanced transaction between the
+// entry's category and the account it was paid from. Transfers move money
+// between two asset accounts, and shared bills and settlements involve the
+// people they were shared with.
+func newPlainTransaction(entry *Entry, currencies Pair, accounts []Account) plainTransaction {
+	switch {
+	case entry.Transfer != nil:
+		return newPlainTransfer(entry, currencies, accounts)
+	case entry.Split != nil && entry.Category == SettlementCategory && len(entry.Split.People) > 0:
+		return newPlainSettlement(entry, currencies)
+	case entry.Split != nil && !entry.IsIncome():
+		return newPlainShared(entry, currencies)
+	}
+
+	// Cash flow is negative for spending, so the expense posting flips its sign
+	root := ExpensesRoot
+	if entry.IsIncome() {
+		root = IncomeRoot
+	}
+	priced, _ := plainAmount(entry, -entry.Home, -entry.Local, currencies)
+	_, funding := plainAmount(entry, entry.Home, entry.Local, currencies)
+	return plainTransaction{
+		entry: entry,
+		postings: []plainPosting{
+			{account: root + ":" + accountName(entry.CategoryOrDefault()), amount: priced},
+			{account: assetAccount(entry.Account), amount: funding},
+		},
+	}
+}
+
+// plainAmount formats an amount of an entry two ways: in the currency the entry
+// was typed in, priced at the stored rate ("@"), and as the stored converted
+// amount. Postings using one and the other balance to within the rounding of
+// the conversion.
+func plainAmount(entry *Entry, home, local Money, currencies Pair) (string, string) {
+	h, l := currencies.Home, currencies.Local
+	switch {
+	case entry.Rate > 0 && entry.Source == h.Code:
+		return commodity(home, h) + " @ " + formatPrice(entry.Rate) + " " + l.Code, commodity(local, l)
+	case entry.Rate > 0 && entry.Source == l.Code:
+		return commodity(local, l) + " @ " + formatPrice(1/entry.Rate) + " " + h.Code, commodity(home, h)
+	case home != 0 && local != 0:
+		// No stored rate (older entries): price with the total instead
+		return commodity(local, l) + " @@ " + commodity(home.Abs(), h), commodity(home, h)
+	case home != 0:
+		return commodity(home, h), commodity(home, h)
+	}
+	return commodity(local, l), commodity(local, l)
+}
+
+// newPlainShared posts a shared bill. Only your share is an expense: the other
+// people's parts of a bill you paid are owed to you, and your share of a bill
+// someone else paid is owed to them. A bill someone else paid that you had no
+// share of gets no postings.
+func newPlainShared(entry *Entry, currencies Pair) plainTransaction {
+	tx := plainTransaction{entry: entry}
+	paidByMe := IsMe(entry.Payer)
+	billHome, billLocal := entry.Bill()
+	homeParts, localParts := entry.Split.Allocate(billHome), entry.Split.Allocate(billLocal)
+
+	var mineHome, mineLocal Money
+	var owed []plainPosting
+	for i, p := range entry.Split.People {
+		switch {
+		case IsMe(p.Name):
+			mineHome, mineLocal = homeParts[i], localParts[i]
+		case paidByMe:
+			priced, _ := plainAmount(entry, homeParts[i], localParts[i], currencies)
+			owed = append(owed, plainPosting{account: personAccount(ReceivableRoot, p.Name), amount: priced})
+		}
+	}
+	if !paidByMe && mineHome == 0 && mineLocal == 0 {
+		return tx
+	}
+
+	if mineHome != 0 || mineLocal != 0 {
+		priced, _ := plainAmount(entry, mineHome, mineLocal, currencies)
+		tx.postings = append(tx.postings, plainPosting{account: ExpensesRoot + ":" + accountName(entry.CategoryOrDefault()), amount: priced})
+	}
+	tx.postings = append(tx.postings, owed...)
+	if paidByMe {
+		_, funding := plainAmount(entry, entry.Home, entry.Local, currencies)
+		tx.postings = append(tx.postings, plainPosting{account: assetAccount(entry.Account), amount: funding})
+	} else {
+		_, debt := plainAmount(entry, -mineHome, -mineLocal, currencies)
+		tx.postings = append(tx.postings, plainPosting{account: personAccount(LiabilitiesRoot, entry.Payer), amount: debt})
+	}
+	return tx
+}
+
+// newPlainSettlement posts settling up with someone: what they pay you clears
+// what they owe you, and what you pay them clears what you owe them
+func newPlainSettlement(entry *Entry, currencies Pair) plainTransaction {
+	account := personAccount(ReceivableRoot, entry.Payer)
+	if IsMe(entry.Payer) {
+		account = personAccount(LiabilitiesRoot, entry.Split.People[0].Name)
+	}
+	priced, _ := plainAmount(entry, -entry.Home, -entry.Local, currencies)
+	_, funding := plainAmount(entry, entry.Home, entry.Local, currencies)
+	return plainTransaction{
+		entry: entry,
+		postings: []plainPosting{
+			{account: account, amount: priced},
+			{account: assetAccount(entry.Account), amount: funding},
+		},
+	}
+}
+
+// personAccount returns the receivable or liability account for a person
+func personAccount(root, person string) string {
+	return root + ":" + accountName(person)
+}
+
+// newPlainTransfer posts a transfer out of one asset account and into the other,
+// each in its account's currency. Between currencies, the amount received is
+// priced at the total sent ("@@"), which includes any fees.
+func newPlainTransfer(entry *Entry, currencies Pair, accounts []Account) plainTransaction {
+	t := entry.Transfer
+	sent, sentCur := transferAmount(t, accountCurrency(accounts, entry.Account, currencies), currencies)
+	received, receivedCur := transferAmount(t, accountCurrency(accounts, t.To, currencies), currencies)
+
+	amount := commodity(received, receivedCur)
+	if receivedCur.Code != sentCur.Code {
+		amount += " @@ " + commodity(sent, sentCur)
+	}
+	return plainTransaction{
+		entry: entry,
+		postings: []plainPosting{
+			{account: assetAccount(t.To), amount: amount},
+			{account: assetAccount(entry.Account), amount: commodity(-sent, sentCur)},
+		},
+	}
+}
+
+// transferAmount returns the amount of a transfer in a currency, or in the other
+// one if it wasn't recorded in that currency
+func transferAmount(t *Transfer, cur Currency, currencies Pair) (Money, Currency) {
+	if (cur.Code == currencies.Home.Code && t.Home != 0) || t.Local == 0 {
+		return t.Home, currencies.Home
+	}
+	return t.Local, currencies.Local
+}
+
+// accountCurrency returns the currency an account is kept in; accounts that
+// aren't configured are taken to be in the local currency
+func accountCurrency(accounts []Account, name string, currencies Pair) Currency {
+	if a, ok := FindAccount(accounts, name); ok {
+		return a.Currency
+	}
+	return currencies.Local
+}
+
+// assetAccount returns the asset account for an account name, or FundingAccount if it is ""
+func assetAccount(name string) string {
+	if name == "" {
+		return FundingAccount
+	}
+	return AssetsRoot + ":" + accountName(name)
+}
+
+// commodity formats an amount with its currency code (e.g., "-45000 IDR")
+func commodity(m Money, cur Currency) string {
+	return m.Format(cur) + " " + cur.Code
+}
+
+// formatPrice prints a rate with every digit it was stored with
+func formatPrice(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', -1, 64)
+}
+
+// accountName turns a category into an account name component valid in both
+// hledger and Beancount (e.g., "eating out" becomes "Eating-Out")
+func accountName(category string) string {
+	var words []string
+	for _, word := range strings.FieldsFunc(category, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words = append(words, string(runes))
+	}
+	if len(words) == 0 {
+		return "Uncategorized"
+	}
+	return strings.Join(words, "-")
+}
+
+// plainDescription keeps a description on one line and out of comment syntax
+func plainDescription(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.NewReplacer(";", ",", "|", "/").Replace(s)
+}
+
+// writeDayComments writes the day's journal and screen time as comment lines
+// and returns true if it wrote any
+func writeDayComments(w *bufio.Writer, day *Day) bool {
+	if day.ScreenTime != "" {
+		fmt.Fprintf(w, "; screen time: %s\n", day.ScreenTime)
+	}
+	if day.Journal == "" {
+		return day.ScreenTime != ""
+	}
+	for _, line := range strings.Split(strings.TrimRight(day.Journal, "\n"), "\n") {
+		if line == "" {
+			w.WriteString(";\n")
+			continue
+		}
+		fmt.Fprintf(w, "; %s\n", line)
+	}
+	return true
+}
+
+// dayTransactions returns the transactions for a day's entries, leaving out
+// entries that have no postings
+func dayTransactions(day *Day, currencies Pair, accounts []Account) []plainTransaction {
+	var txs []plainTransaction
+	for _, entry := range day.Entries {
+		if tx := newPlainTransaction(entry, currencies, accounts); len(tx.postings) > 0 {
+			txs = append(txs, tx)
+		}
+	}
+	return txs
+}
+
+// rangeAccounts returns the accounts used by a range, sorted by name
+func rangeAccounts(dateRange *DateRange, accounts []Account) []string {
+	seen := make(map[string]bool)
+	for _, entry := range dateRange.AllEntries("") {
+		for _, p := range newPlainTransaction(entry, dateRange.Currencies, accounts).postings {
+			seen[p.account] = true
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WriteHledger writes a range as an hledger journal, which ledger-cli also reads.
+// Accounts are the ledger's accounts, which set the currency transfers post in.
+func WriteHledger(w io.Writer, dateRange *DateRange, accounts []Account) error {
+	bw := bufio.NewWriter(w)
+	currencies := dateRange.Currencies
+
+	fmt.Fprintf(bw, "; ledger-a export %s to %s\n", dateRange.Start.Format(DateFormat), dateRange.End.Format(DateFormat))
+	fmt.Fprintf(bw, "commodity %s\ncommodity %s\n", currencies.Home.Code, currencies.Local.Code)
+	for _, account := range rangeAccounts(dateRange, accounts) {
+		fmt.Fprintf(bw, "account %s\n", account)
+	}
+
+	for _, day := range dateRange.Days {
+		bw.WriteString("\n")
+		txs := dayTransactions(day, currencies, accounts)
+		if writeDayComments(bw, day) && len(txs) > 0 {
+			bw.WriteString("\n")
+		}
+
+		for i, tx := range txs {
+			entry := tx.entry
+			fmt.Fprintf(bw, "%s * %s  ; id:%s", entry.DateString(), plainDescription(entry.Description), entry.ID)
+			for _, tag := range entry.Tags {
+				fmt.Fprintf(bw, ", %s:", tag)
+			}
+			bw.WriteString("\n")
+			for _, p := range tx.postings {
+				fmt.Fprintf(bw, "    %-32s  %s\n", p.account, p.amount)
+			}
+			if i < len(txs)-1 {
+				bw.WriteString("\n")
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// WriteBeancount writes a range as a Beancount file, with transfers posted in
+// the currencies of the ledger's accounts
+func WriteBeancount(w io.Writer, dateRange *DateRange, accounts []Account) error {
+	bw := bufio.NewWriter(w)
+	currencies := dateRange.Currencies
+
+	fmt.Fprintf(bw, "; ledger-a export %s to %s\n", dateRange.Start.Format(DateFormat), dateRange.End.Format(DateFormat))
+	fmt.Fprintf(bw, "option \"operating_currency\" \"%s\"\n\n", currencies.Home.Code)
+	for _, account := range rangeAccounts(dateRange, accounts) {
+		fmt.Fprintf(bw, "%s open %s\n", dateRange.Start.Format(DateFormat), account)
+	}
+
+	for _, day := range dateRange.Days {
+		bw.WriteString("\n")
+		txs := dayTransactions(day, currencies, accounts)
+		if writeDayComments(bw, day) && len(txs) > 0 {
+			bw.WriteString("\n")
+		}
+
+		for i, tx := range txs {
+			entry := tx.entry
+			fmt.Fprintf(bw, "%s * %s", entry.DateString(), strconv.Quote(strings.Join(strings.Fields(entry.Description), " ")))
+			for _, tag := range entry.Tags {
+				fmt.Fprintf(bw, " #%s", beancountTag(tag))
+			}
+			bw.WriteString("\n")
+			fmt.Fprintf(bw, "  id: %s\n", strconv.Quote(entry.ID))
+			for _, p := range tx.postings {
+				fmt.Fprintf(bw, "  %-32s  %s\n", p.account, p.amount)
+			}
+			if i < len(txs)-1 {
+				bw.WriteString("\n")
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// beancountTag keeps the characters Beancount allows in tags
+func beancountTag(tag string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '/' || r == '.' {
+			return r
+		}
+		return '-'
+	}, tag)
+}
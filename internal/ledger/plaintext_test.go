@@ -0,0 +1,150 @@
+package ledger
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// exportAccounts are a card in the home currency and a cash wallet in the local one
+var exportAccounts = []Account{{Name: "Wise", Currency: CAD}, {Name: "Cash", Currency: IDR}}
+
+func TestPlainTransactionFundedFromAccount(t *testing.T) {
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	entry := NewEntry(date, "Dinner", 0, 0, "")
+	entry.SetAmount(-150000, IDR, DefaultPair, 10000)
+	entry.Account = "Wise"
+
+	tx := newPlainTransaction(entry, DefaultPair, exportAccounts)
+	if tx.postings[1].account != "Assets:Wise" {
+		t.Errorf("funding account = %s, want Assets:Wise", tx.postings[1].account)
+	}
+
+	entry.Account = ""
+	if tx := newPlainTransaction(entry, DefaultPair, exportAccounts); tx.postings[1].account != FundingAccount {
+		t.Errorf("funding account without an account = %s, want %s", tx.postings[1].account, FundingAccount)
+	}
+}
+
+func TestPlainTransactionTransfer(t *testing.T) {
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	withdrawal := NewEntry(date, "ATM", 0, 0, "")
+	if err := withdrawal.SetAccount("Wise", "Cash"); err != nil {
+		t.Fatal(err)
+	}
+	withdrawal.Transfer.Home = 10500
+	withdrawal.Transfer.Local = 1000000
+
+	tx := newPlainTransaction(withdrawal, DefaultPair, exportAccounts)
+	want := []plainPosting{
+		{account: "Assets:Cash", amount: "1000000 IDR @@ 105.00 CAD"},
+		{account: "Assets:Wise", amount: "-105.00 CAD"},
+	}
+	if !slices.Equal(tx.postings, want) {
+		t.Errorf("postings = %v, want %v", tx.postings, want)
+	}
+
+	// Between accounts in the same currency only one amount is posted
+	local := []Account{{Name: "Wallet", Currency: IDR}, {Name: "Cash", Currency: IDR}}
+	if err := withdrawal.SetAccount("Wallet", "Cash"); err != nil {
+		t.Fatal(err)
+	}
+	tx = newPlainTransaction(withdrawal, DefaultPair, local)
+	want = []plainPosting{
+		{account: "Assets:Cash", amount: "1000000 IDR"},
+		{account: "Assets:Wallet", amount: "-1000000 IDR"},
+	}
+	if !slices.Equal(tx.postings, want) {
+		t.Errorf("postings = %v, want %v", tx.postings, want)
+	}
+}
+
+func TestWriteHledgerOpensAssetAccounts(t *testing.T) {
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	day := NewDay(date)
+	withdrawal := NewEntry(date, "ATM", 0, 0, "")
+	if err := withdrawal.SetAccount("Wise", "Cash"); err != nil {
+		t.Fatal(err)
+	}
+	withdrawal.Transfer.Home, withdrawal.Transfer.Local = 10500, 1000000
+	day.AddEntry(withdrawal)
+	dateRange := NewDateRange(date, date)
+	dateRange.AddDay(day)
+
+	var sb strings.Builder
+	if err := WriteHledger(&sb, dateRange, exportAccounts); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"account Assets:Cash\n", "account Assets:Wise\n"} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("journal doesn't declare %q:\n%s", want, sb.String())
+		}
+	}
+	if strings.Contains(sb.String(), "Expenses:") {
+		t.Errorf("transfer exported as an expense:\n%s", sb.String())
+	}
+}
+
+// sharedDinner returns a 300,000 IDR dinner split as the input says
+func sharedDinner(t *testing.T, split string) *Entry {
+	t.Helper()
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	entry := NewEntry(date, "Dinner", 0, 0, "")
+	entry.Category = "food"
+	entry.SetAmount(-300000, IDR, DefaultPair, 10000)
+	payer, s, err := ParseSplit(split, IDR, DefaultPair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entry.SetSplit(payer, s, DefaultPair); err != nil {
+		t.Fatal(err)
+	}
+	return entry
+}
+
+func TestPlainTransactionShared(t *testing.T) {
+	tests := []struct {
+		split string
+		want  []plainPosting
+	}{
+		{"me alice", []plainPosting{
+			{account: "Expenses:Food", amount: "150000 IDR @ 0.0001 CAD"},
+			{account: "Assets:Receivable:Alice", amount: "150000 IDR @ 0.0001 CAD"},
+			{account: "Assets:Cash", amount: "-30.00 CAD"},
+		}},
+		{"bob: me alice", []plainPosting{
+			{account: "Expenses:Food", amount: "150000 IDR @ 0.0001 CAD"},
+			{account: "Liabilities:Bob", amount: "-15.00 CAD"},
+		}},
+		{"bob: alice", nil},
+	}
+	for _, tt := range tests {
+		tx := newPlainTransaction(sharedDinner(t, tt.split), DefaultPair, nil)
+		if !slices.Equal(tx.postings, tt.want) {
+			t.Errorf("%q: postings = %v, want %v", tt.split, tx.postings, tt.want)
+		}
+	}
+}
+
+func TestPlainTransactionSettlement(t *testing.T) {
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+
+	owed := NewSettlement(date, Balance{Person: "Bob", Home: 1500, Local: 150000})
+	want := []plainPosting{
+		{account: "Liabilities:Bob", amount: "150000 IDR @@ 15.00 CAD"},
+		{account: "Assets:Cash", amount: "-15.00 CAD"},
+	}
+	if tx := newPlainTransaction(owed, DefaultPair, nil); !slices.Equal(tx.postings, want) {
+		t.Errorf("paying Bob: postings = %v, want %v", tx.postings, want)
+	}
+
+	owes := NewSettlement(date, Balance{Person: "Alice", Home: -1500, Local: -150000})
+	want = []plainPosting{
+		{account: "Assets:Receivable:Alice", amount: "-150000 IDR @@ 15.00 CAD"},
+		{account: "Assets:Cash", amount: "15.00 CAD"},
+	}
+	if tx := newPlainTransaction(owes, DefaultPair, nil); !slices.Equal(tx.postings, want) {
+		t.Errorf("paid by Alice: postings = %v, want %v", tx.postings, want)
+	}
+}
@@ -0,0 +1,262 @@
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ledger-a/internal/fsutil"
+)
+
+// RecurringFileName is the name of the recurring entry rules in the data directory
+const RecurringFileName = "recurring.json"
+
+// RecurringFrequency is the unit a recurring rule repeats in
+type RecurringFrequency string
+
+const (
+	RecurDaily   RecurringFrequency = "daily"
+	RecurWeekly  RecurringFrequency = "weekly"
+	RecurMonthly RecurringFrequency = "monthly"
+)
+
+// ParseRecurringFrequency reads a frequency such as "daily", "week" or "Monthly"
+func ParseRecurringFrequency(s string) (RecurringFrequency, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "daily", "day", "days":
+		return RecurDaily, nil
+	case "weekly", "week", "weeks":
+		return RecurWeekly, nil
+	case "monthly", "month", "months":
+		return RecurMonthly, nil
+	}
+	return "", fmt.Errorf("unknown frequency %q (want daily, weekly or monthly)", s)
+}
+
+// RecurringRule adds the same entry on a schedule: every N days, weeks or months
+// from the start date, up to an optional end date. Monthly rules fall on the start
+// date's day of the month, or the month's last day if it is shorter.
+// An occurrence is added to its day the first time that day or a later one is
+// opened, and is remembered so that it's never added twice (even if deleted).
+type RecurringRule struct {
+	ID          string             `json:"id"`
+	Description string             `json:"description"`
+	Category    string             `json:"category,omitempty"` // Category and tags (e.g., "housing #villa")
+	Amount      string             `json:"amount"`             // Cash flow in Currency (negative for spending)
+	Currency    string             `json:"currency"`           // Code of one of the ledger's currencies
+	Frequency   RecurringFrequency `json:"frequency"`
+	Interval    int                `json:"interval,omitempty"` // Every N days, weeks or months (0 means 1)
+	Start       string             `json:"start"`              // First occurrence, YYYY-MM-DD
+	End         string             `json:"end,omitempty"`      // Last day an occurrence may fall on ("" = no end)
+	Paused      bool               `json:"paused,omitempty"`
+	Added       []string           `json:"added,omitempty"` // Occurrences already added to their day, YYYY-MM-DD
+}
+
+// NewRecurringRule creates a rule with a unique ID
+func NewRecurringRule() RecurringRule {
+	return RecurringRule{ID: NewEntryID(), Frequency: RecurMonthly}
+}
+
+// Validate checks that the rule has everything an occurrence needs
+func (r RecurringRule) Validate() error {
+	if strings.TrimSpace(r.Description) == "" {
+		return errors.New("recurring rule has no description")
+	}
+	if r.Currency == "" {
+		return fmt.Errorf("recurring rule %q needs a currency", r.Description)
+	}
+	if _, err := ParseMoney(r.Amount, LookupCurrency(r.Currency)); err != nil || strings.TrimSpace(r.Amount) == "" {
+		return fmt.Errorf("recurring rule %q has an invalid amount %q", r.Description, r.Amount)
+	}
+	if _, err := ParseRecurringFrequency(string(r.Frequency)); err != nil {
+		return fmt.Errorf("recurring rule %q: %w", r.Description, err)
+	}
+	if r.Interval < 0 {
+		return fmt.Errorf("recurring rule %q interval must be positive", r.Description)
+	}
+	start, err := time.Parse(DateFormat, r.Start)
+	if err != nil {
+		return fmt.Errorf("recurring rule %q has an invalid start date %q", r.Description, r.Start)
+	}
+	if r.End != "" {
+		end, err := time.Parse(DateFormat, r.End)
+		if err != nil {
+			return fmt.Errorf("recurring rule %q has an invalid end date %q", r.Description, r.End)
+		}
+		if end.Before(start) {
+			return fmt.Errorf("recurring rule %q ends before it starts", r.Description)
+		}
+	}
+	return nil
+}
+
+// interval returns the number of days, weeks or months between occurrences
+func (r RecurringRule) interval() int {
+	return max(1, r.Interval)
+}
+
+// OccursOn returns true if the schedule has an occurrence on the date
+func (r RecurringRule) OccursOn(date time.Time) bool {
+	key := date.Format(DateFormat)
+	if key < r.Start || (r.End != "" && key > r.End) {
+		return false
+	}
+	start, err := time.Parse(DateFormat, r.Start)
+	if err != nil {
+		return false
+	}
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	frequency, _ := ParseRecurringFrequency(string(r.Frequency))
+	switch frequency {
+	case RecurDaily:
+		return daysSince(start, day)%r.interval() == 0
+	case RecurWeekly:
+		return daysSince(start, day)%(7*r.interval()) == 0
+	case RecurMonthly:
+		months := (day.Year()-start.Year())*12 + int(day.Month()-start.Month())
+		lastDay := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		return months%r.interval() == 0 && day.Day() == min(start.Day(), lastDay)
+	}
+	return false
+}
+
+// daysSince counts whole days from start to day (both UTC midnights)
+func daysSince(start, day time.Time) int {
+	return int(day.Sub(start).Hours() / 24)
+}
+
+// NextOn returns the first occurrence on or after a date, and false if the
+// schedule has ended
+func (r RecurringRule) NextOn(date time.Time) (time.Time, bool) {
+	if start, err := time.ParseInLocation(DateFormat, r.Start, date.Location()); err == nil && date.Before(start) {
+		date = start
+	}
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+
+	// Occurrences are at most interval months apart
+	limit := 32 * r.interval()
+	for i := 0; i < limit; i++ {
+		if r.End != "" && date.Format(DateFormat) > r.End {
+			break
+		}
+		if r.OccursOn(date) {
+			return date, true
+		}
+		date = date.AddDate(0, 0, 1)
+	}
+	return time.Time{}, false
+}
+
+// DueThrough returns the occurrences from the rule's start through a date that
+// haven't been added yet (none while the rule is paused)
+func (r RecurringRule) DueThrough(through time.Time) []time.Time {
+	if r.Paused {
+		return nil
+	}
+	start, err := time.ParseInLocation(DateFormat, r.Start, through.Location())
+	if err != nil {
+		return nil
+	}
+	added := make(map[string]bool, len(r.Added))
+	for _, date := range r.Added {
+		added[date] = true
+	}
+
+	var due []time.Time
+	for date, ok := r.NextOn(start); ok && !date.After(through); date, ok = r.NextOn(date.AddDate(0, 0, 1)) {
+		if !added[date.Format(DateFormat)] {
+			due = append(due, date)
+		}
+	}
+	return due
+}
+
+// Schedule describes how often the rule repeats (e.g., "Monthly" or "Every 2 weeks")
+func (r RecurringRule) Schedule() string {
+	frequency, _ := ParseRecurringFrequency(string(r.Frequency))
+	name, unit := "Daily", "days"
+	switch frequency {
+	case RecurWeekly:
+		name, unit = "Weekly", "weeks"
+	case RecurMonthly:
+		name, unit = "Monthly", "months"
+	}
+	if n := r.interval(); n > 1 {
+		return fmt.Sprintf("Every %d %s", n, unit)
+	}
+	return name
+}
+
+// IsAdded returns true if the occurrence on the date was already added
+func (r RecurringRule) IsAdded(date time.Time) bool {
+	key := date.Format(DateFormat)
+	for _, added := range r.Added {
+		if added == key {
+			return true
+		}
+	}
+	return false
+}
+
+// NewEntry builds the entry for an occurrence, converting the amount at the
+// rate for the date (home to local)
+func (r RecurringRule) NewEntry(date time.Time, currencies Pair, rate float64) (*Entry, error) {
+	cur, ok := currencies.Currency(r.Currency)
+	if !ok {
+		return nil, fmt.Errorf("currency %q is not part of this ledger (%s)", r.Currency, currencies)
+	}
+	amount, err := ParseMoney(r.Amount, cur)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := NewEntry(date, r.Description, 0, 0, "")
+	entry.SetCategoryInput(r.Category)
+	entry.SetAmount(amount, cur, currencies, rate)
+	return entry, nil
+}
+
+// LoadRecurringRules reads the recurring entry rules from a data directory.
+// A missing file means no rules.
+func LoadRecurringRules(dataDir string) ([]RecurringRule, error) {
+	data, err := os.ReadFile(filepath.Join(dataDir, RecurringFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read recurring rules: %w", err)
+	}
+
+	var rules []RecurringRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse recurring rules: %w", err)
+	}
+	for _, r := range rules {
+		if err := r.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+// SaveRecurringRules writes the recurring entry rules to a data directory
+func SaveRecurringRules(dataDir string, rules []RecurringRule) error {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(rules, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal recurring rules: %w", err)
+	}
+
+	if err := fsutil.WriteFile(filepath.Join(dataDir, RecurringFileName), data, 0644); err != nil {
+		return fmt.Errorf("failed to write recurring rules: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package ledger
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// weeklyRule returns a rule adding 100,000 IDR every week from start
+func weeklyRule(description string, start time.Time) RecurringRule {
+	rule := NewRecurringRule()
+	rule.Description = description
+	rule.Amount = "-100000"
+	rule.Currency = "IDR"
+	rule.Frequency = RecurWeekly
+	rule.Start = start.Format(DateFormat)
+	return rule
+}
+
+func TestGetDayLeavesRecurringAlone(t *testing.T) {
+	s := NewServiceWithCurrencies(t.TempDir(), DefaultPair)
+	start := Today().AddDate(0, 0, -14)
+	if err := s.SaveRecurringRules([]RecurringRule{weeklyRule("Scooter", start)}); err != nil {
+		t.Fatal(err)
+	}
+
+	day, err := s.GetDay(start)
+	if err != nil {
+		t.Fatalf("GetDay: %v", err)
+	}
+	if len(day.Entries) != 0 {
+		t.Errorf("GetDay added %d entries", len(day.Entries))
+	}
+	if _, err := os.Stat(s.csvManager.GetFilePath(start)); err == nil {
+		t.Error("GetDay wrote the day")
+	}
+}
+
+func TestOpenDayAddsEarlierOccurrences(t *testing.T) {
+	s := NewServiceWithCurrencies(t.TempDir(), DefaultPair)
+	today := Today()
+	start := today.AddDate(0, 0, -14)
+	if err := s.SaveRecurringRules([]RecurringRule{weeklyRule("Scooter", start)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if pending, err := s.PendingRecurring(today); err != nil || pending != 3 {
+		t.Errorf("PendingRecurring = %d, %v, want 3", pending, err)
+	}
+
+	// Opening the middle occurrence adds it and the one before, not the later one
+	if _, err := s.OpenDay(start.AddDate(0, 0, 7)); err != nil {
+		t.Fatalf("OpenDay: %v", err)
+	}
+	dateRange, err := s.GetDateRange(start, today)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(dateRange.AllEntries("")); n != 2 {
+		t.Errorf("range has %d entries after opening the second week, want 2", n)
+	}
+	if pending, err := s.PendingRecurring(today); err != nil || pending != 1 {
+		t.Errorf("PendingRecurring = %d, %v, want 1", pending, err)
+	}
+
+	// Opening a day again adds nothing twice
+	if _, err := s.OpenDay(today.AddDate(0, 0, 3)); err != nil {
+		t.Fatalf("OpenDay: %v", err)
+	}
+	if added, err := s.AddDueRecurring(today); err != nil || added != 0 {
+		t.Errorf("AddDueRecurring = %d, %v, want nothing left to add", added, err)
+	}
+	dateRange, err = s.GetDateRange(start, today)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(dateRange.AllEntries("")); n != 3 {
+		t.Errorf("range has %d entries, want 3", n)
+	}
+}
+
+func TestAddDueRecurringReportsBrokenRules(t *testing.T) {
+	s := NewServiceWithCurrencies(t.TempDir(), DefaultPair)
+	today := Today()
+	broken := weeklyRule("Rent", today)
+	broken.Currency = "EUR"
+	if err := s.SaveRecurringRules([]RecurringRule{broken, weeklyRule("Scooter", today)}); err != nil {
+		t.Fatal(err)
+	}
+
+	added, err := s.AddDueRecurring(today)
+	if err == nil {
+		t.Error("AddDueRecurring didn't report a rule in a currency outside the ledger")
+	}
+	if added != 1 {
+		t.Errorf("AddDueRecurring added %d entries, want the working rule's 1", added)
+	}
+	day, err := s.GetDay(today)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(day.Entries) != 1 || day.Entries[0].Description != "Scooter" {
+		t.Errorf("day has %d entries, want the scooter's", len(day.Entries))
+	}
+
+	// A broken rule doesn't keep the day from opening
+	day, err = s.OpenDay(today)
+	if err == nil {
+		t.Error("OpenDay didn't report the broken rule")
+	}
+	if day == nil || len(day.Entries) != 1 {
+		t.Errorf("OpenDay = %v, want the day with the scooter's entry", day)
+	}
+}
@@ -0,0 +1,303 @@
+package ledger
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReportLargestCount is how many of the biggest expenses a report keeps
+const ReportLargestCount = 10
+
+// Sum is an amount in both of the ledger's currencies
+type Sum struct {
+	Home  Money
+	Local Money
+}
+
+// Add returns the sum of two amounts
+func (a Sum) Add(b Sum) Sum {
+	return Sum{Home: a.Home + b.Home, Local: a.Local + b.Local}
+}
+
+// Sub returns the difference of two amounts
+func (a Sum) Sub(b Sum) Sum {
+	return Sum{Home: a.Home - b.Home, Local: a.Local - b.Local}
+}
+
+// Div returns the amount divided by n, rounded to the nearest minor unit (zero if n is 0)
+func (a Sum) Div(n int) Sum {
+	if n == 0 {
+		return Sum{}
+	}
+	div := func(m Money) Money {
+		return Money(math.Round(float64(m) / float64(n)))
+	}
+	return Sum{Home: div(a.Home), Local: div(a.Local)}
+}
+
+// Totals is the money out, money in and number of entries over some days.
+// Entries are cash flow, so spending is summed from negative amounts and shown
+// as a positive amount.
+type Totals struct {
+	Spent   Sum
+	Income  Sum
+	Entries int
+	FXCost  Money // What the cash spent cost on top of the market rate (home currency)
+}
+
+// Net returns income minus spending
+func (t Totals) Net() Sum {
+	return t.Income.Sub(t.Spent)
+}
+
+// SpentAtCost returns the home-currency spending with cash expenses valued at
+// the rates their cash was bought at
+func (t Totals) SpentAtCost() Money {
+	return t.Spent.Home + t.FXCost
+}
+
+// add counts an entry as spending or income depending on its sign, taking the
+// FX cost of cash expenses from their values by entry ID
+func (t *Totals) add(entry *Entry, cashValues map[string]CashValue) {
+	t.Entries++
+	if entry.IsIncome() {
+		t.Income = t.Income.Add(Sum{Home: entry.Home, Local: entry.Local})
+		return
+	}
+	t.Spent = t.Spent.Add(Sum{Home: -entry.Home, Local: -entry.Local})
+	if value, ok := cashValues[entry.ID]; ok {
+		t.FXCost += value.FXCost()
+	}
+}
+
+// ScreenTimeStats summarizes the screen time recorded over some days
+type ScreenTimeStats struct {
+	Days    int           // Days with a screen time that could be read
+	Total   time.Duration // Sum over those days
+	Max     time.Duration // Longest day
+	MaxDate time.Time     // Date of the longest day
+}
+
+// Average returns the mean screen time of the days that recorded one
+func (s ScreenTimeStats) Average() time.Duration {
+	if s.Days == 0 {
+		return 0
+	}
+	return (s.Total / time.Duration(s.Days)).Round(time.Minute)
+}
+
+// add records a day's screen time if it can be parsed
+func (s *ScreenTimeStats) add(date time.Time, screenTime string) {
+	d, ok := ParseScreenTime(screenTime)
+	if !ok {
+		return
+	}
+	s.Days++
+	s.Total += d
+	if d > s.Max {
+		s.Max = d
+		s.MaxDate = date
+	}
+}
+
+// ParseScreenTime reads a screen time such as "3h45m", "3h 45m", "45m" or "3:45"
+func ParseScreenTime(s string) (time.Duration, bool) {
+	s = strings.ToLower(strings.Join(strings.Fields(s), ""))
+	if s == "" {
+		return 0, false
+	}
+
+	if hours, minutes, ok := strings.Cut(s, ":"); ok {
+		s = hours + "h" + minutes + "m"
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+// Period is a week or month of the report, clipped to the report's range
+type Period struct {
+	Start      time.Time
+	End        time.Time
+	Totals     Totals
+	ScreenTime ScreenTimeStats
+}
+
+// Days returns the number of calendar days in the period
+func (p Period) Days() int {
+	return daysBetween(p.Start, p.End) + 1
+}
+
+// DailyAverage returns the average spending per calendar day of the period
+func (p Period) DailyAverage() Sum {
+	return p.Totals.Spent.Div(p.Days())
+}
+
+// Weekday is the spending that fell on one day of the week
+type Weekday struct {
+	Day         time.Weekday
+	Occurrences int // How many times the weekday occurs in the range
+	Totals      Totals
+}
+
+// Average returns the average spending on this weekday
+func (w Weekday) Average() Sum {
+	return w.Totals.Spent.Div(w.Occurrences)
+}
+
+// Report is the summary of a date range: totals per week and month, the daily
+// average, the largest expenses, spending by weekday and screen time
+type Report struct {
+	Start      time.Time
+	End        time.Time
+	Currencies Pair
+	Query      string // Filter applied to the entries ("" for all)
+
+	Totals     Totals
+	ScreenTime ScreenTimeStats
+	Weeks      []Period   // Monday to Sunday
+	Months     []Period   // Calendar months
+	Weekdays   [7]Weekday // Monday first
+	Largest    []*Entry   // Biggest expenses by home amount, largest first
+}
+
+// NewReport builds a report for a date range. Only entries matching the query count
+// toward the money totals, leaving out transfers, settlements and adjustments;
+// screen time covers every day in the range. Cash expenses found in cashValues
+// (by entry ID) add their FX cost to the totals.
+func NewReport(dateRange *DateRange, query string, cashValues map[string]CashValue) *Report {
+	start := truncateDay(dateRange.Start)
+	end := truncateDay(dateRange.End)
+
+	r := &Report{
+		Start:      start,
+		End:        end,
+		Currencies: dateRange.Currencies,
+		Query:      query,
+		Weeks:      periods(start, end, weekStart, func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }),
+		Months:     periods(start, end, monthStart, func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }),
+	}
+
+	for i := range r.Weekdays {
+		r.Weekdays[i].Day = time.Weekday((i + 1) % 7)
+	}
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		r.weekday(date).Occurrences++
+	}
+
+	var expenses []*Entry
+	for _, day := range dateRange.Days {
+		date := truncateDay(day.Date)
+		week := findPeriod(r.Weeks, date)
+		month := findPeriod(r.Months, date)
+
+		if day.ScreenTime != "" {
+			r.ScreenTime.add(date, day.ScreenTime)
+			if week != nil {
+				week.ScreenTime.add(date, day.ScreenTime)
+			}
+			if month != nil {
+				month.ScreenTime.add(date, day.ScreenTime)
+			}
+		}
+
+		for _, entry := range day.Counted(query) {
+			r.Totals.add(entry, cashValues)
+			r.weekday(date).Totals.add(entry, cashValues)
+			if week != nil {
+				week.Totals.add(entry, cashValues)
+			}
+			if month != nil {
+				month.Totals.add(entry, cashValues)
+			}
+			if !entry.IsIncome() {
+				expenses = append(expenses, entry)
+			}
+		}
+	}
+
+	sort.SliceStable(expenses, func(i, j int) bool {
+		return expenses[i].Home.Abs() > expenses[j].Home.Abs()
+	})
+	if len(expenses) > ReportLargestCount {
+		expenses = expenses[:ReportLargestCount]
+	}
+	r.Largest = expenses
+
+	return r
+}
+
+// Days returns the number of calendar days in the report's range
+func (r *Report) Days() int {
+	return daysBetween(r.Start, r.End) + 1
+}
+
+// DailyAverage returns the average spending per calendar day in the range
+func (r *Report) DailyAverage() Sum {
+	return r.Totals.Spent.Div(r.Days())
+}
+
+// weekday returns the weekday bucket for a date
+func (r *Report) weekday(date time.Time) *Weekday {
+	return &r.Weekdays[(int(date.Weekday())+6)%7]
+}
+
+// periods splits start..end into consecutive periods, clipping the first and last
+func periods(start, end time.Time, periodStart func(time.Time) time.Time, next func(time.Time) time.Time) []Period {
+	var result []Period
+	for p := periodStart(start); !p.After(end); p = next(p) {
+		last := next(p).AddDate(0, 0, -1)
+		result = append(result, Period{Start: latest(p, start), End: earliest(last, end)})
+	}
+	return result
+}
+
+// findPeriod returns the period containing a date, or nil if none does
+func findPeriod(periods []Period, date time.Time) *Period {
+	for i := range periods {
+		if !date.Before(periods[i].Start) && !date.After(periods[i].End) {
+			return &periods[i]
+		}
+	}
+	return nil
+}
+
+// weekStart returns the Monday on or before a date
+func weekStart(t time.Time) time.Time {
+	return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+}
+
+// monthStart returns the first day of a date's month
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// truncateDay drops the time of day
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// daysBetween counts calendar days from a to b, ignoring daylight saving shifts
+func daysBetween(a, b time.Time) int {
+	ua := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	ub := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(ub.Sub(ua).Hours() / 24)
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,51 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReportLeavesOutBookkeeping(t *testing.T) {
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	day := NewDay(date)
+	day.AddEntry(NewEntry(date, "Dinner", -800, -80000, ""))
+
+	withdrawal := NewEntry(date, "ATM", -10500, -1000000, "")
+	if err := withdrawal.SetAccount("wise", "cash"); err != nil {
+		t.Fatal(err)
+	}
+	day.AddEntry(withdrawal)
+	day.AddEntry(NewSettlement(date, Balance{Person: "Alice", Home: -1500, Local: -150000}))
+	day.AddEntry(NewSettlement(date, Balance{Person: "Bob", Home: 2000, Local: 200000}))
+	adjustment := NewEntry(date, "Reconcile cash", -100, -10000, "")
+	adjustment.Category = AdjustmentCategory
+	day.AddEntry(adjustment)
+
+	dateRange := NewDateRange(date, date)
+	dateRange.AddDay(day)
+	r := NewReport(dateRange, "", nil)
+
+	if r.Totals.Entries != 1 {
+		t.Errorf("Entries = %d, want 1", r.Totals.Entries)
+	}
+	if want := (Sum{Home: 800, Local: 80000}); r.Totals.Spent != want {
+		t.Errorf("Spent = %v, want %v", r.Totals.Spent, want)
+	}
+	if r.Totals.Income != (Sum{}) {
+		t.Errorf("Income = %v, want nothing", r.Totals.Income)
+	}
+	if len(r.Largest) != 1 || r.Largest[0].Description != "Dinner" {
+		t.Errorf("Largest = %v, want the dinner only", r.Largest)
+	}
+
+	// The range totals and category subtotals agree with the report
+	if dateRange.TotalHome() != -800 || dateRange.TotalLocal() != -80000 {
+		t.Errorf("range totals = %d, %d, want -800, -80000", dateRange.TotalHome(), dateRange.TotalLocal())
+	}
+	if home := dateRange.FilteredTotalHome("cash"); home != 0 {
+		t.Errorf("home total of the cash entries = %d, want 0", home)
+	}
+	if categories := dateRange.CategoryTotals(""); len(categories) != 1 || categories[0].Count != 1 {
+		t.Errorf("CategoryTotals = %v, want the dinner only", categories)
+	}
+}
@@ -1,6 +1,8 @@
 package ledger
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 )
@@ -8,38 +10,285 @@ import (
 // Service provides the core business logic for ledger operations
 type Service struct {
 	csvManager *CSVManager
+	budgets    []Budget
+	accounts   []Account
+	rateOn     func(date time.Time) float64 // Home to local rate for recurring entries (nil = none)
 }
 
 // NewService creates a new ledger service
-func NewService() *Service {
-	return &Service{
-		csvManager: NewCSVManager(),
+func NewService() (*Service, error) {
+	return NewServiceWithDir(DataDir)
+}
+
+// NewServiceWithDir creates a new ledger service with a custom data directory.
+// The currencies come from the directory's config file (CAD/IDR if it has none,
+// see OpenPair).
+func NewServiceWithDir(dataDir string) (*Service, error) {
+	pair, err := OpenPair(dataDir, DefaultPair)
+	if err != nil {
+		return nil, err
 	}
+	return NewServiceWithCurrencies(dataDir, pair), nil
 }
 
-// NewServiceWithDir creates a new ledger service with a custom data directory
-func NewServiceWithDir(dataDir string) *Service {
+// NewServiceWithCurrencies creates a new ledger service with a custom data directory and currencies
+func NewServiceWithCurrencies(dataDir string, pair Pair) *Service {
+	csvManager := NewCSVManagerWithDir(dataDir)
+	csvManager.SetCurrencies(pair)
 	return &Service{
-		csvManager: NewCSVManagerWithDir(dataDir),
+		csvManager: csvManager,
 	}
 }
 
-// GetDay loads or creates a day
+// GetCurrencies returns the ledger's home and local currency
+func (s *Service) GetCurrencies() Pair {
+	return s.csvManager.GetCurrencies()
+}
+
+// SetBudgets sets the spending limits tracked for the ledger
+func (s *Service) SetBudgets(budgets []Budget) {
+	s.budgets = budgets
+}
+
+// GetBudgets returns the ledger's spending limits
+func (s *Service) GetBudgets() []Budget {
+	return s.budgets
+}
+
+// SetAccounts sets the accounts money is paid from; the first is the default for new entries
+func (s *Service) SetAccounts(accounts []Account) {
+	s.accounts = accounts
+}
+
+// GetAccounts returns the ledger's accounts
+func (s *Service) GetAccounts() []Account {
+	return s.accounts
+}
+
+// AccountBalances returns each account's balance at the end of a date, counting
+// every entry from the first day with data
+func (s *Service) AccountBalances(through time.Time) ([]AccountBalance, error) {
+	if len(s.accounts) == 0 {
+		return nil, nil
+	}
+	entries, err := s.entriesThrough(through)
+	if err != nil {
+		return nil, err
+	}
+	return AccountBalances(s.accounts, entries, s.GetCurrencies()), nil
+}
+
+// ValueCash values the expenses paid from local-currency accounts up to the end
+// of a date at the effective rates of the cash they used, by entry ID, and
+// returns the batches of cash bought with what is left of them
+func (s *Service) ValueCash(through time.Time) (map[string]CashValue, []*CashBatch, error) {
+	if len(s.accounts) == 0 {
+		return nil, nil, nil
+	}
+	entries, err := s.entriesThrough(through)
+	if err != nil {
+		return nil, nil, err
+	}
+	values, batches := ValueCash(s.accounts, entries, s.GetCurrencies())
+	return values, batches, nil
+}
+
+// entriesThrough returns every entry from the first day with data to the end of a date, oldest first
+func (s *Service) entriesThrough(through time.Time) ([]*Entry, error) {
+	dates, err := s.ListAvailableDates()
+	if err != nil {
+		return nil, err
+	}
+	if len(dates) == 0 || dates[0].After(through) {
+		return nil, nil
+	}
+	dateRange, err := s.GetDateRange(dates[0], through)
+	if err != nil {
+		return nil, err
+	}
+	return dateRange.AllEntries(""), nil
+}
+
+// BudgetStatuses returns the spending against each budget for the periods that
+// contain the given date (today's day, this week, this month).
+// Rate is the number of local units per home unit.
+func (s *Service) BudgetStatuses(date time.Time, rate float64) ([]BudgetStatus, error) {
+	return s.BudgetHistory(date, date, rate)
+}
+
+// BudgetHistory returns the spending against each budget for every period that
+// overlaps start..end, budget by budget and oldest period first. Periods are
+// whole, so the first and last may extend past the range.
+func (s *Service) BudgetHistory(start, end time.Time, rate float64) ([]BudgetStatus, error) {
+	if len(s.budgets) == 0 {
+		return nil, nil
+	}
+
+	// Load every day the periods cover once
+	first, last := start, end
+	for _, budget := range s.budgets {
+		periodStart, _ := budget.Period.Bounds(start)
+		_, periodEnd := budget.Period.Bounds(end)
+		if periodStart.Before(first) {
+			first = periodStart
+		}
+		if periodEnd.After(last) {
+			last = periodEnd
+		}
+	}
+	dateRange, err := s.GetDateRange(first, last)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []BudgetStatus
+	for _, budget := range s.budgets {
+		periodStart, _ := budget.Period.Bounds(start)
+		for !periodStart.After(end) {
+			_, periodEnd := budget.Period.Bounds(periodStart)
+			statuses = append(statuses, NewBudgetStatus(budget, periodStart, periodEnd, dateRange.Days, dateRange.Currencies, rate))
+			periodStart = periodEnd.AddDate(0, 0, 1)
+		}
+	}
+	return statuses, nil
+}
+
+// NewDay creates an empty day in the ledger's currencies
+func (s *Service) NewDay(date time.Time) *Day {
+	day := NewDay(date)
+	day.Currencies = s.GetCurrencies()
+	return day
+}
+
+// SetRateSource sets how the rate for a date is looked up when recurring entries
+// are added. A rate of 0 leaves the entries waiting for one (see Entry.NeedsRate).
+func (s *Service) SetRateSource(rateOn func(date time.Time) float64) {
+	s.rateOn = rateOn
+}
+
+// GetDay loads or creates a day as it is on disk. It doesn't add recurring
+// entries, so it's safe for reading; use OpenDay for a day the user opens.
 func (s *Service) GetDay(date time.Time) (*Day, error) {
 	return s.csvManager.LoadDay(date)
 }
 
+// OpenDay loads a day the user is opening, after adding the recurring entries due
+// on it and on the days before it (see AddDueRecurring). Recurring entries that
+// couldn't be added are reported in the error, but don't keep the day from
+// opening. The day is nil if it can't be loaded: an empty day in its place would
+// be saved over the files that failed to load.
+func (s *Service) OpenDay(date time.Time) (*Day, error) {
+	_, recurringErr := s.AddDueRecurring(date)
+	day, err := s.GetDay(date)
+	if err != nil {
+		return nil, err
+	}
+	return day, recurringErr
+}
+
+// AddDueRecurring adds the occurrences of the recurring rules due on or before a
+// date that haven't been added yet, then saves their days and the rules. Days
+// after today are left alone until they come. A rule whose entry can't be built
+// is skipped and reported in the error once the other rules' entries are saved.
+// Returns the number of entries added.
+func (s *Service) AddDueRecurring(through time.Time) (int, error) {
+	rules, err := s.GetRecurringRules()
+	if err != nil {
+		return 0, err
+	}
+
+	days := make(map[string]*Day)
+	var changed []*Day
+	var errs []error
+	added := 0
+	for i := range rules {
+		rule := &rules[i]
+		for _, date := range rule.DueThrough(recurringThrough(through)) {
+			key := date.Format(DateFormat)
+			day, loaded := days[key]
+			if !loaded {
+				if day, err = s.GetDay(date); err != nil {
+					return 0, err
+				}
+			}
+
+			rate := 0.0
+			if s.rateOn != nil {
+				rate = s.rateOn(date)
+			}
+			entry, err := rule.NewEntry(date, day.Currencies, rate)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("recurring rule %q not added: %w", rule.Description, err))
+				break
+			}
+			if !loaded {
+				days[key] = day
+				changed = append(changed, day)
+			}
+			entry.ScreenTime = day.ScreenTime
+			day.AddEntry(entry)
+			rule.Added = append(rule.Added, key)
+			added++
+		}
+	}
+
+	if added > 0 {
+		for _, day := range changed {
+			if err := s.SaveDay(day); err != nil {
+				return 0, err
+			}
+		}
+		if err := s.SaveRecurringRules(rules); err != nil {
+			return 0, err
+		}
+	}
+	return added, errors.Join(errs...)
+}
+
+// PendingRecurring counts the recurring entries due on or before a date that
+// haven't been added yet because no day since they came due has been opened.
+// Date ranges leave them out until then.
+func (s *Service) PendingRecurring(through time.Time) (int, error) {
+	rules, err := s.GetRecurringRules()
+	if err != nil {
+		return 0, err
+	}
+	pending := 0
+	for _, rule := range rules {
+		pending += len(rule.DueThrough(recurringThrough(through)))
+	}
+	return pending, nil
+}
+
+// recurringThrough returns the last day recurring entries are added through when
+// a date is opened: the date itself, or today for a future date
+func recurringThrough(date time.Time) time.Time {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	if today := Today(); date.After(today) {
+		return today
+	}
+	return date
+}
+
+// GetRecurringRules returns the recurring entry rules saved in the data directory
+func (s *Service) GetRecurringRules() ([]RecurringRule, error) {
+	return LoadRecurringRules(s.csvManager.GetDataDir())
+}
+
+// SaveRecurringRules replaces the recurring entry rules in the data directory
+func (s *Service) SaveRecurringRules(rules []RecurringRule) error {
+	return SaveRecurringRules(s.csvManager.GetDataDir(), rules)
+}
+
 // GetToday loads or creates today's day
 func (s *Service) GetToday() (*Day, error) {
 	return s.GetDay(time.Now())
 }
 
-// SaveDay saves a day to CSV
+// SaveDay reconciles the files on disk with the day, deleting data.csv, entry.md
+// and the day directory when a day loaded from disk no longer has entries or a
+// journal
 func (s *Service) SaveDay(day *Day) error {
-	// Don't save empty days
-	if day.IsEmpty() {
-		return nil
-	}
 	return s.csvManager.SaveDay(day)
 }
 
@@ -64,12 +313,68 @@ func (s *Service) ListAvailableDates() ([]time.Time, error) {
 	return s.csvManager.ListAvailableDates()
 }
 
+// AdjacentDateWithData returns the closest day before (or, if forward is true,
+// after) the given date that has entries or a journal. It returns false if there is none.
+func (s *Service) AdjacentDateWithData(date time.Time, forward bool) (time.Time, bool, error) {
+	dates, err := s.ListAvailableDates()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	// Dates are sorted, so compare the YYYY-MM-DD strings
+	current := date.Format(DateFormat)
+	if forward {
+		for _, d := range dates {
+			if d.Format(DateFormat) > current {
+				return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, date.Location()), true, nil
+			}
+		}
+	} else {
+		for i := len(dates) - 1; i >= 0; i-- {
+			if d := dates[i]; d.Format(DateFormat) < current {
+				return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, date.Location()), true, nil
+			}
+		}
+	}
+	return time.Time{}, false, nil
+}
+
+// ListCategories returns every category used across the ledger, sorted by name
+func (s *Service) ListCategories() ([]string, error) {
+	dates, err := s.ListAvailableDates()
+	if err != nil {
+		return nil, err
+	}
+	if len(dates) == 0 {
+		return nil, nil
+	}
+
+	dateRange, err := s.GetDateRange(dates[0], dates[len(dates)-1])
+	if err != nil {
+		return nil, err
+	}
+	return dateRange.Categories(), nil
+}
+
 // AddEntry adds an entry to a day and saves it
 func (s *Service) AddEntry(day *Day, entry *Entry) error {
 	day.AddEntry(entry)
 	return s.SaveDay(day)
 }
 
+// SettleUp adds the entry that settles a person's balance to a day and saves it
+func (s *Service) SettleUp(date time.Time, balance Balance) (*Entry, error) {
+	day, err := s.GetDay(date)
+	if err != nil {
+		return nil, err
+	}
+	entry := NewSettlement(date, balance)
+	if err := s.AddEntry(day, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
 // RemoveEntry removes an entry from a day and saves it
 func (s *Service) RemoveEntry(day *Day, entryID string) (*Entry, error) {
 	removed := day.RemoveEntry(entryID)
@@ -77,9 +382,8 @@ func (s *Service) RemoveEntry(day *Day, entryID string) (*Entry, error) {
 		return nil, nil
 	}
 
-	// If day is now empty, we still want to keep the file
-	// unless explicitly deleted
-	if err := s.csvManager.SaveDay(day); err != nil {
+	// Removing the last entry deletes the day's CSV file
+	if err := s.SaveDay(day); err != nil {
 		// Restore the entry if save fails
 		day.AddEntry(removed)
 		return nil, err
@@ -102,6 +406,16 @@ func (s *Service) SetScreenTime(day *Day, screenTime string) error {
 	return s.SaveDay(day)
 }
 
+// MigrateEntryIDs persists IDs for entries in day files that predate the id column
+func (s *Service) MigrateEntryIDs() (int, error) {
+	return s.csvManager.MigrateIDs()
+}
+
+// GetImportProfiles returns the statement import profiles saved in the data directory
+func (s *Service) GetImportProfiles() ([]ImportProfile, error) {
+	return LoadImportProfiles(s.csvManager.GetDataDir())
+}
+
 // GetCSVManager returns the underlying CSV manager
 func (s *Service) GetCSVManager() *CSVManager {
 	return s.csvManager
@@ -0,0 +1,44 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdjacentDateWithData(t *testing.T) {
+	s := NewServiceWithCurrencies(t.TempDir(), DefaultPair)
+	m := s.GetCSVManager()
+	date := func(day int) time.Time { return time.Date(2024, 3, day, 0, 0, 0, 0, time.Local) }
+	savedDay(t, m, date(10))
+	savedDay(t, m, date(20))
+	if err := m.SaveJournal(date(12), "Rained all day"); err != nil {
+		t.Fatal(err)
+	}
+	// A day directory with nothing in it is skipped
+	if err := m.EnsureDayDir(date(15)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		from    time.Time
+		forward bool
+		want    time.Time // Zero if there is none
+	}{
+		{date(12), true, date(20)},
+		{date(12), false, date(10)},
+		{date(11), true, date(12)}, // Journal-only days count
+		{date(16), false, date(12)},
+		{date(10), false, time.Time{}},
+		{date(20), true, time.Time{}},
+	}
+	for _, tt := range tests {
+		got, ok, err := s.AdjacentDateWithData(tt.from, tt.forward)
+		if err != nil {
+			t.Fatalf("AdjacentDateWithData: %v", err)
+		}
+		if ok != !tt.want.IsZero() || !got.Equal(tt.want) {
+			t.Errorf("AdjacentDateWithData(%s, forward %v) = %s, %v, want %s",
+				tt.from.Format(DateFormat), tt.forward, got.Format(DateFormat), ok, tt.want.Format(DateFormat))
+		}
+	}
+}
@@ -0,0 +1,404 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Me is the name that stands for you when splitting a bill
+const Me = "me"
+
+// SettlementCategory is the category of the entries recorded by settling up
+const SettlementCategory = "settlement"
+
+// IsMe returns true if a person's name stands for you ("" or "me")
+func IsMe(name string) bool {
+	name = strings.TrimSpace(name)
+	return name == "" || strings.EqualFold(name, Me)
+}
+
+// SplitMethod is how a bill is divided among the people sharing it
+type SplitMethod string
+
+const (
+	SplitEqual  SplitMethod = "equal"
+	SplitShares SplitMethod = "shares" // In proportion to each person's shares
+	SplitExact  SplitMethod = "exact"  // Each person's part is given as an amount
+)
+
+// SplitPerson is one of the people sharing a bill
+type SplitPerson struct {
+	Name   string  `json:"name"`
+	Shares float64 `json:"shares,omitempty"` // Weight when split by shares
+	Amount Money   `json:"amount,omitempty"` // Part of the bill when split by exact amounts, in the entry's source currency
+}
+
+// Split is how a bill is shared. When you paid, the bill is the entry's amount.
+// When someone else paid, nothing left your pocket, so the entry's amount is zero
+// and the bill is kept here in both currencies.
+type Split struct {
+	Method    SplitMethod   `json:"method"`
+	People    []SplitPerson `json:"people"`
+	BillHome  Money         `json:"bill_home,omitempty"` // Bill paid by someone else (positive)
+	BillLocal Money         `json:"bill_local,omitempty"`
+}
+
+// Clone returns a copy of the split (nil stays nil)
+func (s *Split) Clone() *Split {
+	if s == nil {
+		return nil
+	}
+	clone := *s
+	clone.People = append([]SplitPerson(nil), s.People...)
+	return &clone
+}
+
+// weights returns each person's part of the bill relative to the others
+func (s *Split) weights() []*big.Rat {
+	weights := make([]*big.Rat, len(s.People))
+	for i, p := range s.People {
+		switch s.Method {
+		case SplitShares:
+			weights[i] = rateRat(p.Shares)
+		case SplitExact:
+			weights[i] = new(big.Rat).SetInt64(int64(p.Amount))
+		default:
+			weights[i] = big.NewRat(1, 1)
+		}
+	}
+	return weights
+}
+
+// Allocate divides a bill among the people in proportion to their weights. The
+// parts add up to the bill exactly; leftover minor units go to the first people.
+func (s *Split) Allocate(bill Money) []Money {
+	weights := s.weights()
+	sum := new(big.Rat)
+	for _, w := range weights {
+		sum.Add(sum, w)
+	}
+
+	parts := make([]Money, len(weights))
+	if sum.Sign() == 0 {
+		return parts
+	}
+	var allocated Money
+	for i, w := range weights {
+		part := new(big.Rat).Mul(new(big.Rat).SetInt64(int64(bill)), w)
+		part.Quo(part, sum)
+		parts[i] = Money(new(big.Int).Quo(part.Num(), part.Denom()).Int64()) // Toward zero
+		allocated += parts[i]
+	}
+
+	step := Money(1)
+	if bill < 0 {
+		step = -1
+	}
+	for i := 0; allocated != bill && len(parts) > 0; i = (i + 1) % len(parts) {
+		parts[i] += step
+		allocated += step
+	}
+	return parts
+}
+
+// Format writes the people the way ParseSplit reads them ("me alice",
+// "me*2 alice", "me=100000 alice=50000"), with exact amounts in cur
+func (s *Split) Format(cur Currency) string {
+	parts := make([]string, len(s.People))
+	for i, p := range s.People {
+		switch s.Method {
+		case SplitShares:
+			parts[i] = p.Name + "*" + strconv.FormatFloat(p.Shares, 'f', -1, 64)
+		case SplitExact:
+			parts[i] = p.Name + "=" + p.Amount.Format(cur)
+		default:
+			parts[i] = p.Name
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Describe explains the split for display (e.g., "split equally: me, alice")
+func (s *Split) Describe() string {
+	names := make([]string, len(s.People))
+	for i, p := range s.People {
+		names[i] = p.Name
+	}
+	switch s.Method {
+	case SplitShares:
+		return "split by shares: " + s.Format(Currency{})
+	case SplitExact:
+		return "split by amounts: " + strings.Join(names, ", ")
+	}
+	if len(s.People) == 1 {
+		return "all for " + s.People[0].Name
+	}
+	return "split equally: " + strings.Join(names, ", ")
+}
+
+// ParseSplit reads who paid a bill and who shares it: an optional payer followed
+// by a colon, then the people separated by spaces or commas. Names alone split the
+// bill equally, name*N splits it by shares and name=amount gives each part in cur
+// ("me alice bob", "alice: me alice", "me*2 alice", "me=100k alice=50k"). A payer
+// without people means the bill was all yours ("alice:"). "me" stands for you.
+// Empty input means the entry isn't shared.
+func ParseSplit(input string, cur Currency, currencies Pair) (string, *Split, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", nil, nil
+	}
+
+	payer := ""
+	if before, after, found := strings.Cut(input, ":"); found {
+		if strings.TrimSpace(before) == "" {
+			return "", nil, errors.New("missing payer before ':'")
+		}
+		payer = normalizeName(before)
+		input = after
+	}
+
+	fields := strings.FieldsFunc(input, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+	if len(fields) == 0 {
+		if IsMe(payer) {
+			return "", nil, errors.New("no one to split with")
+		}
+		return payer, &Split{Method: SplitEqual, People: []SplitPerson{{Name: Me}}}, nil
+	}
+
+	split := &Split{Method: SplitEqual}
+	methods := make([]SplitMethod, len(fields))
+	seen := make(map[string]bool)
+	for i, field := range fields {
+		person, method, err := parseSplitPerson(field, cur, currencies)
+		if err != nil {
+			return "", nil, err
+		}
+		if method != SplitEqual {
+			if split.Method != SplitEqual && split.Method != method {
+				return "", nil, errors.New("use either shares (*) or amounts (=), not both")
+			}
+			split.Method = method
+		}
+		key := strings.ToLower(person.Name)
+		if seen[key] {
+			return "", nil, fmt.Errorf("%s is listed twice", person.Name)
+		}
+		seen[key] = true
+		methods[i] = method
+		split.People = append(split.People, person)
+	}
+
+	// Names without a weight count as one share; with amounts, everyone needs one
+	for i, p := range split.People {
+		switch {
+		case split.Method == SplitShares && methods[i] == SplitEqual:
+			split.People[i].Shares = 1
+		case split.Method == SplitExact && methods[i] != SplitExact:
+			return "", nil, fmt.Errorf("%s needs an amount (name=amount)", p.Name)
+		}
+	}
+
+	if IsMe(payer) {
+		payer = ""
+	}
+	return payer, split, nil
+}
+
+// parseSplitPerson reads "name", "name*shares" or "name=amount"
+func parseSplitPerson(field string, cur Currency, currencies Pair) (SplitPerson, SplitMethod, error) {
+	if strings.IndexAny(field, "*=") == 0 {
+		return SplitPerson{}, "", fmt.Errorf("missing name before %q", field)
+	}
+	if name, shares, found := strings.Cut(field, "*"); found {
+		n, err := strconv.ParseFloat(shares, 64)
+		if err != nil || n <= 0 {
+			return SplitPerson{}, "", fmt.Errorf("invalid shares %q for %s", shares, name)
+		}
+		return SplitPerson{Name: normalizeName(name), Shares: n}, SplitShares, nil
+	}
+	if name, amount, found := strings.Cut(field, "="); found {
+		m, amountCur, err := ParseAmount(amount, cur, currencies)
+		if err != nil {
+			return SplitPerson{}, "", fmt.Errorf("invalid amount for %s: %w", name, err)
+		}
+		if amountCur.Code != cur.Code || m < 0 {
+			return SplitPerson{}, "", fmt.Errorf("amount for %s must be a positive %s amount", name, cur.Code)
+		}
+		return SplitPerson{Name: normalizeName(name), Amount: m}, SplitExact, nil
+	}
+	return SplitPerson{Name: normalizeName(field)}, SplitEqual, nil
+}
+
+// normalizeName trims a person's name and spells you as "me"
+func normalizeName(name string) string {
+	name = strings.TrimSpace(name)
+	if IsMe(name) {
+		return Me
+	}
+	return name
+}
+
+// IsShared returns true if someone else paid for the entry or it's split with others
+func (e *Entry) IsShared() bool {
+	return e.Split != nil
+}
+
+// PaidByOther returns true if someone else paid the bill and none of it came
+// out of your pocket, so the entry's amount is zero
+func (e *Entry) PaidByOther() bool {
+	return e.Split != nil && !IsMe(e.Payer) && e.Home == 0 && e.Local == 0
+}
+
+// MoveAmountToBill makes the entry's amount the bill that someone else paid,
+// leaving the entry's own amount at zero
+func (e *Entry) MoveAmountToBill() {
+	if e.Split == nil || IsMe(e.Payer) {
+		return
+	}
+	e.Split.BillHome, e.Split.BillLocal = e.Home.Abs(), e.Local.Abs()
+	e.Home, e.Local = 0, 0
+}
+
+// Bill returns the whole bill of a shared entry in both currencies (positive):
+// the entry's amount if you paid, or the bill kept with the split if someone else did
+func (e *Entry) Bill() (Money, Money) {
+	if IsMe(e.Payer) || e.Split == nil {
+		return e.Home.Abs(), e.Local.Abs()
+	}
+	return e.Split.BillHome, e.Split.BillLocal
+}
+
+// SetSplit sets who paid for the entry and how it's shared (nil split for an
+// entry that isn't shared). Once someone else paid, the entry's amount moves into
+// the split's bill and the entry records that nothing left your pocket; it moves
+// back when you're the payer again. Exact amounts must add up to the bill.
+func (e *Entry) SetSplit(payer string, split *Split, currencies Pair) error {
+	if split != nil && e.IsTransfer() {
+		return errors.New("a transfer can't be shared")
+	}
+	billHome, billLocal := e.Bill()
+	if split == nil {
+		payer = ""
+	}
+	if split != nil && split.Method == SplitExact {
+		bill := billLocal
+		if e.SourceCurrency(currencies).Code == currencies.Home.Code {
+			bill = billHome
+		}
+		var sum Money
+		for _, p := range split.People {
+			sum += p.Amount
+		}
+		if sum != bill {
+			cur := e.SourceCurrency(currencies)
+			return fmt.Errorf("amounts add up to %s, not the bill of %s", sum.FormatSymbol(cur), bill.FormatSymbol(cur))
+		}
+	}
+
+	wasMine := IsMe(e.Payer)
+	split = split.Clone()
+	switch {
+	case wasMine && !IsMe(payer):
+		split.BillHome, split.BillLocal = billHome, billLocal
+		e.Home, e.Local = 0, 0
+	case !wasMine && IsMe(payer):
+		e.Home, e.Local = -billHome, -billLocal
+	case !IsMe(payer):
+		split.BillHome, split.BillLocal = billHome, billLocal
+	}
+
+	if IsMe(payer) {
+		payer = ""
+	}
+	e.Payer = payer
+	e.Split = split
+	return nil
+}
+
+// Balance is where one person stands across shared entries, in both currencies:
+// positive when they paid more than their share and are owed, negative when they owe
+type Balance struct {
+	Person string
+	Home   Money
+	Local  Money
+}
+
+// Owes returns true if the person owes money, going by the local currency where
+// it is nonzero
+func (b Balance) Owes() bool {
+	if b.Local != 0 {
+		return b.Local < 0
+	}
+	return b.Home < 0
+}
+
+// IsSettled returns true if nothing is owed either way
+func (b Balance) IsSettled() bool {
+	return b.Home == 0 && b.Local == 0
+}
+
+// Balances nets out who paid for the shared entries and whose share they were.
+// Names are matched regardless of case. You ("me") come first, then everyone else
+// by name. Amounts of everyone add up to zero.
+func Balances(entries []*Entry) []Balance {
+	index := make(map[string]int)
+	var balances []Balance
+	add := func(name string, home, local Money) {
+		name = normalizeName(name)
+		key := strings.ToLower(name)
+		i, ok := index[key]
+		if !ok {
+			i = len(balances)
+			index[key] = i
+			balances = append(balances, Balance{Person: name})
+		}
+		balances[i].Home += home
+		balances[i].Local += local
+	}
+
+	for _, e := range entries {
+		if e.Split == nil {
+			continue
+		}
+		billHome, billLocal := e.Bill()
+		add(e.Payer, billHome, billLocal)
+		homeParts, localParts := e.Split.Allocate(billHome), e.Split.Allocate(billLocal)
+		for i, p := range e.Split.People {
+			add(p.Name, -homeParts[i], -localParts[i])
+		}
+	}
+
+	sort.SliceStable(balances, func(i, j int) bool {
+		if IsMe(balances[i].Person) != IsMe(balances[j].Person) {
+			return IsMe(balances[i].Person)
+		}
+		return strings.ToLower(balances[i].Person) < strings.ToLower(balances[j].Person)
+	})
+	return balances
+}
+
+// NewSettlement creates the entry that settles a person's balance with you: they
+// pay you what they owe, or you pay them what they're owed
+func NewSettlement(date time.Time, balance Balance) *Entry {
+	home, local := balance.Home.Abs(), balance.Local.Abs()
+	entry := NewEntry(date, "Settle up with "+balance.Person, 0, 0, "")
+	entry.Category = SettlementCategory
+
+	if balance.Owes() {
+		// Money in from them, as if they paid a bill that was all yours
+		entry.Home, entry.Local = home, local
+		entry.Payer = balance.Person
+		entry.Split = &Split{Method: SplitEqual, People: []SplitPerson{{Name: Me}}, BillHome: home, BillLocal: local}
+	} else {
+		// Money out to them, as if you paid a bill that was all theirs
+		entry.Home, entry.Local = -home, -local
+		entry.Split = &Split{Method: SplitEqual, People: []SplitPerson{{Name: balance.Person}}}
+	}
+	return entry
+}
@@ -0,0 +1,159 @@
+package ledger
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSplitAllocate(t *testing.T) {
+	people := func(names ...string) []SplitPerson {
+		var p []SplitPerson
+		for _, name := range names {
+			p = append(p, SplitPerson{Name: name})
+		}
+		return p
+	}
+
+	tests := []struct {
+		name  string
+		split Split
+		bill  Money
+		want  []Money
+	}{
+		{"equal", Split{Method: SplitEqual, People: people("me", "alice", "bob")}, 100, []Money{34, 33, 33}},
+		{"equal negative", Split{Method: SplitEqual, People: people("me", "alice", "bob")}, -100, []Money{-34, -33, -33}},
+		{"leftover to the first people", Split{Method: SplitEqual, People: people("me", "alice", "bob")}, 2, []Money{1, 1, 0}},
+		{"shares", Split{Method: SplitShares, People: []SplitPerson{{Name: "me", Shares: 2}, {Name: "alice", Shares: 1}}}, 100, []Money{67, 33}},
+		{"fractional shares", Split{Method: SplitShares, People: []SplitPerson{{Name: "me", Shares: 0.5}, {Name: "alice", Shares: 1.5}}}, 1001, []Money{251, 750}},
+		{"exact", Split{Method: SplitExact, People: []SplitPerson{{Name: "me", Amount: 60000}, {Name: "alice", Amount: 40000}}}, 1000, []Money{600, 400}},
+		{"no weight", Split{Method: SplitExact, People: []SplitPerson{{Name: "me"}, {Name: "alice"}}}, 1000, []Money{0, 0}},
+	}
+	for _, tt := range tests {
+		got := tt.split.Allocate(tt.bill)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: Allocate(%d) = %v, want %v", tt.name, tt.bill, got, tt.want)
+		}
+	}
+}
+
+func TestBalancesAddUpToZero(t *testing.T) {
+	date := time.Date(2026, 10, 14, 0, 0, 0, 0, time.Local)
+
+	// You paid for three
+	dinner := NewEntry(date, "Dinner", -1000, -100000, "")
+	if err := dinner.SetSplit("", &Split{Method: SplitEqual, People: []SplitPerson{{Name: "me"}, {Name: "Alice"}, {Name: "bob"}}}, DefaultPair); err != nil {
+		t.Fatal(err)
+	}
+	// Alice paid, and you had two shares
+	taxi := NewEntry(date, "Taxi", -900, -90000, "")
+	if err := taxi.SetSplit("alice", &Split{Method: SplitShares, People: []SplitPerson{{Name: "me", Shares: 2}, {Name: "alice", Shares: 1}}}, DefaultPair); err != nil {
+		t.Fatal(err)
+	}
+	entries := []*Entry{dinner, taxi, NewEntry(date, "Coffee", -300, -30000, "")}
+
+	balances := Balances(entries)
+	want := []Balance{
+		{Person: "me", Home: 66, Local: 6666},
+		{Person: "Alice", Home: 267, Local: 26667},
+		{Person: "bob", Home: -333, Local: -33333},
+	}
+	if !slices.Equal(balances, want) {
+		t.Fatalf("Balances = %v, want %v", balances, want)
+	}
+	var home, local Money
+	for _, b := range balances {
+		home += b.Home
+		local += b.Local
+	}
+	if home != 0 || local != 0 {
+		t.Errorf("balances add up to %d, %d, want 0", home, local)
+	}
+
+	// Settling up with Bob leaves him even and moves his debt to you
+	entries = append(entries, NewSettlement(date, balances[2]))
+	balances = Balances(entries)
+	if !balances[2].IsSettled() {
+		t.Errorf("Bob = %v after settling up, want settled", balances[2])
+	}
+	if balances[0].Home != -267 || balances[0].Local != -26667 {
+		t.Errorf("me = %v after Bob settled up, want -267, -26667", balances[0])
+	}
+}
+
+func TestNewSettlement(t *testing.T) {
+	date := time.Date(2026, 10, 14, 0, 0, 0, 0, time.Local)
+
+	// They owe you: money in, as if they paid a bill that was all yours
+	in := NewSettlement(date, Balance{Person: "bob", Home: -333, Local: -33333})
+	if in.Home != 333 || in.Local != 33333 || in.Payer != "bob" {
+		t.Errorf("settlement from Bob = %d, %d paid by %q, want 333, 33333 paid by bob", in.Home, in.Local, in.Payer)
+	}
+	if in.Split == nil || len(in.Split.People) != 1 || !IsMe(in.Split.People[0].Name) {
+		t.Errorf("settlement from Bob is split %+v, want all yours", in.Split)
+	}
+
+	// You owe them: money out, as if you paid a bill that was all theirs
+	out := NewSettlement(date, Balance{Person: "Alice", Home: 267, Local: 26667})
+	if out.Home != -267 || out.Local != -26667 || out.Payer != "" {
+		t.Errorf("settlement to Alice = %d, %d paid by %q, want -267, -26667 paid by you", out.Home, out.Local, out.Payer)
+	}
+	if out.Split == nil || len(out.Split.People) != 1 || out.Split.People[0].Name != "Alice" {
+		t.Errorf("settlement to Alice is split %+v, want all hers", out.Split)
+	}
+
+	for _, entry := range []*Entry{in, out} {
+		if entry.Category != SettlementCategory || !entry.IsBookkeeping() {
+			t.Errorf("%s is in category %q, want a settlement", entry.Description, entry.Category)
+		}
+	}
+}
+
+func TestEntrySetSplit(t *testing.T) {
+	date := time.Date(2026, 10, 14, 0, 0, 0, 0, time.Local)
+	equal := &Split{Method: SplitEqual, People: []SplitPerson{{Name: "me"}, {Name: "alice"}}}
+	entry := NewEntry(date, "Dinner", -1000, -100000, "")
+
+	// Someone else paying moves the amount into the bill
+	if err := entry.SetSplit("alice", equal, DefaultPair); err != nil {
+		t.Fatal(err)
+	}
+	if !entry.PaidByOther() || entry.Split.BillHome != 1000 || entry.Split.BillLocal != 100000 {
+		t.Errorf("paid by Alice = %d, %d with bill %d, %d, want 0, 0 with bill 1000, 100000",
+			entry.Home, entry.Local, entry.Split.BillHome, entry.Split.BillLocal)
+	}
+
+	// Paying yourself again moves it back
+	if err := entry.SetSplit("me", equal, DefaultPair); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Payer != "" || entry.Home != -1000 || entry.Local != -100000 {
+		t.Errorf("paid by you = %d, %d paid by %q, want -1000, -100000", entry.Home, entry.Local, entry.Payer)
+	}
+
+	// Exact amounts must add up to the bill in the entry's currency
+	exact := &Split{Method: SplitExact, People: []SplitPerson{{Name: "me", Amount: 60000}, {Name: "alice", Amount: 30000}}}
+	if err := entry.SetSplit("", exact, DefaultPair); err == nil {
+		t.Error("SetSplit accepted amounts that don't add up to the bill")
+	}
+	exact.People[1].Amount = 40000
+	if err := entry.SetSplit("", exact, DefaultPair); err != nil {
+		t.Errorf("SetSplit with amounts adding up to the bill: %v", err)
+	}
+
+	// No split stops sharing the entry
+	if err := entry.SetSplit("alice", nil, DefaultPair); err != nil {
+		t.Fatal(err)
+	}
+	if entry.IsShared() || entry.Payer != "" || entry.Home != -1000 {
+		t.Errorf("unshared entry = %d paid by %q, shared %v, want -1000 paid by you", entry.Home, entry.Payer, entry.IsShared())
+	}
+
+	transfer := NewEntry(date, "ATM", -1000, -100000, "")
+	if err := transfer.SetAccount("wise", "cash"); err != nil {
+		t.Fatal(err)
+	}
+	if err := transfer.SetSplit("", equal, DefaultPair); err == nil {
+		t.Error("SetSplit shared a transfer")
+	}
+}
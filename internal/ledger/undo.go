@@ -1,6 +1,20 @@
 package ledger
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"ledger-a/internal/fsutil"
+)
+
+// UndoFileName is the name of the persisted undo/redo history in the data directory
+const UndoFileName = "undo.json"
+
+// UndoHistoryVersion is the format of the undo history file
+const UndoHistoryVersion = 1
 
 // ActionType represents the type of action that can be undone
 type ActionType int
@@ -10,20 +24,52 @@ const (
 	ActionDeleteEntry
 	ActionEditEntry
 	ActionSetScreenTime
+	ActionSetJournal
 )
 
+// actionNames are the names action types are stored under in the undo history file
+var actionNames = map[ActionType]string{
+	ActionAddEntry:      "add_entry",
+	ActionDeleteEntry:   "delete_entry",
+	ActionEditEntry:     "edit_entry",
+	ActionSetScreenTime: "set_screen_time",
+	ActionSetJournal:    "set_journal",
+}
+
+// MarshalText stores the action type by name so the history survives reordering the constants
+func (t ActionType) MarshalText() ([]byte, error) {
+	name, ok := actionNames[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown action type %d", int(t))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText parses an action type name
+func (t *ActionType) UnmarshalText(text []byte) error {
+	for actionType, name := range actionNames {
+		if name == string(text) {
+			*t = actionType
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown action type %q", text)
+}
+
 // UndoAction represents an action that can be undone
 type UndoAction struct {
-	Type        ActionType
-	Date        time.Time
-	Entry       *Entry     // For entry operations
-	OldEntry    *Entry     // For edit operations (previous state)
-	ScreenTime  string     // For screen time operations
-	OldScreenTime string   // Previous screen time
-	Description string     // Human-readable description for notification
+	Type          ActionType `json:"type"`
+	Date          time.Time  `json:"date"`
+	Entry         *Entry     `json:"entry,omitempty"`           // For entry operations
+	OldEntry      *Entry     `json:"old_entry,omitempty"`       // For edit operations (previous state)
+	ScreenTime    string     `json:"screen_time,omitempty"`     // For screen time operations
+	OldScreenTime string     `json:"old_screen_time,omitempty"` // Previous screen time
+	Journal       string     `json:"journal,omitempty"`         // For journal operations
+	OldJournal    string     `json:"old_journal,omitempty"`     // Previous journal
+	Description   string     `json:"description"`               // Human-readable description for notification
 }
 
-// UndoStack manages undo operations for the current session
+// UndoStack is a bounded stack of actions, used for both undo and redo history
 type UndoStack struct {
 	actions []*UndoAction
 	maxSize int
@@ -123,25 +169,51 @@ func (us *UndoStack) PushSetScreenTime(date time.Time, oldScreenTime, newScreenT
 	})
 }
 
+// PushSetJournal records a journal change action
+func (us *UndoStack) PushSetJournal(date time.Time, oldJournal, newJournal string) {
+	us.Push(&UndoAction{
+		Type:        ActionSetJournal,
+		Date:        date,
+		Journal:     newJournal,
+		OldJournal:  oldJournal,
+		Description: "Updated journal",
+	})
+}
+
 // truncate shortens a string and adds ellipsis if needed
 func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
 		return s
 	}
-	return s[:maxLen-3] + "..."
+	return string(runes[:maxLen-3]) + "..."
+}
+
+// undoLog is the on-disk form of the undo and redo history
+type undoLog struct {
+	Version int           `json:"version"`
+	Undo    []*UndoAction `json:"undo"`
+	Redo    []*UndoAction `json:"redo"`
 }
 
-// UndoManager handles undo operations with the ledger service
+// UndoManager handles undo and redo operations with the ledger service.
+// The history is kept in the data directory so it survives restarts.
 type UndoManager struct {
-	stack   *UndoStack
-	service *Service
+	stack     *UndoStack
+	redoStack *UndoStack
+	service   *Service
+	path      string
+	frozen    bool // The history file is from a newer version and is left alone
 }
 
-// NewUndoManager creates a new undo manager
+// NewUndoManager creates an undo manager with an empty history; call Load to
+// restore the history saved in the service's data directory
 func NewUndoManager(service *Service) *UndoManager {
 	return &UndoManager{
-		stack:   NewUndoStack(),
-		service: service,
+		stack:     NewUndoStack(),
+		redoStack: NewUndoStack(),
+		service:   service,
+		path:      filepath.Join(service.GetCSVManager().GetDataDir(), UndoFileName),
 	}
 }
 
@@ -150,6 +222,66 @@ func (um *UndoManager) GetStack() *UndoStack {
 	return um.stack
 }
 
+// GetRedoStack returns the redo stack
+func (um *UndoManager) GetRedoStack() *UndoStack {
+	return um.redoStack
+}
+
+// Load restores the undo and redo history from the data directory.
+// A missing history file leaves both stacks empty.
+func (um *UndoManager) Load() error {
+	data, err := os.ReadFile(um.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read undo history: %w", err)
+	}
+
+	var log undoLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		return fmt.Errorf("failed to parse undo history: %w", err)
+	}
+	if log.Version > UndoHistoryVersion {
+		um.frozen = true
+		return fmt.Errorf("undo history is in a newer format (version %d), leaving it alone", log.Version)
+	}
+
+	um.stack.Clear()
+	for _, action := range log.Undo {
+		um.stack.Push(action)
+	}
+	um.redoStack.Clear()
+	for _, action := range log.Redo {
+		um.redoStack.Push(action)
+	}
+	return nil
+}
+
+// save writes the undo and redo history to the data directory, unless the file
+// there is from a newer version
+func (um *UndoManager) save() error {
+	if um.frozen {
+		return nil
+	}
+	data, err := json.MarshalIndent(undoLog{
+		Version: UndoHistoryVersion,
+		Undo:    um.stack.actions,
+		Redo:    um.redoStack.actions,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal undo history: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(um.path), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	if err := fsutil.WriteFile(um.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write undo history: %w", err)
+	}
+	return nil
+}
+
 // Undo performs the undo operation and returns a description of what was undone
 func (um *UndoManager) Undo() (string, error) {
 	action := um.stack.Pop()
@@ -157,46 +289,109 @@ func (um *UndoManager) Undo() (string, error) {
 		return "", nil
 	}
 
+	msg, err := um.apply(action, true)
+	if err != nil {
+		um.stack.Push(action)
+		return "", err
+	}
+
+	um.redoStack.Push(action)
+	return msg, um.save()
+}
+
+// Redo reapplies the last undone action and returns a description of what was redone
+func (um *UndoManager) Redo() (string, error) {
+	action := um.redoStack.Pop()
+	if action == nil {
+		return "", nil
+	}
+
+	msg, err := um.apply(action, false)
+	if err != nil {
+		um.redoStack.Push(action)
+		return "", err
+	}
+
+	um.stack.Push(action)
+	return msg, um.save()
+}
+
+// apply reverts an action (undo) or performs it again (redo) on its day and saves the day
+func (um *UndoManager) apply(action *UndoAction, undo bool) (string, error) {
 	day, err := um.service.GetDay(action.Date)
 	if err != nil {
 		return "", err
 	}
 
+	var msg string
 	switch action.Type {
 	case ActionAddEntry:
-		// Undo add = remove the entry
-		day.RemoveEntry(action.Entry.ID)
-		if err := um.service.SaveDay(day); err != nil {
-			return "", err
+		if undo {
+			// Undo add = remove the entry
+			day.RemoveEntry(action.Entry.ID)
+			msg = "Undo: Removed '" + truncate(action.Entry.Description, 20) + "'"
+		} else {
+			addOnce(day, action.Entry)
+			msg = "Redo: Added '" + truncate(action.Entry.Description, 20) + "'"
 		}
-		return "Undo: Removed '" + truncate(action.Entry.Description, 20) + "'", nil
 
 	case ActionDeleteEntry:
-		// Undo delete = restore the entry
-		day.AddEntry(action.Entry)
-		if err := um.service.SaveDay(day); err != nil {
-			return "", err
+		if undo {
+			// Undo delete = restore the entry
+			addOnce(day, action.Entry)
+			msg = "Undo: Restored '" + truncate(action.Entry.Description, 20) + "'"
+		} else {
+			day.RemoveEntry(action.Entry.ID)
+			msg = "Redo: Deleted '" + truncate(action.Entry.Description, 20) + "'"
 		}
-		return "Undo: Restored '" + truncate(action.Entry.Description, 20) + "'", nil
 
 	case ActionEditEntry:
-		// Undo edit = restore old entry state
-		day.UpdateEntry(action.OldEntry)
-		if err := um.service.SaveDay(day); err != nil {
-			return "", err
+		entry, verb := action.Entry, "Redo: Edited"
+		if undo {
+			// Undo edit = restore old entry state
+			entry, verb = action.OldEntry, "Undo: Reverted"
+		}
+		if !day.UpdateEntry(entry.Clone()) {
+			return "", fmt.Errorf("'%s' is no longer on %s", truncate(entry.Description, 20), action.Date.Format("2006-01-02"))
 		}
-		return "Undo: Reverted '" + truncate(action.OldEntry.Description, 20) + "'", nil
+		msg = verb + " '" + truncate(entry.Description, 20) + "'"
 
 	case ActionSetScreenTime:
-		// Undo screen time = restore old screen time
-		day.SetScreenTime(action.OldScreenTime)
-		if err := um.service.SaveDay(day); err != nil {
-			return "", err
+		if undo {
+			// Undo screen time = restore old screen time
+			day.SetScreenTime(action.OldScreenTime)
+			msg = "Undo: Restored screen time to '" + action.OldScreenTime + "'"
+		} else {
+			day.SetScreenTime(action.ScreenTime)
+			msg = "Redo: Changed screen time to '" + action.ScreenTime + "'"
+		}
+
+	case ActionSetJournal:
+		if undo {
+			// Undo journal = restore old journal
+			day.Journal = action.OldJournal
+			msg = "Undo: Restored journal"
+		} else {
+			day.Journal = action.Journal
+			msg = "Redo: Updated journal"
 		}
-		return "Undo: Restored screen time to '" + action.OldScreenTime + "'", nil
+
+	default:
+		return "", nil
+	}
+
+	if err := um.service.SaveDay(day); err != nil {
+		return "", err
 	}
+	return msg, nil
+}
 
-	return "", nil
+// addOnce adds a copy of the entry unless the day already has it, which can happen
+// when the day file was changed after the action was recorded
+func addOnce(day *Day, entry *Entry) {
+	if day.GetEntry(entry.ID) == nil {
+		day.AddEntry(entry.Clone())
+	}
 }
 
 // CanUndo returns true if there are actions to undo
@@ -204,22 +399,40 @@ func (um *UndoManager) CanUndo() bool {
 	return !um.stack.IsEmpty()
 }
 
+// CanRedo returns true if there are undone actions to redo
+func (um *UndoManager) CanRedo() bool {
+	return !um.redoStack.IsEmpty()
+}
+
+// record adds a new action to the history. A new action starts a new branch of
+// history, so anything that was undone can no longer be redone.
+func (um *UndoManager) record(push func(*UndoStack)) error {
+	push(um.stack)
+	um.redoStack.Clear()
+	return um.save()
+}
+
 // RecordAddEntry records an add entry action for undo
-func (um *UndoManager) RecordAddEntry(date time.Time, entry *Entry) {
-	um.stack.PushAddEntry(date, entry)
+func (um *UndoManager) RecordAddEntry(date time.Time, entry *Entry) error {
+	return um.record(func(us *UndoStack) { us.PushAddEntry(date, entry) })
 }
 
 // RecordDeleteEntry records a delete entry action for undo
-func (um *UndoManager) RecordDeleteEntry(date time.Time, entry *Entry) {
-	um.stack.PushDeleteEntry(date, entry)
+func (um *UndoManager) RecordDeleteEntry(date time.Time, entry *Entry) error {
+	return um.record(func(us *UndoStack) { us.PushDeleteEntry(date, entry) })
 }
 
 // RecordEditEntry records an edit entry action for undo
-func (um *UndoManager) RecordEditEntry(date time.Time, oldEntry, newEntry *Entry) {
-	um.stack.PushEditEntry(date, oldEntry, newEntry)
+func (um *UndoManager) RecordEditEntry(date time.Time, oldEntry, newEntry *Entry) error {
+	return um.record(func(us *UndoStack) { us.PushEditEntry(date, oldEntry, newEntry) })
 }
 
 // RecordSetScreenTime records a screen time change for undo
-func (um *UndoManager) RecordSetScreenTime(date time.Time, oldScreenTime, newScreenTime string) {
-	um.stack.PushSetScreenTime(date, oldScreenTime, newScreenTime)
+func (um *UndoManager) RecordSetScreenTime(date time.Time, oldScreenTime, newScreenTime string) error {
+	return um.record(func(us *UndoStack) { us.PushSetScreenTime(date, oldScreenTime, newScreenTime) })
+}
+
+// RecordSetJournal records a journal change for undo
+func (um *UndoManager) RecordSetJournal(date time.Time, oldJournal, newJournal string) error {
+	return um.record(func(us *UndoStack) { us.PushSetJournal(date, oldJournal, newJournal) })
 }
@@ -0,0 +1,80 @@
+package ledger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUndoHistorySurvivesRestart(t *testing.T) {
+	s := NewServiceWithCurrencies(t.TempDir(), DefaultPair)
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	entry := NewEntry(date, "Dinner", -1500, -150000, "")
+	entry.ScreenTime = "2h"
+	if err := entry.SetSplit("alice", &Split{Method: SplitEqual, People: []SplitPerson{{Name: "me"}, {Name: "alice"}}}, DefaultPair); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewUndoManager(s).RecordAddEntry(date, entry); err != nil {
+		t.Fatal(err)
+	}
+
+	um := NewUndoManager(s)
+	if err := um.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	got := um.GetStack().Peek().Entry
+	if got.ID != entry.ID || got.ScreenTime != "2h" {
+		t.Errorf("entry = %+v", got)
+	}
+	if got.Split == nil || got.Split.BillHome != 1500 || got.Split.BillLocal != 150000 {
+		t.Errorf("split = %+v, want the bill kept", got.Split)
+	}
+}
+
+func TestUndoEditOfMissingEntry(t *testing.T) {
+	s := NewServiceWithCurrencies(t.TempDir(), DefaultPair)
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+	old := NewEntry(date, "Dinner", -1500, -150000, "")
+	edited := old.Clone()
+	edited.Description = "Late dinner"
+
+	// The entry was edited, then deleted without the deletion being recorded
+	um := NewUndoManager(s)
+	if err := um.RecordEditEntry(date, old, edited); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := um.Undo(); err == nil {
+		t.Error("Undo reverted an edit of an entry that isn't there")
+	}
+	if !um.CanUndo() || um.CanRedo() {
+		t.Error("the failed undo left the history changed")
+	}
+	day, err := s.GetDay(date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(day.Entries) != 0 {
+		t.Errorf("day has %d entries after the failed undo, want none", len(day.Entries))
+	}
+}
+
+func TestUndoHistoryLeavesNewerFormatAlone(t *testing.T) {
+	s := NewServiceWithCurrencies(t.TempDir(), DefaultPair)
+	path := filepath.Join(s.GetCSVManager().GetDataDir(), UndoFileName)
+	newer := `{"version": 99, "undo": [], "redo": []}`
+	if err := os.WriteFile(path, []byte(newer), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	um := NewUndoManager(s)
+	if err := um.Load(); err == nil {
+		t.Error("Load read a history in a newer format")
+	}
+	if err := um.RecordSetJournal(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), "", "Quiet day"); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != newer {
+		t.Errorf("newer history was rewritten: %s, %v", data, err)
+	}
+}
@@ -1,12 +1,14 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"ledger-a/internal/config"
 	"ledger-a/internal/currency"
 	"ledger-a/internal/ledger"
 )
@@ -22,8 +24,43 @@ const (
 	StateDateInput
 	StateQueryStartDate
 	StateQueryEndDate
+	StateImport
+	StateReport
+	StateCalendar
+	StateRecurring
+	StateBalances
 )
 
+// RateUpdatedMsg carries the result of a background exchange-rate refresh
+type RateUpdatedMsg struct {
+	Rate   float64
+	Err    error
+	Manual bool // Requested by the user rather than at startup
+}
+
+// refreshRateCmd fetches the latest rate off the UI goroutine
+func refreshRateCmd(converter *currency.Converter, manual bool) tea.Cmd {
+	return func() tea.Msg {
+		rate, err := converter.FetchLatest()
+		return RateUpdatedMsg{Rate: rate, Err: err, Manual: manual}
+	}
+}
+
+// HistoricalRateMsg carries the result of fetching a past day's rate in the background
+type HistoricalRateMsg struct {
+	Date time.Time
+	Rate float64
+	Err  error
+}
+
+// fetchRateOnCmd fetches the rate for a past day off the UI goroutine
+func fetchRateOnCmd(converter *currency.Converter, date time.Time) tea.Cmd {
+	return func() tea.Msg {
+		rate, err := converter.FetchRateOn(date)
+		return HistoricalRateMsg{Date: date, Rate: rate, Err: err}
+	}
+}
+
 // App is the main application model
 type App struct {
 	state     AppState
@@ -37,12 +74,19 @@ type App struct {
 	converter     *currency.Converter
 	undoManager   *ledger.UndoManager
 
+	// Days whose rate is being fetched, by YYYY-MM-DD
+	fetchingRates map[string]bool
+
 	// Views
 	menu       MenuModel
 	dayView    DayViewModel
 	editor     EditorModel
 	rangeView  RangeViewModel
-	datePicker DatePickerModel
+	calendar   CalendarModel
+	importView ImportModel
+	reportView ReportModel
+	recurring  RecurringModel
+	balances   BalancesModel
 
 	// Date input
 	dateInput      textinput.Model
@@ -52,6 +96,10 @@ type App struct {
 	// Query mode (start + optional end date)
 	queryStartDate time.Time
 	queryEndInput  textinput.Model
+	queryReport    bool // Open the report instead of the range view
+
+	// State the editor returns to (the menu or the calendar)
+	editorReturn AppState
 
 	// Current data
 	currentDay       *ledger.Day
@@ -61,20 +109,58 @@ type App struct {
 	currentDateRange *ledger.DateRange
 }
 
-// NewApp creates a new application
-func NewApp() *App {
+// NewApp creates a new application from a resolved config
+func NewApp(cfg *config.Config) (*App, error) {
 	styles := DefaultStyles()
 
-	ledgerService := ledger.NewService()
-	converter := currency.NewConverter("ledger-data")
+	currencies, err := cfg.Currencies()
+	if err != nil {
+		return nil, err
+	}
+
+	budgets, err := cfg.LedgerBudgets(currencies)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := cfg.LedgerAccounts(currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	ledgerService := ledger.NewServiceWithCurrencies(cfg.DataDir, currencies)
+	ledgerService.SetBudgets(budgets)
+	ledgerService.SetAccounts(accounts)
+	converter := currency.NewConverter(cfg.CacheDir, currencies.Home.Code, currencies.Local.Code)
+	converter.SetOfflineMode(cfg.Offline)
+	converter.SetFixedRate(cfg.Rate)
+	ledgerService.SetRateSource(func(date time.Time) float64 {
+		// Opening a day never waits on the API: recurring entries added without a
+		// cached rate wait for it like entries typed in the editor
+		rate, _ := converter.CachedRateOn(date)
+		return rate
+	})
 	undoManager := ledger.NewUndoManager(ledgerService)
+	undoErr := undoManager.Load()
 
-	_ = converter.RefreshRate()
+	var notices []string
+	// Give entries in older day files stable IDs so undo can find them after a reload
+	if _, err := ledgerService.MigrateEntryIDs(); err != nil {
+		notices = append(notices, "Entry IDs not migrated: "+err.Error())
+	}
+	if notice := cfg.LegacyDataNotice(); notice != "" {
+		notices = append(notices, notice)
+	}
 
 	menu := NewMenuModel(styles)
-	dayView := NewDayViewModel(styles, ledger.NewDay(time.Now()))
-	editor := NewEditorModel(styles, ledger.NewDay(time.Now()), converter, undoManager)
-	datePicker := NewDatePickerModel(styles, DatePickerModeSingleDate)
+	menu.SetNotices(notices)
+	dayView := NewDayViewModel(styles, ledgerService.NewDay(time.Now()))
+	editor := NewEditorModel(styles, ledgerService.NewDay(time.Now()), converter, undoManager)
+	editor.SetChargeProfile(cfg.ChargeProfile())
+	editor.SetAccounts(accounts)
+	if undoErr != nil {
+		// A damaged history file shouldn't keep the ledger from opening
+		editor.SetNotificationMsg("Undo history not loaded: "+undoErr.Error(), true)
+	}
 
 	return &App{
 		state:         StateMenu,
@@ -84,17 +170,21 @@ func NewApp() *App {
 		ledgerService: ledgerService,
 		converter:     converter,
 		undoManager:   undoManager,
+		fetchingRates: make(map[string]bool),
 		menu:          menu,
 		dayView:       dayView,
 		editor:        editor,
-		datePicker:    datePicker,
 		currentDate:   ledger.Today(),
-	}
+	}, nil
 }
 
-// Init initializes the application
+// Init initializes the application and starts the exchange-rate refresh in the background
 func (a *App) Init() tea.Cmd {
-	return nil
+	if !a.converter.CanRefresh() {
+		return nil
+	}
+	a.editor.SetRefreshing(true)
+	return refreshRateCmd(a.converter, false)
 }
 
 // Update handles messages for the application
@@ -108,7 +198,43 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.menu.SetSize(msg.Width, msg.Height)
 		a.dayView.SetSize(msg.Width, msg.Height)
 		a.editor.SetSize(msg.Width, msg.Height)
-		a.datePicker.SetSize(msg.Width, msg.Height)
+		a.calendar.SetSize(msg.Width, msg.Height)
+		a.recurring.SetSize(msg.Width, msg.Height)
+		a.importView.SetSize(msg.Width, msg.Height)
+		a.reportView.SetSize(msg.Width, msg.Height)
+		a.balances.SetSize(msg.Width, msg.Height)
+		return a, nil
+
+	case RateUpdatedMsg:
+		err := a.converter.ApplyRefresh(msg.Rate, msg.Err)
+		a.editor.SetRefreshing(false)
+		if msg.Manual {
+			if err != nil {
+				a.editor.SetNotificationMsg("Rate refresh failed: "+err.Error(), true)
+			} else {
+				a.editor.SetNotificationMsg("Rate updated: "+a.converter.FormatRate(), false)
+			}
+		}
+		if a.state == StateDayEdit {
+			// Budgets set in one currency are shown in the other at the new rate
+			a.refreshBudgets()
+			return a, a.fillRates()
+		}
+		return a, nil
+
+	case HistoricalRateMsg:
+		delete(a.fetchingRates, msg.Date.Format(ledger.DateFormat))
+		err := a.converter.ApplyRateOn(msg.Date, msg.Rate, msg.Err)
+		if msg.Err != nil {
+			a.editor.SetNotificationMsg("No rate for "+msg.Date.Format("Jan 2")+", its entries aren't converted: "+msg.Err.Error(), true)
+			return a, nil
+		}
+		if err != nil {
+			a.editor.SetNotificationMsg("Rate cache not saved: "+err.Error(), true)
+		}
+		if a.state == StateDayEdit && a.currentDay != nil && a.currentDay.Date.Equal(msg.Date) {
+			return a, a.fillRates()
+		}
 		return a, nil
 
 	case tea.KeyMsg:
@@ -132,6 +258,16 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return a.updateQueryStartDate(msg)
 	case StateQueryEndDate:
 		return a.updateQueryEndDate(msg)
+	case StateImport:
+		return a.updateImport(msg)
+	case StateReport:
+		return a.updateReport(msg)
+	case StateCalendar:
+		return a.updateCalendar(msg)
+	case StateRecurring:
+		return a.updateRecurring(msg)
+	case StateBalances:
+		return a.updateBalances(msg)
 	}
 
 	return a, cmd
@@ -144,7 +280,8 @@ func (a *App) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch selection {
 	case MenuToday:
 		return a.loadDayEditor(ledger.Today())
-	case MenuQuery:
+	case MenuQuery, MenuReport:
+		a.queryReport = selection == MenuReport
 		a.dateInputTitle = "Enter Start Date"
 		a.dateInput = textinput.New()
 		a.dateInput.Placeholder = "MM/DD/YYYY"
@@ -167,6 +304,21 @@ func (a *App) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.prevState = StateMenu
 		a.state = StateDateInput
 		return a, textinput.Blink
+	case MenuImport:
+		a.importView = NewImportModel(a.styles, a.ledgerService, a.converter)
+		a.importView.SetSize(a.width, a.height)
+		a.state = StateImport
+		return a, a.importView.Init()
+	case MenuCalendar:
+		a.calendar = NewCalendarModel(a.styles, a.ledgerService, a.currentDate)
+		a.calendar.SetSize(a.width, a.height)
+		a.state = StateCalendar
+		return a, nil
+	case MenuRecurring:
+		a.recurring = NewRecurringModel(a.styles, a.ledgerService)
+		a.recurring.SetSize(a.width, a.height)
+		a.state = StateRecurring
+		return a, nil
 	case MenuQuit:
 		return a, tea.Quit
 	}
@@ -174,6 +326,62 @@ func (a *App) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
+func (a *App) updateImport(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var action ImportAction
+	var cmd tea.Cmd
+	a.importView, cmd, action = a.importView.Update(msg)
+
+	switch action {
+	case ImportActionBack:
+		a.state = StateMenu
+		return a, nil
+	case ImportActionDone:
+		// Show the imported days
+		a.rangeStartDate, a.rangeEndDate = a.importView.ImportedRange()
+		model, cmd := a.loadRangeView()
+		a.rangeView.SetNotification(a.importView.GetNotification())
+		return model, cmd
+	}
+
+	return a, cmd
+}
+
+func (a *App) updateCalendar(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var action CalendarAction
+	var cmd tea.Cmd
+	a.calendar, cmd, action = a.calendar.Update(msg)
+
+	switch action {
+	case CalendarActionBack:
+		a.state = StateMenu
+		return a, nil
+	case CalendarActionOpenDay:
+		model, cmd := a.loadDayEditor(a.calendar.GetSelectedDate())
+		if a.state == StateDayEdit {
+			a.editorReturn = StateCalendar
+		}
+		return model, cmd
+	case CalendarActionRange:
+		a.rangeStartDate, a.rangeEndDate = a.calendar.GetSelectedRange()
+		return a.loadRangeView()
+	}
+
+	return a, cmd
+}
+
+func (a *App) updateRecurring(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var action RecurringAction
+	var cmd tea.Cmd
+	a.recurring, cmd, action = a.recurring.Update(msg)
+
+	if action == RecurringActionBack {
+		a.state = StateMenu
+		return a, nil
+	}
+
+	return a, cmd
+}
+
 func (a *App) updateDayView(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var action DayViewAction
 	var cmd tea.Cmd
@@ -197,30 +405,87 @@ func (a *App) updateEditor(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch action {
 	case EditorActionBack:
-		if a.currentDay != nil && !a.currentDay.IsEmpty() {
-			_ = a.ledgerService.SaveDay(a.currentDay)
+		if a.currentDay != nil {
+			if err := a.ledgerService.SaveDay(a.currentDay); err != nil {
+				// Stay in the editor so the unsaved day isn't silently lost
+				a.editor.SetNotificationMsg("Save failed: "+err.Error(), true)
+				return a, cmd
+			}
+		}
+		if a.editorReturn == StateCalendar {
+			// Show the edited day's new total
+			a.calendar.Reload()
+			a.state = StateCalendar
+			return a, nil
 		}
 		a.state = StateMenu
 		return a, nil
 	case EditorActionSaved:
 		if a.currentDay != nil {
-			_ = a.ledgerService.SaveDay(a.currentDay)
+			if err := a.ledgerService.SaveDay(a.currentDay); err != nil {
+				a.editor.SetNotificationMsg("Save failed: "+err.Error(), true)
+			}
 		}
+		a.refreshBudgets()
+		a.refreshAccounts()
+		return a, tea.Batch(cmd, a.fillRates())
+	case EditorActionPrevDay:
+		return a.switchEditorDay(a.currentDate.AddDate(0, 0, -1))
+	case EditorActionNextDay:
+		return a.switchEditorDay(a.currentDate.AddDate(0, 0, 1))
+	case EditorActionPrevDataDay, EditorActionNextDataDay:
+		date, ok, err := a.ledgerService.AdjacentDateWithData(a.currentDate, action == EditorActionNextDataDay)
+		switch {
+		case err != nil:
+			a.editor.SetNotificationMsg("Error: "+err.Error(), true)
+		case !ok:
+			notification := "No earlier day with data"
+			if action == EditorActionNextDataDay {
+				notification = "No later day with data"
+			}
+			a.editor.SetNotificationMsg(notification, false)
+		default:
+			return a.switchEditorDay(date)
+		}
+		return a, cmd
+	case EditorActionGoToDate:
+		return a.switchEditorDay(a.editor.GetGoToDate())
 	case EditorActionReload:
-		// Reload the day from service (for undo)
+		// Reload the day from service (for undo and redo)
 		notification, isError := a.editor.GetNotification()
 		day, err := a.ledgerService.GetDay(a.currentDate)
 		if err != nil {
-			day = ledger.NewDay(a.currentDate)
+			// Keep editing what's on screen rather than an empty day
+			a.editor.SetNotificationMsg("Reload failed: "+err.Error(), true)
+			return a, cmd
 		}
 		a.currentDay = day
 		a.editor.SetDay(day)
 		a.editor.SetNotificationMsg(notification, isError)
+		a.refreshBudgets()
+		a.refreshAccounts()
+		return a, tea.Batch(cmd, a.fillRates())
 	}
 
 	return a, cmd
 }
 
+// switchEditorDay saves the day being edited and opens another one in the editor,
+// keeping the view the editor returns to
+func (a *App) switchEditorDay(date time.Time) (tea.Model, tea.Cmd) {
+	if a.currentDay != nil {
+		if err := a.ledgerService.SaveDay(a.currentDay); err != nil {
+			a.editor.SetNotificationMsg("Save failed: "+err.Error(), true)
+			return a, nil
+		}
+	}
+
+	editorReturn := a.editorReturn
+	model, cmd := a.loadDayEditor(date)
+	a.editorReturn = editorReturn
+	return model, cmd
+}
+
 func (a *App) updateRangeView(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var action RangeViewAction
 	var cmd tea.Cmd
@@ -236,11 +501,85 @@ func (a *App) updateRangeView(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if selectedEntry != nil {
 			return a.loadDayEditor(selectedEntry.Date)
 		}
+	case RangeViewShowReport:
+		a.prevState = StateRangeView
+		return a.loadReportView(a.currentDateRange, a.rangeView.GetQuery())
+	case RangeViewShowBalances:
+		a.balances = NewBalancesModel(a.styles, a.currentDateRange, a.rangeView.GetQuery())
+		a.balances.SetSize(a.width, a.height)
+		a.state = StateBalances
+		return a, nil
 	}
 
 	return a, cmd
 }
 
+func (a *App) updateReport(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var action ReportAction
+	var cmd tea.Cmd
+	a.reportView, cmd, action = a.reportView.Update(msg)
+
+	if action == ReportActionBack {
+		// Back to the range the report was opened from
+		if a.prevState == StateRangeView {
+			a.state = StateRangeView
+		} else {
+			a.state = StateMenu
+		}
+		return a, nil
+	}
+
+	return a, cmd
+}
+
+func (a *App) updateBalances(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var action BalancesAction
+	var cmd tea.Cmd
+	a.balances, cmd, action = a.balances.Update(msg)
+
+	switch action {
+	case BalancesActionBack:
+		// Settlements may have changed the range
+		a.rangeView.SetDateRange(a.currentDateRange)
+		a.state = StateRangeView
+		return a, nil
+	case BalancesActionSettle:
+		return a.settleUp()
+	}
+
+	return a, cmd
+}
+
+// settleUp records today's settlement with the selected person and refreshes the balances
+func (a *App) settleUp() (tea.Model, tea.Cmd) {
+	balance, ok := a.balances.GetSelectedBalance()
+	if !ok {
+		return a, nil
+	}
+
+	today := ledger.Today()
+	entry, err := a.ledgerService.SettleUp(today, balance)
+	if err != nil {
+		a.balances.SetNotification(err.Error(), true)
+		return a, nil
+	}
+	if err := a.undoManager.RecordAddEntry(today, entry); err != nil {
+		a.balances.SetNotification(err.Error(), true)
+		return a, nil
+	}
+
+	msg := "Settled up with " + balance.Person
+	if dateRange, err := a.ledgerService.GetDateRange(a.rangeStartDate, a.rangeEndDate); err == nil {
+		a.currentDateRange = dateRange
+		a.balances.SetDateRange(dateRange, a.balances.query)
+	}
+	if today.Before(a.currentDateRange.Start) || today.After(a.currentDateRange.End) {
+		msg += " (recorded today, outside this range)"
+	}
+	a.balances.SetNotification(msg, false)
+	return a, nil
+}
+
 func (a *App) updateQueryStartDate(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -303,8 +642,12 @@ func (a *App) updateQueryEndDate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			val := a.queryEndInput.Value()
 
-			// If empty, show single day view
+			// If empty, show single day view (or a one-day report)
 			if val == "" {
+				if a.queryReport {
+					a.rangeStartDate, a.rangeEndDate = a.queryStartDate, a.queryStartDate
+					return a.loadReport()
+				}
 				return a.loadDayView(a.queryStartDate)
 			}
 
@@ -323,6 +666,9 @@ func (a *App) updateQueryEndDate(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Load range view
 			a.rangeStartDate = a.queryStartDate
 			a.rangeEndDate = date
+			if a.queryReport {
+				return a.loadReport()
+			}
 			return a.loadRangeView()
 
 		case "esc", "q":
@@ -413,33 +759,120 @@ func autoInsertDateSlashes(s string) string {
 func (a *App) loadDayEditor(date time.Time) (tea.Model, tea.Cmd) {
 	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 
-	day, err := a.ledgerService.GetDay(date)
-	if err != nil {
-		day = ledger.NewDay(date)
+	day, err := a.ledgerService.OpenDay(date)
+	if day == nil {
+		a.showLoadError(date, err)
+		return a, nil
 	}
 
 	a.currentDay = day
 	a.currentDate = date
+	a.editorReturn = StateMenu
 	a.editor.SetDay(day)
+	if categories, err := a.ledgerService.ListCategories(); err == nil {
+		a.editor.SetCategorySuggestions(categories)
+	}
 	a.editor.RefreshCurrencyStatus()
 	a.editor.ClearNotification()
+	if err != nil {
+		a.editor.SetNotificationMsg(err.Error(), true)
+	}
+	a.refreshBudgets()
+	a.refreshAccounts()
 	a.state = StateDayEdit
 
-	return a, nil
+	return a, a.fillRates()
+}
+
+// fillRates converts the entries of the day being edited that are waiting for its
+// rate and saves the day. If the rate isn't cached, it returns a command fetching
+// it in the background (nil if the API can't be reached).
+func (a *App) fillRates() tea.Cmd {
+	day := a.currentDay
+	if day == nil || !day.NeedsRate() {
+		return nil
+	}
+
+	if rate, ok := a.converter.CachedRateOn(day.Date); ok {
+		filled := day.FillRate(rate)
+		if filled == 0 {
+			return nil
+		}
+		if err := a.ledgerService.SaveDay(day); err != nil {
+			a.editor.SetNotificationMsg("Save failed: "+err.Error(), true)
+			return nil
+		}
+		a.editor.SetDay(day)
+		a.editor.SetNotificationMsg(fmt.Sprintf("Converted %d entries at the rate for %s", filled, day.Date.Format("Jan 2")), false)
+		a.refreshBudgets()
+		a.refreshAccounts()
+		return nil
+	}
+
+	key := day.Date.Format(ledger.DateFormat)
+	if !day.Date.Before(ledger.Today()) || !a.converter.CanRefresh() || !a.converter.ShouldFetchRateOn(day.Date) || a.fetchingRates[key] {
+		return nil
+	}
+	a.fetchingRates[key] = true
+	return fetchRateOnCmd(a.converter, day.Date)
+}
+
+// refreshAccounts recomputes the account balances at the end of the day being edited
+func (a *App) refreshAccounts() {
+	balances, err := a.ledgerService.AccountBalances(a.currentDate)
+	if err != nil {
+		// Leave the balances out rather than interrupt editing
+		balances = nil
+	}
+	a.editor.SetAccountBalances(balances)
+}
+
+// refreshBudgets recomputes the spending against the budgets for the day being edited
+func (a *App) refreshBudgets() {
+	statuses, err := a.ledgerService.BudgetStatuses(a.currentDate, a.converter.GetRate())
+	if err != nil {
+		// Leave the budgets out of the ribbon rather than interrupt editing
+		statuses = nil
+	}
+	a.editor.SetBudgets(statuses)
+}
+
+// showLoadError reports a day that couldn't be opened on the screen it was
+// opened from
+func (a *App) showLoadError(date time.Time, err error) {
+	msg := fmt.Sprintf("Can't open %s: %v", date.Format("01/02/2006"), err)
+	switch a.state {
+	case StateMenu:
+		a.menu.SetNotification(msg)
+	case StateCalendar:
+		a.calendar.SetNotification(msg)
+	case StateDayView:
+		a.dayView.SetNotification(msg)
+	case StateDayEdit:
+		a.editor.SetNotificationMsg(msg, true)
+	case StateRangeView:
+		a.rangeView.SetNotification(msg)
+	default:
+		a.dateInputError = msg
+	}
 }
 
 func (a *App) loadDayView(date time.Time) (tea.Model, tea.Cmd) {
 	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 
-	day, err := a.ledgerService.GetDay(date)
-	if err != nil {
-		day = ledger.NewDay(date)
+	day, err := a.ledgerService.OpenDay(date)
+	if day == nil {
+		a.showLoadError(date, err)
+		return a, nil
 	}
 
 	a.currentDay = day
 	a.currentDate = date
 	a.dayView = NewDayViewModel(a.styles, day)
 	a.dayView.SetSize(a.width, a.height)
+	if err != nil {
+		a.dayView.SetNotification(err.Error())
+	}
 	a.state = StateDayView
 
 	return a, nil
@@ -449,16 +882,48 @@ func (a *App) loadRangeView() (tea.Model, tea.Cmd) {
 	dateRange, err := a.ledgerService.GetDateRange(a.rangeStartDate, a.rangeEndDate)
 	if err != nil {
 		dateRange = ledger.NewDateRange(a.rangeStartDate, a.rangeEndDate)
+		dateRange.Currencies = a.ledgerService.GetCurrencies()
 	}
 
 	a.currentDateRange = dateRange
 	a.rangeView = NewRangeViewModel(a.styles, dateRange)
+	if budgets, err := a.ledgerService.BudgetHistory(a.rangeStartDate, a.rangeEndDate, a.converter.GetRate()); err == nil {
+		a.rangeView.SetBudgets(budgets)
+	}
+	if values, batches, err := a.ledgerService.ValueCash(a.rangeEndDate); err == nil {
+		a.rangeView.SetCashValues(values, batches)
+	}
+	if pending, err := a.ledgerService.PendingRecurring(a.rangeEndDate); err == nil && pending > 0 {
+		a.rangeView.SetNotification(fmt.Sprintf("%d recurring entries due aren't added yet; open a day to add them", pending))
+	}
 	a.rangeView.SetSize(a.width, a.height)
 	a.state = StateRangeView
 
 	return a, nil
 }
 
+// loadReport loads the query range and shows its report
+func (a *App) loadReport() (tea.Model, tea.Cmd) {
+	dateRange, err := a.ledgerService.GetDateRange(a.rangeStartDate, a.rangeEndDate)
+	if err != nil {
+		dateRange = ledger.NewDateRange(a.rangeStartDate, a.rangeEndDate)
+		dateRange.Currencies = a.ledgerService.GetCurrencies()
+	}
+
+	a.currentDateRange = dateRange
+	a.prevState = StateMenu
+	return a.loadReportView(dateRange, "")
+}
+
+func (a *App) loadReportView(dateRange *ledger.DateRange, query string) (tea.Model, tea.Cmd) {
+	values, _, _ := a.ledgerService.ValueCash(dateRange.End)
+	a.reportView = NewReportModel(a.styles, dateRange, query, values)
+	a.reportView.SetSize(a.width, a.height)
+	a.state = StateReport
+
+	return a, nil
+}
+
 // View renders the application
 func (a *App) View() string {
 	switch a.state {
@@ -476,6 +941,16 @@ func (a *App) View() string {
 		return a.renderQueryStartDate()
 	case StateQueryEndDate:
 		return a.renderQueryEndDate()
+	case StateImport:
+		return a.importView.View()
+	case StateReport:
+		return a.reportView.View()
+	case StateCalendar:
+		return a.calendar.View()
+	case StateRecurring:
+		return a.recurring.View()
+	case StateBalances:
+		return a.balances.View()
 	}
 
 	return ""
@@ -0,0 +1,223 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ledger-a/internal/ledger"
+)
+
+// BalancesAction represents an action taken in the balances view
+type BalancesAction int
+
+const (
+	BalancesActionNone BalancesAction = iota
+	BalancesActionBack
+	BalancesActionSettle // Record a settlement with the selected person
+)
+
+// BalancesModel shows who owes whom across the shared entries of a date range
+type BalancesModel struct {
+	dateRange    *ledger.DateRange
+	query        string
+	people       []ledger.Balance // Everyone but you
+	you          ledger.Balance
+	shared       int // Number of shared entries counted
+	selectedIdx  int
+	confirming   bool // Waiting for y/n before settling up
+	styles       *Styles
+	width        int
+	height       int
+	notification string
+	notifyError  bool
+}
+
+// NewBalancesModel creates a balances view for the entries of a date range that
+// match the query
+func NewBalancesModel(styles *Styles, dateRange *ledger.DateRange, query string) BalancesModel {
+	m := BalancesModel{
+		styles: styles,
+		width:  80,
+		height: 24,
+	}
+	m.SetDateRange(dateRange, query)
+	return m
+}
+
+// SetDateRange recomputes the balances, keeping the selection where possible
+func (m *BalancesModel) SetDateRange(dateRange *ledger.DateRange, query string) {
+	m.dateRange = dateRange
+	m.query = query
+	m.people = nil
+	m.you = ledger.Balance{Person: ledger.Me}
+	m.shared = 0
+
+	entries := dateRange.AllEntries(query)
+	for _, e := range entries {
+		if e.IsShared() {
+			m.shared++
+		}
+	}
+	for _, b := range ledger.Balances(entries) {
+		if ledger.IsMe(b.Person) {
+			m.you = b
+		} else {
+			m.people = append(m.people, b)
+		}
+	}
+	m.selectedIdx = max(0, min(m.selectedIdx, len(m.people)-1))
+}
+
+// Init initializes the balances view
+func (m BalancesModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the balances view
+func (m BalancesModel) Update(msg tea.Msg) (BalancesModel, tea.Cmd, BalancesAction) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil, BalancesActionNone
+	}
+
+	if m.confirming {
+		m.confirming = false
+		m.notification = ""
+		if key.String() == "y" {
+			return m, nil, BalancesActionSettle
+		}
+		return m, nil, BalancesActionNone
+	}
+
+	m.notification = ""
+	switch key.String() {
+	case "up", "k":
+		if m.selectedIdx > 0 {
+			m.selectedIdx--
+		}
+	case "down", "j":
+		if m.selectedIdx < len(m.people)-1 {
+			m.selectedIdx++
+		}
+	case "s":
+		b, ok := m.GetSelectedBalance()
+		if !ok {
+			return m, nil, BalancesActionNone
+		}
+		if b.IsSettled() {
+			m.SetNotification(b.Person+" is already settled up", false)
+			return m, nil, BalancesActionNone
+		}
+		m.confirming = true
+		m.SetNotification(m.settlementPrompt(b), false)
+	case "esc", "q":
+		return m, nil, BalancesActionBack
+	}
+	return m, nil, BalancesActionNone
+}
+
+// settlementPrompt asks to confirm the payment that settles a balance
+func (m BalancesModel) settlementPrompt(b ledger.Balance) string {
+	amounts := m.formatAmounts(b)
+	if b.Owes() {
+		return fmt.Sprintf("Record %s paying you %s today? (y/n)", b.Person, amounts)
+	}
+	return fmt.Sprintf("Record paying %s %s today? (y/n)", b.Person, amounts)
+}
+
+// formatAmounts formats a balance as positive amounts in both currencies
+func (m BalancesModel) formatAmounts(b ledger.Balance) string {
+	currencies := m.dateRange.Currencies
+	return formatCurrency(b.Local.Abs(), currencies.Local) + " (" + formatCurrency(b.Home.Abs(), currencies.Home) + ")"
+}
+
+// GetSelectedBalance returns the selected person's balance, and false if there is no one
+func (m BalancesModel) GetSelectedBalance() (ledger.Balance, bool) {
+	if m.selectedIdx >= len(m.people) {
+		return ledger.Balance{}, false
+	}
+	return m.people[m.selectedIdx], true
+}
+
+// SetNotification shows a message until the next key press
+func (m *BalancesModel) SetNotification(msg string, isError bool) {
+	m.notification = msg
+	m.notifyError = isError
+}
+
+// View renders the balances view
+func (m BalancesModel) View() string {
+	var content strings.Builder
+	currencies := m.dateRange.Currencies
+
+	subtitle := fmt.Sprintf("%d shared entries", m.shared)
+	if m.shared == 1 {
+		subtitle = "1 shared entry"
+	}
+	if m.query != "" {
+		subtitle += " matching '" + m.query + "'"
+	}
+	content.WriteString(m.styles.Subtitle.Render(subtitle))
+	content.WriteString("\n\n")
+
+	cols := []reportColumn{
+		{title: "Person", width: 16},
+		{title: "", width: 10},
+		{title: currencies.Home.Code, width: 14, right: true},
+		{title: currencies.Local.Code, width: 18, right: true},
+	}
+	var rows [][]string
+	for _, b := range m.people {
+		status := "owes you"
+		switch {
+		case b.IsSettled():
+			status = "settled"
+		case !b.Owes():
+			status = "you owe"
+		}
+		rows = append(rows, []string{
+			b.Person, status,
+			formatCurrency(b.Home.Abs(), currencies.Home),
+			formatCurrency(b.Local.Abs(), currencies.Local),
+		})
+	}
+	if len(rows) == 0 {
+		rows = append(rows, []string{"No one", "", "", ""})
+	}
+
+	// Your own position is what everyone else's adds up to
+	yourStatus := "owed"
+	switch {
+	case m.you.IsSettled():
+		yourStatus = "settled"
+	case m.you.Owes():
+		yourStatus = "you owe"
+	}
+	total := []string{
+		"You", yourStatus,
+		formatCurrency(m.you.Home.Abs(), currencies.Home),
+		formatCurrency(m.you.Local.Abs(), currencies.Local),
+	}
+	lines := renderColumnTable(m.styles, cols, rows, total, m.selectedIdx)
+	content.WriteString(strings.Join(lines, "\n"))
+
+	help := m.styles.HelpKey.Render("↑/↓") + m.styles.HelpDesc.Render(" select  ") +
+		m.styles.HelpKey.Render("s") + m.styles.HelpDesc.Render(" settle up  ") +
+		m.styles.HelpKey.Render("q") + m.styles.HelpDesc.Render(" back")
+	footer := RenderRibbonFooter("", help, m.styles)
+
+	notification := m.notification
+	if m.notifyError && notification != "" {
+		notification = "Error: " + notification
+	}
+	title := "Balances: " + m.dateRange.FormatRangeDisplay()
+	return RenderBoxWithTitle(content.String(), title, footer, notification, m.width, m.height)
+}
+
+// SetSize sets the view dimensions
+func (m *BalancesModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
@@ -0,0 +1,344 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"ledger-a/internal/ledger"
+)
+
+// CalendarAction represents the result of a key press in the calendar
+type CalendarAction int
+
+const (
+	CalendarActionNone CalendarAction = iota
+	CalendarActionBack
+	CalendarActionOpenDay // Open the editor on the selected day
+	CalendarActionRange   // Show the selected start/end range
+)
+
+// CalendarModel is a month grid for picking a day or a range. Days with data are
+// highlighted and show their home-currency total and screen time.
+type CalendarModel struct {
+	service *ledger.Service
+	styles  *Styles
+	width   int
+	height  int
+
+	cursor     time.Time // Selected day
+	anchor     time.Time // Start of a range being selected (zero if none)
+	rangeStart time.Time // Last selected range
+	rangeEnd   time.Time
+	available  map[string]bool        // Days with files on disk, by YYYY-MM-DD
+	days       map[string]*ledger.Day // Loaded days of the displayed month
+	monthTotal ledger.Money
+	loaded     time.Time // First day of the loaded month
+
+	notification string
+}
+
+// NewCalendarModel creates a calendar showing the month of the given date
+func NewCalendarModel(styles *Styles, service *ledger.Service, date time.Time) CalendarModel {
+	m := CalendarModel{
+		service: service,
+		styles:  styles,
+		width:   80,
+		height:  24,
+		cursor:  time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local),
+	}
+	m.Reload()
+	return m
+}
+
+// Reload rereads which days have data and the displayed month (e.g., after editing a day)
+func (m *CalendarModel) Reload() {
+	m.available = make(map[string]bool)
+	dates, err := m.service.ListAvailableDates()
+	if err != nil {
+		m.notification = "Error: " + err.Error()
+	}
+	for _, date := range dates {
+		m.available[date.Format(ledger.DateFormat)] = true
+	}
+
+	m.loaded = time.Time{}
+	m.loadMonth()
+}
+
+// loadMonth loads the days of the cursor's month if it isn't loaded yet
+func (m *CalendarModel) loadMonth() {
+	first := time.Date(m.cursor.Year(), m.cursor.Month(), 1, 0, 0, 0, 0, time.Local)
+	if first.Equal(m.loaded) {
+		return
+	}
+	m.loaded = first
+	m.days = make(map[string]*ledger.Day)
+	m.monthTotal = 0
+
+	dateRange, err := m.service.GetDateRange(first, first.AddDate(0, 1, -1))
+	if err != nil {
+		m.notification = "Error: " + err.Error()
+		return
+	}
+	for _, day := range dateRange.Days {
+		m.days[day.Date.Format(ledger.DateFormat)] = day
+	}
+	m.monthTotal = dateRange.TotalHome()
+}
+
+// Init initializes the calendar
+func (m CalendarModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the calendar
+func (m CalendarModel) Update(msg tea.Msg) (CalendarModel, tea.Cmd, CalendarAction) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.notification = ""
+		switch msg.String() {
+		case "left", "h":
+			m.move(m.cursor.AddDate(0, 0, -1))
+		case "right", "l":
+			m.move(m.cursor.AddDate(0, 0, 1))
+		case "up", "k":
+			m.move(m.cursor.AddDate(0, 0, -7))
+		case "down", "j":
+			m.move(m.cursor.AddDate(0, 0, 7))
+		case "pgup":
+			m.move(addMonths(m.cursor, -1))
+		case "pgdown":
+			m.move(addMonths(m.cursor, 1))
+		case "t":
+			m.move(ledger.Today())
+		case "enter":
+			return m, nil, CalendarActionOpenDay
+		case "v", " ":
+			if m.anchor.IsZero() {
+				m.anchor = m.cursor
+				m.notification = "Range from " + m.anchor.Format("01/02/2006") + ": move and press v again"
+				return m, nil, CalendarActionNone
+			}
+			m.rangeStart, m.rangeEnd = m.anchor, m.cursor
+			if m.rangeEnd.Before(m.rangeStart) {
+				m.rangeStart, m.rangeEnd = m.rangeEnd, m.rangeStart
+			}
+			m.anchor = time.Time{}
+			return m, nil, CalendarActionRange
+		case "esc", "q":
+			if !m.anchor.IsZero() {
+				m.anchor = time.Time{}
+				return m, nil, CalendarActionNone
+			}
+			return m, nil, CalendarActionBack
+		}
+	}
+
+	return m, nil, CalendarActionNone
+}
+
+// move puts the cursor on a date, loading its month if needed
+func (m *CalendarModel) move(date time.Time) {
+	m.cursor = date
+	m.loadMonth()
+}
+
+// addMonths moves a date by whole months, clamping the day to the target month's length
+func addMonths(date time.Time, months int) time.Time {
+	first := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, date.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(date.Day(), last)-1)
+}
+
+// View renders the calendar
+func (m CalendarModel) View() string {
+	var content strings.Builder
+
+	content.WriteString(m.renderGrid())
+	content.WriteString("\n")
+	content.WriteString(m.renderSelection())
+
+	help := m.styles.HelpKey.Render("hjkl") + m.styles.HelpDesc.Render(" move  ") +
+		m.styles.HelpKey.Render("PgUp/PgDn") + m.styles.HelpDesc.Render(" month  ") +
+		m.styles.HelpKey.Render("t") + m.styles.HelpDesc.Render(" today  ") +
+		m.styles.HelpKey.Render("Enter") + m.styles.HelpDesc.Render(" open  ") +
+		m.styles.HelpKey.Render("v") + m.styles.HelpDesc.Render(" range  ") +
+		m.styles.HelpKey.Render("Esc") + m.styles.HelpDesc.Render(" back")
+	footer := RenderRibbonFooter("", help, m.styles)
+
+	title := "Calendar: " + m.cursor.Format("January 2006")
+	if m.monthTotal != 0 {
+		title += " (" + formatCurrency(m.monthTotal, m.service.GetCurrencies().Home) + ")"
+	}
+	return RenderBoxWithTitle(content.String(), title, footer, m.notification, m.width, m.height)
+}
+
+// cellLayout returns the width of a day cell and the padding on each side of it
+// for the current window size; narrow windows drop the padding
+func (m CalendarModel) cellLayout() (int, int) {
+	column := (m.width - 12) / 7 // Box border, padding and the grid's own borders
+	if column >= 13 {
+		return min(14, column-2), 1
+	}
+	return max(8, column), 0
+}
+
+// renderGrid renders the month as weeks from Monday to Sunday, two lines per day.
+// Short windows leave out the lines between weeks.
+func (m CalendarModel) renderGrid() string {
+	w, pad := m.cellLayout()
+	padding := strings.Repeat(" ", pad)
+	compact := m.height < 28
+	border := m.styles.TableBorder
+
+	line := func(left, mid, right string) string {
+		parts := make([]string, 7)
+		for i := range parts {
+			parts[i] = strings.Repeat("─", w+2*pad)
+		}
+		return border.Render(left + strings.Join(parts, mid) + right)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(line("┌", "┬", "┐") + "\n")
+	sb.WriteString(border.Render("│"))
+	for _, name := range []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"} {
+		sb.WriteString(padding + m.styles.TableHeader.Width(w).Align(lipgloss.Center).Render(name) + padding + border.Render("│"))
+	}
+	sb.WriteString("\n" + line("├", "┼", "┤") + "\n")
+
+	first := time.Date(m.cursor.Year(), m.cursor.Month(), 1, 0, 0, 0, 0, time.Local)
+	last := first.AddDate(0, 1, -1)
+	start := first.AddDate(0, 0, -((int(first.Weekday()) + 6) % 7))
+	for week := start; !week.After(last); week = week.AddDate(0, 0, 7) {
+		if week.After(start) && !compact {
+			sb.WriteString(line("├", "┼", "┤") + "\n")
+		}
+
+		var top, bottom strings.Builder
+		top.WriteString(border.Render("│"))
+		bottom.WriteString(border.Render("│"))
+		for i := 0; i < 7; i++ {
+			date := week.AddDate(0, 0, i)
+			upper, lower := m.renderCell(date, w)
+			top.WriteString(padding + upper + padding + border.Render("│"))
+			bottom.WriteString(padding + lower + padding + border.Render("│"))
+		}
+		sb.WriteString(top.String() + "\n" + bottom.String() + "\n")
+	}
+	sb.WriteString(line("└", "┴", "┘"))
+
+	return sb.String()
+}
+
+// renderCell renders a day as two lines: the day number with markers and screen
+// time, then the home-currency total
+func (m CalendarModel) renderCell(date time.Time, w int) (string, string) {
+	if date.Month() != m.cursor.Month() {
+		blank := lipgloss.NewStyle().Width(w).Render("")
+		return blank, blank
+	}
+
+	key := date.Format(ledger.DateFormat)
+	day := m.days[key]
+
+	number := fmt.Sprintf("%2d", date.Day())
+	markers, screenTime, total := "", "", ""
+	if day != nil {
+		if len(day.Entries) > 0 {
+			markers += "•"
+			total = formatCurrency(day.TotalHome(), m.service.GetCurrencies().Home)
+		}
+		if day.HasJournal() {
+			markers += "*"
+		}
+		screenTime = day.ScreenTime
+	}
+
+	numberStyle := m.styles.Subtitle
+	if m.available[key] {
+		numberStyle = m.styles.TableCellDate
+	}
+	if date.Equal(ledger.Today()) {
+		numberStyle = m.styles.TableCellDate.Underline(true)
+	}
+
+	cell := lipgloss.NewStyle().Width(w)
+	switch {
+	case date.Equal(m.cursor):
+		cell = m.styles.DatePickerSelected.Width(w).Align(lipgloss.Left)
+		numberStyle = lipgloss.NewStyle()
+	case m.inSelection(date):
+		cell = m.styles.TableRowSelected.Width(w)
+		numberStyle = lipgloss.NewStyle()
+	}
+
+	left := numberStyle.Render(number) + markers
+	gap := max(1, w-lipgloss.Width(left)-lipgloss.Width(screenTime))
+	upper := cell.Render(truncateStr(left+strings.Repeat(" ", gap)+screenTime, w))
+	lower := cell.Align(lipgloss.Right).Render(truncateStr(total, w))
+	return upper, lower
+}
+
+// inSelection returns true for days between the range anchor and the cursor
+func (m CalendarModel) inSelection(date time.Time) bool {
+	if m.anchor.IsZero() {
+		return false
+	}
+	start, end := m.anchor, m.cursor
+	if end.Before(start) {
+		start, end = end, start
+	}
+	return !date.Before(start) && !date.After(end)
+}
+
+// renderSelection describes the selected day or range being selected
+func (m CalendarModel) renderSelection() string {
+	if !m.anchor.IsZero() {
+		start, end := m.anchor, m.cursor
+		if end.Before(start) {
+			start, end = end, start
+		}
+		days := int(end.Sub(start).Hours()/24+0.5) + 1
+		return m.styles.Subtitle.Render(fmt.Sprintf("%s - %s (%d days)", start.Format("01/02/2006"), end.Format("01/02/2006"), days))
+	}
+
+	summary := m.cursor.Format("Monday, January 2, 2006")
+	if day := m.days[m.cursor.Format(ledger.DateFormat)]; day != nil {
+		if n := len(day.Entries); n > 0 {
+			summary += fmt.Sprintf("  ·  %d entries, %s", n, formatCurrency(day.TotalHome(), m.service.GetCurrencies().Home))
+		}
+		if day.ScreenTime != "" {
+			summary += "  ·  screen " + day.ScreenTime
+		}
+		if day.HasJournal() {
+			summary += "  ·  journal"
+		}
+	}
+	return m.styles.Subtitle.Render(summary)
+}
+
+// SetNotification sets a notification shown until the next key
+func (m *CalendarModel) SetNotification(msg string) {
+	m.notification = msg
+}
+
+// SetSize sets the view dimensions
+func (m *CalendarModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// GetSelectedDate returns the day under the cursor
+func (m CalendarModel) GetSelectedDate() time.Time {
+	return m.cursor
+}
+
+// GetSelectedRange returns the last range selected with v, start first
+func (m CalendarModel) GetSelectedRange() (time.Time, time.Time) {
+	return m.rangeStart, m.rangeEnd
+}
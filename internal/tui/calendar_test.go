@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ledger-a/internal/ledger"
+)
+
+// keyMsg returns the key press for a key as tea.KeyMsg.String names it
+func keyMsg(s string) tea.KeyMsg {
+	switch s {
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "pgup":
+		return tea.KeyMsg{Type: tea.KeyPgUp}
+	case "pgdown":
+		return tea.KeyMsg{Type: tea.KeyPgDown}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		date   time.Time
+		months int
+		want   time.Time
+	}{
+		{time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local), 1, time.Date(2024, 2, 15, 0, 0, 0, 0, time.Local)},
+		{time.Date(2024, 1, 31, 0, 0, 0, 0, time.Local), 1, time.Date(2024, 2, 29, 0, 0, 0, 0, time.Local)},
+		{time.Date(2024, 3, 31, 0, 0, 0, 0, time.Local), -1, time.Date(2024, 2, 29, 0, 0, 0, 0, time.Local)},
+		{time.Date(2024, 12, 31, 0, 0, 0, 0, time.Local), 2, time.Date(2025, 2, 28, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		if got := addMonths(tt.date, tt.months); !got.Equal(tt.want) {
+			t.Errorf("addMonths(%s, %d) = %s, want %s", tt.date.Format(ledger.DateFormat), tt.months,
+				got.Format(ledger.DateFormat), tt.want.Format(ledger.DateFormat))
+		}
+	}
+}
+
+func TestCalendarNavigation(t *testing.T) {
+	service := ledger.NewServiceWithCurrencies(t.TempDir(), ledger.DefaultPair)
+	dinner := time.Date(2024, 2, 10, 0, 0, 0, 0, time.Local)
+	day := service.NewDay(dinner)
+	day.AddEntry(ledger.NewEntry(dinner, "Dinner", -1500, -150000, ""))
+	day.ScreenTime = "2h"
+	if err := service.SaveDay(day); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewCalendarModel(DefaultStyles(), service, time.Date(2024, 1, 31, 0, 0, 0, 0, time.Local))
+	press := func(keys ...string) CalendarAction {
+		t.Helper()
+		action := CalendarActionNone
+		for _, k := range keys {
+			m, _, action = m.Update(keyMsg(k))
+		}
+		return action
+	}
+
+	// A month down clamps to the end of February and loads its days
+	press("pgdown")
+	if want := time.Date(2024, 2, 29, 0, 0, 0, 0, time.Local); !m.GetSelectedDate().Equal(want) {
+		t.Errorf("cursor = %s, want %s", m.GetSelectedDate().Format(ledger.DateFormat), want.Format(ledger.DateFormat))
+	}
+	if !m.available["2024-02-10"] || m.monthTotal != -1500 {
+		t.Errorf("February has data %v and total %d, want the dinner's -1500", m.available["2024-02-10"], m.monthTotal)
+	}
+	if view := m.View(); !strings.Contains(view, "February 2024") || !strings.Contains(view, "2h") {
+		t.Error("view doesn't show the month and the dinner's screen time")
+	}
+
+	// Up a week and back with hjkl or the arrows, then open the day
+	press("k", "down", "h")
+	if want := time.Date(2024, 2, 28, 0, 0, 0, 0, time.Local); !m.GetSelectedDate().Equal(want) {
+		t.Errorf("cursor = %s, want %s", m.GetSelectedDate().Format(ledger.DateFormat), want.Format(ledger.DateFormat))
+	}
+	if action := press("enter"); action != CalendarActionOpenDay {
+		t.Errorf("enter = %v, want CalendarActionOpenDay", action)
+	}
+
+	// Two presses select a range, whichever way the cursor moved
+	if action := press("v", "h", "h", "v"); action != CalendarActionRange {
+		t.Errorf("second v = %v, want CalendarActionRange", action)
+	}
+	start, end := m.GetSelectedRange()
+	if !start.Equal(time.Date(2024, 2, 26, 0, 0, 0, 0, time.Local)) || !end.Equal(time.Date(2024, 2, 28, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("range = %s - %s, want 2024-02-26 - 2024-02-28", start.Format(ledger.DateFormat), end.Format(ledger.DateFormat))
+	}
+
+	// Esc cancels a range being selected before leaving the calendar
+	if action := press("v", "esc"); action != CalendarActionNone {
+		t.Errorf("esc while selecting = %v, want CalendarActionNone", action)
+	}
+	if action := press("esc"); action != CalendarActionBack {
+		t.Errorf("esc = %v, want CalendarActionBack", action)
+	}
+}
@@ -288,14 +288,14 @@ func (m DayViewModel) renderEntryRow(idx int, descWidth int) string {
 	sb.WriteString(strings.Repeat(" ", descWidth-len(desc)))
 	sb.WriteString(" ")
 
-	// CAD
-	cadStr := formatCurrency(entry.CAD, "CAD")
-	sb.WriteString(fmt.Sprintf("%12s", cadStr))
+	// Home
+	homeStr := formatEntryAmount(entry, entry.Home, m.day.Currencies.Home)
+	sb.WriteString(fmt.Sprintf("%12s", homeStr))
 	sb.WriteString(" ")
 
-	// IDR
-	idrStr := formatCurrency(entry.IDR, "IDR")
-	sb.WriteString(fmt.Sprintf("%12s", idrStr))
+	// Local
+	localStr := formatEntryAmount(entry, entry.Local, m.day.Currencies.Local)
+	sb.WriteString(fmt.Sprintf("%12s", localStr))
 
 	return sb.String()
 }
@@ -421,7 +421,7 @@ func (m DayViewModel) renderTableLines(contentWidth, maxRows int) []string {
 }
 
 // renderTableRowCompact renders a compact table row for split view
-func (m DayViewModel) renderTableRowCompact(idx int, entry *ledger.Entry, descWidth, idrWidth, cadWidth int) string {
+func (m DayViewModel) renderTableRowCompact(idx int, entry *ledger.Entry, descWidth, catWidth, localWidth, homeWidth int) string {
 	border := m.styles.TableBorder
 
 	rowStyle := m.styles.TableRow
@@ -443,36 +443,40 @@ func (m DayViewModel) renderTableRowCompact(idx int, entry *ledger.Entry, descWi
 	sb.WriteString(" " + rowStyle.Width(descWidth).Render(desc) + " ")
 	sb.WriteString(border.Render("│"))
 
-	// IDR first
-	idrStyle := m.styles.ValueNeutral
-	if entry.IDR > 0 {
-		idrStyle = m.styles.ValuePositive
-	} else if entry.IDR < 0 {
-		idrStyle = m.styles.ValueNegative
+	// Category
+	sb.WriteString(" " + m.styles.TableRow.Width(catWidth).Render(truncateStr(entry.CategoryLabel(), catWidth)) + " ")
+	sb.WriteString(border.Render("│"))
+
+	// Local first
+	localStyle := m.styles.ValueNeutral
+	if entry.Local > 0 {
+		localStyle = m.styles.ValuePositive
+	} else if entry.Local < 0 {
+		localStyle = m.styles.ValueNegative
 	}
-	sb.WriteString(" " + idrStyle.Width(idrWidth).Render(formatCurrency(entry.IDR, "IDR")) + " ")
+	sb.WriteString(" " + localStyle.Width(localWidth).Render(formatEntryAmount(entry, entry.Local, m.day.Currencies.Local)) + " ")
 	sb.WriteString(border.Render("│"))
 
-	// CAD second
-	cadStyle := m.styles.ValueNeutral
-	if entry.CAD > 0 {
-		cadStyle = m.styles.ValuePositive
-	} else if entry.CAD < 0 {
-		cadStyle = m.styles.ValueNegative
+	// Home second
+	homeStyle := m.styles.ValueNeutral
+	if entry.Home > 0 {
+		homeStyle = m.styles.ValuePositive
+	} else if entry.Home < 0 {
+		homeStyle = m.styles.ValueNegative
 	}
-	sb.WriteString(" " + cadStyle.Width(cadWidth).Render(formatCurrency(entry.CAD, "CAD")) + " ")
+	sb.WriteString(" " + homeStyle.Width(homeWidth).Render(formatEntryAmount(entry, entry.Home, m.day.Currencies.Home)) + " ")
 	sb.WriteString(border.Render("│"))
 
 	return sb.String()
 }
 
 func (m DayViewModel) renderTableWithWidth(panelWidth, maxRows int) string {
-	// Fixed widths for CAD and IDR columns
-	cadWidth := 14
-	idrWidth := 16
+	// Fixed widths for home and local columns
+	homeWidth := 14
+	localWidth := 16
 
 	// Calculate description width based on available panel width
-	descWidth := panelWidth - cadWidth - idrWidth - 12
+	descWidth := panelWidth - homeWidth - localWidth - 12
 	if descWidth < 15 {
 		descWidth = 15
 	}
@@ -481,21 +485,21 @@ func (m DayViewModel) renderTableWithWidth(panelWidth, maxRows int) string {
 	border := m.styles.TableBorder
 
 	// Top border
-	sb.WriteString(border.Render("┌" + strings.Repeat("─", descWidth+2) + "┬" + strings.Repeat("─", idrWidth+2) + "┬" + strings.Repeat("─", cadWidth+2) + "┐"))
+	sb.WriteString(border.Render("┌" + strings.Repeat("─", descWidth+2) + "┬" + strings.Repeat("─", localWidth+2) + "┬" + strings.Repeat("─", homeWidth+2) + "┐"))
 	sb.WriteString("\n")
 
 	// Header
 	sb.WriteString(border.Render("│"))
 	sb.WriteString(" " + m.styles.TableHeader.Width(descWidth).Render("Description") + " ")
 	sb.WriteString(border.Render("│"))
-	sb.WriteString(" " + m.styles.TableHeader.Width(idrWidth).Render("IDR") + " ")
+	sb.WriteString(" " + m.styles.TableHeader.Width(localWidth).Render(m.day.Currencies.Local.Code) + " ")
 	sb.WriteString(border.Render("│"))
-	sb.WriteString(" " + m.styles.TableHeader.Width(cadWidth).Render("CAD") + " ")
+	sb.WriteString(" " + m.styles.TableHeader.Width(homeWidth).Render(m.day.Currencies.Home.Code) + " ")
 	sb.WriteString(border.Render("│"))
 	sb.WriteString("\n")
 
 	// Header separator
-	sb.WriteString(border.Render("├" + strings.Repeat("─", descWidth+2) + "┼" + strings.Repeat("─", idrWidth+2) + "┼" + strings.Repeat("─", cadWidth+2) + "┤"))
+	sb.WriteString(border.Render("├" + strings.Repeat("─", descWidth+2) + "┼" + strings.Repeat("─", localWidth+2) + "┼" + strings.Repeat("─", homeWidth+2) + "┤"))
 	sb.WriteString("\n")
 
 	// Calculate visible rows
@@ -513,9 +517,9 @@ func (m DayViewModel) renderTableWithWidth(panelWidth, maxRows int) string {
 		}
 		sb.WriteString(" " + m.styles.Subtitle.Width(descWidth).Render(truncateStr(emptyMsg, descWidth)) + " ")
 		sb.WriteString(border.Render("│"))
-		sb.WriteString(" " + lipgloss.NewStyle().Width(idrWidth).Render("") + " ")
+		sb.WriteString(" " + lipgloss.NewStyle().Width(localWidth).Render("") + " ")
 		sb.WriteString(border.Render("│"))
-		sb.WriteString(" " + lipgloss.NewStyle().Width(cadWidth).Render("") + " ")
+		sb.WriteString(" " + lipgloss.NewStyle().Width(homeWidth).Render("") + " ")
 		sb.WriteString(border.Render("│"))
 		sb.WriteString("\n")
 	} else {
@@ -541,21 +545,21 @@ func (m DayViewModel) renderTableWithWidth(panelWidth, maxRows int) string {
 
 		for i := startIdx; i < endIdx; i++ {
 			entry := m.entries[i]
-			sb.WriteString(m.renderTableRowWithWidth(i, entry, descWidth, idrWidth, cadWidth))
+			sb.WriteString(m.renderTableRowWithWidth(i, entry, descWidth, localWidth, homeWidth))
 			sb.WriteString("\n")
 		}
 	}
 
 	// Separator before totals
-	sb.WriteString(border.Render("├" + strings.Repeat("─", descWidth+2) + "┼" + strings.Repeat("─", idrWidth+2) + "┼" + strings.Repeat("─", cadWidth+2) + "┤"))
+	sb.WriteString(border.Render("├" + strings.Repeat("─", descWidth+2) + "┼" + strings.Repeat("─", localWidth+2) + "┼" + strings.Repeat("─", homeWidth+2) + "┤"))
 	sb.WriteString("\n")
 
 	// Totals row
-	sb.WriteString(m.tableRenderer.RenderTotalsRowWithWidth(m.day, m.search.GetQuery(), descWidth, idrWidth, cadWidth))
+	sb.WriteString(m.tableRenderer.RenderTotalsRowWithWidth(m.day, m.search.GetQuery(), descWidth, localWidth, homeWidth))
 	sb.WriteString("\n")
 
 	// Bottom border
-	sb.WriteString(border.Render("└" + strings.Repeat("─", descWidth+2) + "┴" + strings.Repeat("─", idrWidth+2) + "┴" + strings.Repeat("─", cadWidth+2) + "┘"))
+	sb.WriteString(border.Render("└" + strings.Repeat("─", descWidth+2) + "┴" + strings.Repeat("─", localWidth+2) + "┴" + strings.Repeat("─", homeWidth+2) + "┘"))
 
 	return sb.String()
 }
@@ -564,7 +568,7 @@ func (m DayViewModel) renderTableRow(idx int, entry *ledger.Entry, descWidth int
 	return m.renderTableRowWithWidth(idx, entry, descWidth, 16, 14)
 }
 
-func (m DayViewModel) renderTableRowWithWidth(idx int, entry *ledger.Entry, descWidth, idrWidth, cadWidth int) string {
+func (m DayViewModel) renderTableRowWithWidth(idx int, entry *ledger.Entry, descWidth, localWidth, homeWidth int) string {
 	var sb strings.Builder
 	border := m.styles.TableBorder
 
@@ -586,24 +590,24 @@ func (m DayViewModel) renderTableRowWithWidth(idx int, entry *ledger.Entry, desc
 	sb.WriteString(" " + rowStyle.Width(descWidth).Render(desc) + " ")
 	sb.WriteString(border.Render("│"))
 
-	// IDR first
-	idrStyle := m.styles.ValueNeutral
-	if entry.IDR > 0 {
-		idrStyle = m.styles.ValuePositive
-	} else if entry.IDR < 0 {
-		idrStyle = m.styles.ValueNegative
+	// Local first
+	localStyle := m.styles.ValueNeutral
+	if entry.Local > 0 {
+		localStyle = m.styles.ValuePositive
+	} else if entry.Local < 0 {
+		localStyle = m.styles.ValueNegative
 	}
-	sb.WriteString(" " + idrStyle.Width(idrWidth).Render(formatCurrency(entry.IDR, "IDR")) + " ")
+	sb.WriteString(" " + localStyle.Width(localWidth).Render(formatEntryAmount(entry, entry.Local, m.day.Currencies.Local)) + " ")
 	sb.WriteString(border.Render("│"))
 
-	// CAD second
-	cadStyle := m.styles.ValueNeutral
-	if entry.CAD > 0 {
-		cadStyle = m.styles.ValuePositive
-	} else if entry.CAD < 0 {
-		cadStyle = m.styles.ValueNegative
+	// Home second
+	homeStyle := m.styles.ValueNeutral
+	if entry.Home > 0 {
+		homeStyle = m.styles.ValuePositive
+	} else if entry.Home < 0 {
+		homeStyle = m.styles.ValueNegative
 	}
-	sb.WriteString(" " + cadStyle.Width(cadWidth).Render(formatCurrency(entry.CAD, "CAD")) + " ")
+	sb.WriteString(" " + homeStyle.Width(homeWidth).Render(formatEntryAmount(entry, entry.Home, m.day.Currencies.Home)) + " ")
 	sb.WriteString(border.Render("│"))
 
 	return sb.String()
@@ -668,33 +672,32 @@ func (m *DayViewModel) ClearNotification() {
 
 // formatCurrencyCompact formats currency in abbreviated form for narrow displays
 // e.g., "6.5M" instead of "6,455,930", "$530" instead of "$530.00"
-func formatCurrencyCompact(amount float64, currency string) string {
-	absAmount := amount
+func formatCurrencyCompact(amount ledger.Money, currency ledger.Currency) string {
+	absAmount := amount.Abs().Float(currency)
 	prefix := ""
 	if amount < 0 {
-		absAmount = -amount
 		prefix = "-"
 	}
 
-	if currency == "CAD" {
-		// For CAD, just drop decimals if it's a whole number
+	if currency.Decimals > 0 {
+		// For currencies with a minor unit, just drop decimals if it's a whole number
 		if absAmount >= 1000000 {
-			return prefix + fmt.Sprintf("$%.1fM", absAmount/1000000)
+			return prefix + fmt.Sprintf("%s%.1fM", currency.Symbol, absAmount/1000000)
 		} else if absAmount >= 1000 {
-			return prefix + fmt.Sprintf("$%.1fK", absAmount/1000)
+			return prefix + fmt.Sprintf("%s%.1fK", currency.Symbol, absAmount/1000)
 		} else if absAmount == float64(int(absAmount)) {
-			return prefix + fmt.Sprintf("$%.0f", absAmount)
+			return prefix + fmt.Sprintf("%s%.0f", currency.Symbol, absAmount)
 		}
-		return prefix + fmt.Sprintf("$%.2f", absAmount)
+		return prefix + currency.Symbol + amount.Abs().Format(currency)
 	}
 
-	// For IDR, abbreviate large numbers
+	// For currencies without a minor unit, abbreviate large numbers
 	if absAmount >= 1000000 {
 		return prefix + fmt.Sprintf("%.1fM", absAmount/1000000)
 	} else if absAmount >= 1000 {
 		return prefix + fmt.Sprintf("%.1fK", absAmount/1000)
 	}
-	return prefix + fmt.Sprintf("%.0f", absAmount)
+	return prefix + amount.Abs().Format(currency)
 }
 
 func max(a, b int) int {
@@ -2,8 +2,8 @@ package tui
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -23,6 +23,10 @@ const (
 	EditorModeInlineEdit
 	EditorModeScreenTime
 	EditorModeJournal
+	EditorModeGoTo
+	EditorModeSplit
+	EditorModeAccount
+	EditorModeReconcile
 )
 
 // EditorAction represents an action taken in the editor
@@ -33,6 +37,11 @@ const (
 	EditorActionBack
 	EditorActionSaved
 	EditorActionReload
+	EditorActionPrevDay     // Save and open the previous calendar day
+	EditorActionNextDay     // Save and open the next calendar day
+	EditorActionPrevDataDay // Save and open the previous day with data
+	EditorActionNextDataDay // Save and open the next day with data
+	EditorActionGoToDate    // Save and open the date from the go-to prompt
 )
 
 // Column represents which column is selected
@@ -40,8 +49,9 @@ type Column int
 
 const (
 	ColDescription Column = iota
-	ColIDR
-	ColCAD
+	ColCategory
+	ColLocal
+	ColHome
 )
 
 // EditorModel represents the day editor with vim-style keybindings
@@ -68,6 +78,19 @@ type EditorModel struct {
 
 	screenTimeInput textinput.Model
 
+	// Go-to-date prompt
+	goToInput textinput.Model
+	goToDate  time.Time
+
+	// Who paid for the selected entry and who shares it
+	splitInput textinput.Model
+
+	// Account the selected entry was paid from, and the cash counted when reconciling
+	accountInput    textinput.Model
+	reconcileInput  textinput.Model
+	accounts        []ledger.Account        // The first is the default for new entries
+	accountBalances []ledger.AccountBalance // Balances at the end of the day
+
 	// For journal editing
 	journalTextarea textarea.Model
 	journalOriginal string
@@ -75,10 +98,17 @@ type EditorModel struct {
 	converter   *currency.Converter
 	undoManager *ledger.UndoManager
 
+	categorySuggestions []string // Previously used categories for autocomplete
+
+	budgets []ledger.BudgetStatus // Spending against the budgets for the day's periods
+
+	chargeProfile ledger.ChargeProfile // Service charge and tax added by "++" and %
+
 	notification string
 	notifyError  bool
 
 	currencyStatus string
+	refreshing     bool // An exchange-rate refresh is in flight
 }
 
 // NewEditorModel creates a new editor model
@@ -92,6 +122,30 @@ func NewEditorModel(styles *Styles, day *ledger.Day, converter *currency.Convert
 	screenTimeInput.Width = 15
 	screenTimeInput.CharLimit = 10
 
+	goToInput := textinput.New()
+	goToInput.Placeholder = "MM/DD/YYYY"
+	goToInput.Width = 12
+	goToInput.CharLimit = 10
+	goToInput.Prompt = ""
+
+	splitInput := textinput.New()
+	splitInput.Placeholder = "alice: me alice"
+	splitInput.Width = 40
+	splitInput.CharLimit = 200
+	splitInput.Prompt = ""
+
+	accountInput := textinput.New()
+	accountInput.Placeholder = "wise > cash"
+	accountInput.Width = 30
+	accountInput.CharLimit = 100
+	accountInput.Prompt = ""
+
+	reconcileInput := textinput.New()
+	reconcileInput.Placeholder = "cash 850k"
+	reconcileInput.Width = 30
+	reconcileInput.CharLimit = 100
+	reconcileInput.Prompt = ""
+
 	journalTextarea := textarea.New()
 	journalTextarea.Placeholder = "Write your journal entry here..."
 	journalTextarea.ShowLineNumbers = false
@@ -119,9 +173,14 @@ func NewEditorModel(styles *Styles, day *ledger.Day, converter *currency.Convert
 		pendingDelete:   false,
 		editInput:       editInput,
 		screenTimeInput: screenTimeInput,
+		goToInput:       goToInput,
+		splitInput:      splitInput,
+		accountInput:    accountInput,
+		reconcileInput:  reconcileInput,
 		journalTextarea: journalTextarea,
 		converter:       converter,
 		undoManager:     undoManager,
+		chargeProfile:   ledger.DefaultChargeProfile,
 		currencyStatus:  converter.GetStatusMessage(),
 	}
 
@@ -144,6 +203,14 @@ func (m EditorModel) Update(msg tea.Msg) (EditorModel, tea.Cmd, EditorAction) {
 		return m.updateScreenTime(msg)
 	case EditorModeJournal:
 		return m.updateJournal(msg)
+	case EditorModeGoTo:
+		return m.updateGoTo(msg)
+	case EditorModeSplit:
+		return m.updateSplit(msg)
+	case EditorModeAccount:
+		return m.updateAccount(msg)
+	case EditorModeReconcile:
+		return m.updateReconcile(msg)
 	default:
 		return m.updateNormal(msg)
 	}
@@ -159,13 +226,19 @@ func (m EditorModel) updateJournal(msg tea.Msg) (EditorModel, tea.Cmd, EditorAct
 			m.mode = EditorModeNormal
 			m.journalTextarea.Blur()
 			m.setNotification("Journal saved", false)
+			if m.day.Journal != m.journalOriginal {
+				m.noteUndoErr(m.undoManager.RecordSetJournal(m.day.Date, m.journalOriginal, m.day.Journal))
+			}
 			return m, nil, EditorActionSaved
 		case "ctrl+d":
-			// Delete journal without confirmation
+			// Delete journal without confirmation; it can be restored with undo
 			m.day.Journal = ""
 			m.mode = EditorModeNormal
 			m.journalTextarea.Blur()
 			m.setNotification("Journal deleted", false)
+			if m.journalOriginal != "" {
+				m.noteUndoErr(m.undoManager.RecordSetJournal(m.day.Date, m.journalOriginal, ""))
+			}
 			return m, nil, EditorActionSaved
 		}
 	}
@@ -197,7 +270,7 @@ func (m EditorModel) updateNormal(msg tea.Msg) (EditorModel, tea.Cmd, EditorActi
 				m.selectedCol--
 			}
 		case "right":
-			if m.selectedCol < ColCAD {
+			if m.selectedCol < ColHome {
 				m.selectedCol++
 			}
 		case "/":
@@ -217,10 +290,10 @@ func (m EditorModel) updateNormal(msg tea.Msg) (EditorModel, tea.Cmd, EditorActi
 				m.pendingDelete = false
 				if len(m.entries) > 0 && m.selectedRow < len(m.entries) {
 					entry := m.entries[m.selectedRow]
-					m.undoManager.RecordDeleteEntry(m.day.Date, entry)
 					m.day.RemoveEntry(entry.ID)
 					m.updateFilteredEntries()
 					m.setNotification(fmt.Sprintf("Deleted '%s'", truncateStr(entry.Description, 20)), false)
+					m.noteUndoErr(m.undoManager.RecordDeleteEntry(m.day.Date, entry))
 					return m, nil, EditorActionSaved
 				}
 			} else {
@@ -241,6 +314,56 @@ func (m EditorModel) updateNormal(msg tea.Msg) (EditorModel, tea.Cmd, EditorActi
 			return m, textarea.Blink, EditorActionNone
 		case "u":
 			return m.performUndo()
+		case "ctrl+r":
+			return m.performRedo()
+		case "R":
+			return m.refreshRate()
+		case "%":
+			if len(m.entries) > 0 && m.selectedRow < len(m.entries) {
+				return m.toggleCharges(m.entries[m.selectedRow])
+			}
+		case "S":
+			if len(m.entries) > 0 && m.selectedRow < len(m.entries) {
+				m.mode = EditorModeSplit
+				m.splitInput.SetValue(m.splitInputValue(m.entries[m.selectedRow]))
+				m.splitInput.CursorEnd()
+				m.splitInput.Focus()
+				return m, textinput.Blink, EditorActionNone
+			}
+		case "A":
+			if len(m.accounts) == 0 {
+				m.setNotification("No accounts set up in config.json", true)
+				return m, nil, EditorActionNone
+			}
+			if len(m.entries) > 0 && m.selectedRow < len(m.entries) {
+				m.mode = EditorModeAccount
+				m.accountInput.SetValue(m.entries[m.selectedRow].FormatAccount())
+				m.accountInput.CursorEnd()
+				m.accountInput.Focus()
+				return m, textinput.Blink, EditorActionNone
+			}
+		case "=":
+			if len(m.accounts) == 0 {
+				m.setNotification("No accounts set up in config.json", true)
+				return m, nil, EditorActionNone
+			}
+			m.mode = EditorModeReconcile
+			m.reconcileInput.SetValue("")
+			m.reconcileInput.Focus()
+			return m, textinput.Blink, EditorActionNone
+		case "[", "H":
+			return m, nil, EditorActionPrevDay
+		case "]", "L":
+			return m, nil, EditorActionNextDay
+		case "{":
+			return m, nil, EditorActionPrevDataDay
+		case "}":
+			return m, nil, EditorActionNextDataDay
+		case "g":
+			m.mode = EditorModeGoTo
+			m.goToInput.SetValue("")
+			m.goToInput.Focus()
+			return m, textinput.Blink, EditorActionNone
 		case "esc":
 			if m.search.HasQuery() {
 				m.search.Clear()
@@ -258,6 +381,9 @@ func (m EditorModel) updateNormal(msg tea.Msg) (EditorModel, tea.Cmd, EditorActi
 
 func (m *EditorModel) addNewEntry() {
 	entry := ledger.NewEntry(m.day.Date, "", 0, 0, m.day.ScreenTime)
+	if len(m.accounts) > 0 {
+		entry.Account = m.accounts[0].Name
+	}
 	m.day.AddEntry(entry)
 	m.updateFilteredEntries()
 	m.selectedRow = len(m.entries) - 1
@@ -284,18 +410,27 @@ func (m *EditorModel) startInlineEdit() {
 		m.editInput.SetValue(entry.Description)
 		m.editInput.Width = 40
 		m.initialValue = entry.Description
-	case ColCAD:
-		cadStr := formatNumberWithCommas(entry.CAD, 2)
-		m.editInput.SetValue(cadStr)
-		m.initialValue = cadStr
-		m.editInput.Width = 12
-	case ColIDR:
-		idrStr := formatNumberWithCommas(entry.IDR, 0)
-		m.editInput.SetValue(idrStr)
-		m.initialValue = idrStr
-		m.editInput.Width = 14
+	case ColCategory:
+		label := entry.CategoryLabel()
+		m.editInput.SetValue(label)
+		m.editInput.Width = 16
+		m.initialValue = label
+	case ColHome:
+		homeStr := m.amountInput(entry, m.day.Currencies.Home)
+		m.editInput.SetValue(homeStr)
+		m.initialValue = homeStr
+		m.editInput.Width = 10 // Scrolls within the narrowest amount cell
+	case ColLocal:
+		localStr := m.amountInput(entry, m.day.Currencies.Local)
+		m.editInput.SetValue(localStr)
+		m.initialValue = localStr
+		m.editInput.Width = 10
 	}
 
+	// Autocomplete only applies to the category column
+	m.editInput.ShowSuggestions = m.selectedCol == ColCategory
+	m.editInput.SetSuggestions(m.categorySuggestions)
+
 	m.editInput.Focus()
 	m.editInput.CursorStart()
 	m.mode = EditorModeInlineEdit
@@ -308,14 +443,17 @@ func (m EditorModel) updateInlineEdit(msg tea.Msg) (EditorModel, tea.Cmd, Editor
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "tab":
-			// Save current and move to next column
-			m.saveCurrentCell(entry)
-			if m.selectedCol < ColCAD {
+			// Accept the category suggestion, then save and move to next column
+			m.acceptCategorySuggestion()
+			if !m.saveCurrentCell(entry) {
+				return m, nil, EditorActionNone
+			}
+			if m.selectedCol < ColHome {
 				m.selectedCol++
 				m.startInlineEdit()
 				return m, textinput.Blink, EditorActionNone
 			} else {
-				// At CAD, tab wraps - but if new entry, save instead
+				// At the home column, tab wraps - but if new entry, save instead
 				if m.isNewEntry {
 					return m.finishEdit(entry, true)
 				}
@@ -326,7 +464,9 @@ func (m EditorModel) updateInlineEdit(msg tea.Msg) (EditorModel, tea.Cmd, Editor
 
 		case "shift+tab":
 			// Save current and move to previous column
-			m.saveCurrentCell(entry)
+			if !m.saveCurrentCell(entry) {
+				return m, nil, EditorActionNone
+			}
 			if m.selectedCol > ColDescription {
 				m.selectedCol--
 				m.startInlineEdit()
@@ -344,14 +484,16 @@ func (m EditorModel) updateInlineEdit(msg tea.Msg) (EditorModel, tea.Cmd, Editor
 					m.isNewEntry = false
 					return m, nil, EditorActionNone
 				}
-				m.selectedCol = ColCAD
+				m.selectedCol = ColHome
 				m.startInlineEdit()
 				return m, textinput.Blink, EditorActionNone
 			}
 
-		case "up", "k":
+		case "up":
 			// Save and move up
-			m.saveCurrentCell(entry)
+			if !m.saveCurrentCell(entry) {
+				return m, nil, EditorActionNone
+			}
 			if m.selectedRow > 0 {
 				m.finishEdit(entry, false)
 				m.selectedRow--
@@ -361,9 +503,11 @@ func (m EditorModel) updateInlineEdit(msg tea.Msg) (EditorModel, tea.Cmd, Editor
 			}
 			return m, textinput.Blink, EditorActionNone
 
-		case "down", "j":
+		case "down":
 			// Save and move down
-			m.saveCurrentCell(entry)
+			if !m.saveCurrentCell(entry) {
+				return m, nil, EditorActionNone
+			}
 			if m.selectedRow < len(m.entries)-1 {
 				m.finishEdit(entry, false)
 				m.selectedRow++
@@ -374,8 +518,11 @@ func (m EditorModel) updateInlineEdit(msg tea.Msg) (EditorModel, tea.Cmd, Editor
 			return m, textinput.Blink, EditorActionNone
 
 		case "enter":
-			m.saveCurrentCell(entry)
-			// If in description column and this is a new entry, move to IDR
+			m.acceptCategorySuggestion()
+			if !m.saveCurrentCell(entry) {
+				return m, nil, EditorActionNone
+			}
+			// If in description column and this is a new entry, move to category
 			if m.selectedCol == ColDescription && m.isNewEntry {
 				if entry.Description == "" {
 					// Empty description, cancel
@@ -386,7 +533,13 @@ func (m EditorModel) updateInlineEdit(msg tea.Msg) (EditorModel, tea.Cmd, Editor
 					m.isNewEntry = false
 					return m, nil, EditorActionNone
 				}
-				m.selectedCol = ColIDR
+				m.selectedCol = ColCategory
+				m.startInlineEdit()
+				return m, textinput.Blink, EditorActionNone
+			}
+			// New entries continue from category to the amount
+			if m.selectedCol == ColCategory && m.isNewEntry {
+				m.selectedCol = ColLocal
 				m.startInlineEdit()
 				return m, textinput.Blink, EditorActionNone
 			}
@@ -397,16 +550,18 @@ func (m EditorModel) updateInlineEdit(msg tea.Msg) (EditorModel, tea.Cmd, Editor
 			return m.cancelEdit(entry)
 
 		case "left", "h":
-			// In add mode, if in CAD/IDR and haven't typed yet, navigate between columns
-			if m.isNewEntry && !m.hasTypedInCell && (m.selectedCol == ColCAD || m.selectedCol == ColIDR) {
-				m.saveCurrentCell(entry)
-				if m.selectedCol == ColCAD {
-					// Move from CAD to IDR
-					m.selectedCol = ColIDR
+			// In add mode, if in an amount column and haven't typed yet, navigate between columns
+			if m.isNewEntry && !m.hasTypedInCell && (m.selectedCol == ColHome || m.selectedCol == ColLocal) {
+				if !m.saveCurrentCell(entry) {
+					return m, nil, EditorActionNone
+				}
+				if m.selectedCol == ColHome {
+					// Move from home to local
+					m.selectedCol = ColLocal
 					m.startInlineEdit()
 					return m, textinput.Blink, EditorActionNone
-				} else if m.selectedCol == ColIDR {
-					// Move from IDR back to description - save and exit to normal
+				} else if m.selectedCol == ColLocal {
+					// Move from local back to description - save and exit to normal
 					m.mode = EditorModeNormal
 					m.editOriginal = nil
 					m.isNewEntry = false
@@ -416,26 +571,28 @@ func (m EditorModel) updateInlineEdit(msg tea.Msg) (EditorModel, tea.Cmd, Editor
 			// Otherwise, let textinput handle cursor movement
 
 		case "right", "l":
-			// In add mode, if in CAD/IDR and haven't typed yet, navigate between columns
-			if m.isNewEntry && !m.hasTypedInCell && (m.selectedCol == ColCAD || m.selectedCol == ColIDR) {
-				m.saveCurrentCell(entry)
-				if m.selectedCol == ColIDR {
-					// Move from IDR to CAD
-					m.selectedCol = ColCAD
+			// In add mode, if in an amount column and haven't typed yet, navigate between columns
+			if m.isNewEntry && !m.hasTypedInCell && (m.selectedCol == ColHome || m.selectedCol == ColLocal) {
+				if !m.saveCurrentCell(entry) {
+					return m, nil, EditorActionNone
+				}
+				if m.selectedCol == ColLocal {
+					// Move from local to home
+					m.selectedCol = ColHome
 					m.startInlineEdit()
 					return m, textinput.Blink, EditorActionNone
 				}
-				// At CAD and pressing right - just stay here
+				// At home and pressing right - just stay here
 				return m, nil, EditorActionNone
 			}
 			// Otherwise, let textinput handle cursor movement
 
 		default:
-			// Track typing for CAD/IDR navigation behavior
+			// Track typing for amount column navigation behavior
 			key := msg.String()
 			if len(key) == 1 || key == "backspace" || key == "delete" {
 				// For currency fields, on first typing, clear the value to allow replacement
-				if !m.hasTypedInCell && (m.selectedCol == ColCAD || m.selectedCol == ColIDR) && len(key) == 1 {
+				if !m.hasTypedInCell && (m.selectedCol == ColHome || m.selectedCol == ColLocal) && len(key) == 1 {
 					m.editInput.SetValue("")
 				}
 				m.hasTypedInCell = true
@@ -456,30 +613,151 @@ func (m EditorModel) updateInlineEdit(msg tea.Msg) (EditorModel, tea.Cmd, Editor
 	return m, cmd, EditorActionNone
 }
 
-func (m *EditorModel) saveCurrentCell(entry *ledger.Entry) {
+// saveCurrentCell stores the edited cell's value in the entry. Amounts are read
+// as expressions ("45k", "2 x 35k", "20 cad"), with "++" for the service charge
+// and tax and "tip" for a tip ("150k++ tip 20k"); an amount naming the other
+// currency goes in that currency's column. Returns false, with an error notification, if
+// the amount can't be read.
+func (m *EditorModel) saveCurrentCell(entry *ledger.Entry) bool {
 	val := strings.TrimSpace(m.editInput.Value())
-	// Remove commas for parsing
-	val = strings.ReplaceAll(val, ",", "")
 	switch m.selectedCol {
 	case ColDescription:
 		entry.Description = m.editInput.Value()
-	case ColCAD:
+	case ColCategory:
+		entry.SetCategoryInput(m.editInput.Value())
+		m.addCategorySuggestion(entry.Category)
+	case ColHome, ColLocal:
+		currencies := m.day.Currencies
 		if val == "" {
-			entry.CAD = 0
-			entry.IDR = 0
-		} else if cad, err := strconv.ParseFloat(val, 64); err == nil {
-			entry.CAD = cad
-			entry.IDR = m.converter.CADToIDR(cad)
+			entry.Home = 0
+			entry.Local = 0
+			entry.SetConverted("", 0)
+			entry.Charges = nil
+			entry.MoveAmountToTransfer()
+			return true
 		}
-	case ColIDR:
-		if val == "" {
-			entry.IDR = 0
-			entry.CAD = 0
-		} else if idr, err := strconv.ParseFloat(val, 64); err == nil {
-			entry.IDR = idr
-			entry.CAD = m.converter.IDRToCAD(idr)
+		if entry.Charges != nil && m.editInput.Value() == m.initialValue {
+			// Keep the stored percentages even if the profile has changed since
+			return true
+		}
+
+		cur := currencies.Local
+		if m.selectedCol == ColHome {
+			cur = currencies.Home
+		}
+		amount, cur, charges, err := ledger.ParseChargedAmount(val, cur, currencies, m.chargeProfile)
+		if err != nil {
+			m.setNotification("Invalid amount: "+err.Error(), true)
+			return false
+		}
+		if t := entry.Transfer; t != nil && t.Home != 0 && t.Local != 0 && charges == nil {
+			// Both sides of a transfer are known, so one changes without converting the
+			// other: a withdrawal's debit includes the bank's fees and markup
+			if cur.Code == currencies.Home.Code {
+				t.Home = amount.Abs()
+			} else {
+				t.Local = amount.Abs()
+			}
+			return true
+		}
+		paidByOther := entry.PaidByOther()
+		entry.SetAmount(amount, cur, currencies, m.dayRate())
+		entry.Charges = charges
+		if paidByOther {
+			// The amount of a bill someone else paid is the bill, not money out
+			entry.MoveAmountToBill()
+		}
+		// The amount of a transfer is what was moved, not money out
+		entry.MoveAmountToTransfer()
+	}
+	return true
+}
+
+// dayRate returns the cached rate for the day being edited, or 0 if it hasn't been
+// fetched yet. Entries converted without a rate wait for it (see Entry.NeedsRate);
+// the app fetches it in the background and fills them in.
+func (m EditorModel) dayRate() float64 {
+	rate, _ := m.converter.CachedRateOn(m.day.Date)
+	return rate
+}
+
+// amountInput returns the text an amount cell starts editing with. The column the
+// amount was entered in shows its charges ("150,000++ tip 20,000") so they can be
+// changed; the other column shows the plain amount, and typing there drops them.
+// A bill someone else paid shows the bill, and a transfer the amount moved, since
+// the entry's own amount is zero.
+func (m EditorModel) amountInput(entry *ledger.Entry, cur ledger.Currency) string {
+	c := entry.Charges
+	if c == nil || entry.SourceCurrency(m.day.Currencies).Code != cur.Code {
+		if t := entry.Transfer; t != nil {
+			if cur.Code == m.day.Currencies.Home.Code {
+				return formatMoneyWithCommas(t.Home, cur)
+			}
+			return formatMoneyWithCommas(t.Local, cur)
+		}
+		if entry.PaidByOther() {
+			// Edit the bill someone else paid, as spending
+			billHome, billLocal := entry.Bill()
+			if cur.Code == m.day.Currencies.Home.Code {
+				return formatMoneyWithCommas(-billHome, cur)
+			}
+			return formatMoneyWithCommas(-billLocal, cur)
+		}
+		if cur.Code == m.day.Currencies.Home.Code {
+			return formatMoneyWithCommas(entry.Home, cur)
+		}
+		return formatMoneyWithCommas(entry.Local, cur)
+	}
+
+	input := formatMoneyWithCommas(c.Base, cur)
+	if c.HasPercentages() {
+		input += "++"
+	}
+	if c.Tip != 0 {
+		input += " tip " + formatMoneyWithCommas(c.Tip.Abs(), cur)
+	}
+	return input
+}
+
+// toggleCharges adds the service charge and tax profile to an entry, or takes it off
+func (m EditorModel) toggleCharges(entry *ledger.Entry) (EditorModel, tea.Cmd, EditorAction) {
+	if entry.Home == 0 && entry.Local == 0 {
+		m.setNotification("Enter an amount before adding charges", true)
+		return m, nil, EditorActionNone
+	}
+	old := entry.Clone()
+	entry.ToggleCharges(m.chargeProfile, m.day.Currencies, m.dayRate())
+
+	if entry.Charges != nil && entry.Charges.HasPercentages() {
+		m.setNotification(fmt.Sprintf("Added %s to '%s'", entry.Charges.Percentages(), truncateStr(entry.Description, 20)), false)
+	} else {
+		m.setNotification(fmt.Sprintf("Removed charges from '%s'", truncateStr(entry.Description, 20)), false)
+	}
+	m.noteUndoErr(m.undoManager.RecordEditEntry(m.day.Date, old, entry))
+	return m, nil, EditorActionSaved
+}
+
+// acceptCategorySuggestion completes the category input with the highlighted suggestion
+func (m *EditorModel) acceptCategorySuggestion() {
+	if m.selectedCol != ColCategory || !m.hasTypedInCell {
+		return
+	}
+	if suggestion := m.editInput.CurrentSuggestion(); suggestion != "" {
+		m.editInput.SetValue(suggestion)
+	}
+}
+
+// addCategorySuggestion remembers a newly used category for autocomplete
+func (m *EditorModel) addCategorySuggestion(category string) {
+	if category == "" {
+		return
+	}
+	for _, c := range m.categorySuggestions {
+		if c == category {
+			return
 		}
 	}
+	m.categorySuggestions = append(m.categorySuggestions, category)
 }
 
 func (m EditorModel) finishEdit(entry *ledger.Entry, showNotification bool) (EditorModel, tea.Cmd, EditorAction) {
@@ -497,16 +775,16 @@ func (m EditorModel) finishEdit(entry *ledger.Entry, showNotification bool) (Edi
 	if m.editOriginal != nil {
 		if m.editOriginal.Description == "" {
 			// This was a new entry
-			m.undoManager.RecordAddEntry(m.day.Date, entry)
 			if showNotification {
 				m.setNotification(fmt.Sprintf("Added '%s'", truncateStr(entry.Description, 20)), false)
 			}
+			m.noteUndoErr(m.undoManager.RecordAddEntry(m.day.Date, entry))
 		} else {
 			// This was an edit
-			m.undoManager.RecordEditEntry(m.day.Date, m.editOriginal, entry)
 			if showNotification {
 				m.setNotification(fmt.Sprintf("Updated '%s'", truncateStr(entry.Description, 20)), false)
 			}
+			m.noteUndoErr(m.undoManager.RecordEditEntry(m.day.Date, m.editOriginal, entry))
 		}
 	}
 
@@ -525,8 +803,16 @@ func (m EditorModel) cancelEdit(entry *ledger.Entry) (EditorModel, tea.Cmd, Edit
 		} else {
 			// Restore original values
 			entry.Description = m.editOriginal.Description
-			entry.CAD = m.editOriginal.CAD
-			entry.IDR = m.editOriginal.IDR
+			entry.Category = m.editOriginal.Category
+			entry.Tags = m.editOriginal.Tags
+			entry.Home = m.editOriginal.Home
+			entry.Local = m.editOriginal.Local
+			entry.Rate = m.editOriginal.Rate
+			entry.Source = m.editOriginal.Source
+			entry.Charges = m.editOriginal.Charges
+			entry.Payer = m.editOriginal.Payer
+			entry.Split = m.editOriginal.Split
+			entry.Transfer = m.editOriginal.Transfer
 		}
 	}
 	m.mode = EditorModeNormal
@@ -566,9 +852,9 @@ func (m EditorModel) updateScreenTime(msg tea.Msg) (EditorModel, tea.Cmd, Editor
 			oldScreenTime := m.day.ScreenTime
 			newScreenTime := strings.TrimSpace(m.screenTimeInput.Value())
 			m.day.SetScreenTime(newScreenTime)
-			m.undoManager.RecordSetScreenTime(m.day.Date, oldScreenTime, newScreenTime)
 			m.mode = EditorModeNormal
 			m.setNotification("Screen time updated", false)
+			m.noteUndoErr(m.undoManager.RecordSetScreenTime(m.day.Date, oldScreenTime, newScreenTime))
 			return m, nil, EditorActionSaved
 		case "esc":
 			m.mode = EditorModeNormal
@@ -581,21 +867,263 @@ func (m EditorModel) updateScreenTime(msg tea.Msg) (EditorModel, tea.Cmd, Editor
 	return m, cmd, EditorActionNone
 }
 
-func (m EditorModel) performUndo() (EditorModel, tea.Cmd, EditorAction) {
-	msg, err := m.undoManager.Undo()
+func (m EditorModel) updateGoTo(msg tea.Msg) (EditorModel, tea.Cmd, EditorAction) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter":
+			val := m.goToInput.Value()
+			if val == "" {
+				m.mode = EditorModeNormal
+				return m, nil, EditorActionNone
+			}
+			date, err := time.ParseInLocation("01/02/2006", val, m.day.Date.Location())
+			if err != nil {
+				m.setNotification("Invalid date (use MM/DD/YYYY)", true)
+				return m, nil, EditorActionNone
+			}
+			m.goToDate = date
+			m.mode = EditorModeNormal
+			return m, nil, EditorActionGoToDate
+		case "esc":
+			m.mode = EditorModeNormal
+			return m, nil, EditorActionNone
+		}
+	}
+
+	// Auto-insert slashes as the date is typed
+	oldVal := m.goToInput.Value()
+	var cmd tea.Cmd
+	m.goToInput, cmd = m.goToInput.Update(msg)
+	if newVal := m.goToInput.Value(); len(newVal) > len(oldVal) {
+		newVal = autoInsertDateSlashes(newVal)
+		m.goToInput.SetValue(newVal)
+		m.goToInput.SetCursor(len(newVal))
+	}
+	return m, cmd, EditorActionNone
+}
+
+func (m EditorModel) updateSplit(msg tea.Msg) (EditorModel, tea.Cmd, EditorAction) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "enter":
+			if len(m.entries) == 0 || m.selectedRow >= len(m.entries) {
+				m.mode = EditorModeNormal
+				return m, nil, EditorActionNone
+			}
+			entry := m.entries[m.selectedRow]
+			currencies := m.day.Currencies
+			payer, split, err := ledger.ParseSplit(m.splitInput.Value(), entry.SourceCurrency(currencies), currencies)
+			if err != nil {
+				m.setNotification("Invalid split: "+err.Error(), true)
+				return m, nil, EditorActionNone
+			}
+
+			old := entry.Clone()
+			if err := entry.SetSplit(payer, split, currencies); err != nil {
+				m.setNotification("Invalid split: "+err.Error(), true)
+				return m, nil, EditorActionNone
+			}
+			m.mode = EditorModeNormal
+			if m.splitInputValue(old) == m.splitInputValue(entry) {
+				return m, nil, EditorActionNone
+			}
+
+			if split == nil {
+				m.setNotification(fmt.Sprintf("'%s' is no longer shared", truncateStr(entry.Description, 20)), false)
+			} else {
+				m.setNotification(fmt.Sprintf("Shared '%s'", truncateStr(entry.Description, 20)), false)
+			}
+			m.noteUndoErr(m.undoManager.RecordEditEntry(m.day.Date, old, entry))
+			return m, nil, EditorActionSaved
+		case "esc":
+			m.mode = EditorModeNormal
+			return m, nil, EditorActionNone
+		}
+	}
+
+	var cmd tea.Cmd
+	m.splitInput, cmd = m.splitInput.Update(msg)
+	return m, cmd, EditorActionNone
+}
+
+func (m EditorModel) updateAccount(msg tea.Msg) (EditorModel, tea.Cmd, EditorAction) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "enter":
+			if len(m.entries) == 0 || m.selectedRow >= len(m.entries) {
+				m.mode = EditorModeNormal
+				return m, nil, EditorActionNone
+			}
+			entry := m.entries[m.selectedRow]
+			from, to, err := ledger.ParseAccountInput(m.accountInput.Value(), m.accounts)
+			if err != nil {
+				m.setNotification("Invalid account: "+err.Error(), true)
+				return m, nil, EditorActionNone
+			}
+
+			old := entry.Clone()
+			if err := entry.SetAccount(from, to); err != nil {
+				m.setNotification("Invalid account: "+err.Error(), true)
+				return m, nil, EditorActionNone
+			}
+			m.mode = EditorModeNormal
+			if old.FormatAccount() == entry.FormatAccount() {
+				return m, nil, EditorActionNone
+			}
+
+			description := truncateStr(entry.Description, 20)
+			switch {
+			case to != "":
+				m.setNotification(fmt.Sprintf("'%s' moves money from %s to %s", description, from, to), false)
+			case from != "":
+				m.setNotification(fmt.Sprintf("'%s' paid from %s", description, from), false)
+			default:
+				m.setNotification(fmt.Sprintf("'%s' has no account", description), false)
+			}
+			m.noteUndoErr(m.undoManager.RecordEditEntry(m.day.Date, old, entry))
+			return m, nil, EditorActionSaved
+		case "esc":
+			m.mode = EditorModeNormal
+			return m, nil, EditorActionNone
+		}
+	}
+
+	var cmd tea.Cmd
+	m.accountInput, cmd = m.accountInput.Update(msg)
+	return m, cmd, EditorActionNone
+}
+
+func (m EditorModel) updateReconcile(msg tea.Msg) (EditorModel, tea.Cmd, EditorAction) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "enter":
+			if strings.TrimSpace(m.reconcileInput.Value()) == "" {
+				m.mode = EditorModeNormal
+				return m, nil, EditorActionNone
+			}
+			return m.reconcile(m.reconcileInput.Value())
+		case "esc":
+			m.mode = EditorModeNormal
+			return m, nil, EditorActionNone
+		}
+	}
+
+	var cmd tea.Cmd
+	m.reconcileInput, cmd = m.reconcileInput.Update(msg)
+	return m, cmd, EditorActionNone
+}
+
+// reconcile reads what is actually in an account ("cash 850k", or just "850k" for
+// the default account) and adds an adjustment for the difference from its balance
+func (m EditorModel) reconcile(input string) (EditorModel, tea.Cmd, EditorAction) {
+	account := m.accounts[0]
+	amountPart := input
+	if fields := strings.Fields(input); len(fields) > 1 {
+		if a, ok := ledger.FindAccount(m.accounts, fields[0]); ok {
+			account = a
+			amountPart = strings.Join(fields[1:], " ")
+		}
+	}
+
+	actual, cur, err := ledger.ParseAmount(amountPart, account.Currency, m.day.Currencies)
 	if err != nil {
-		m.setNotification("Undo failed: "+err.Error(), true)
+		m.setNotification("Invalid amount: "+err.Error(), true)
 		return m, nil, EditorActionNone
 	}
+	if cur.Code != account.Currency.Code {
+		m.setNotification("Invalid amount: "+account.Name+" is kept in "+account.Currency.Code, true)
+		return m, nil, EditorActionNone
+	}
+
+	balance := ledger.AccountBalance{Account: account, Balance: account.Opening}
+	for _, b := range m.accountBalances {
+		if b.Account.Name == account.Name {
+			balance = b
+		}
+	}
+	m.mode = EditorModeNormal
+	if actual == balance.Balance {
+		m.setNotification(account.Name+" already matches at "+formatCurrency(actual, cur), false)
+		return m, nil, EditorActionNone
+	}
+
+	entry := ledger.NewAdjustment(m.day.Date, balance, actual, m.day.Currencies, m.dayRate())
+	entry.ScreenTime = m.day.ScreenTime
+	m.day.AddEntry(entry)
+	m.updateFilteredEntries()
+	m.selectedRow = len(m.entries) - 1
+
+	difference := actual - balance.Balance
+	if difference < 0 {
+		m.setNotification(fmt.Sprintf("Recorded %s missing from %s", formatCurrency(-difference, cur), account.Name), false)
+	} else {
+		m.setNotification(fmt.Sprintf("Recorded %s extra in %s", formatCurrency(difference, cur), account.Name), false)
+	}
+	m.noteUndoErr(m.undoManager.RecordAddEntry(m.day.Date, entry))
+	return m, nil, EditorActionSaved
+}
+
+// splitInputValue returns the selected entry's payer and split as typed in the
+// split prompt ("alice: me alice"), or "" if it isn't shared
+func (m EditorModel) splitInputValue(entry *ledger.Entry) string {
+	if entry.Split == nil {
+		return ""
+	}
+	people := entry.Split.Format(entry.SourceCurrency(m.day.Currencies))
+	if ledger.IsMe(entry.Payer) {
+		return people
+	}
+	return strings.TrimSpace(entry.Payer + ": " + people)
+}
+
+func (m EditorModel) performUndo() (EditorModel, tea.Cmd, EditorAction) {
+	return m.applyHistory("Undo", m.undoManager.Undo)
+}
+
+func (m EditorModel) performRedo() (EditorModel, tea.Cmd, EditorAction) {
+	return m.applyHistory("Redo", m.undoManager.Redo)
+}
+
+// applyHistory runs an undo or redo and reloads the day if it changed anything
+func (m EditorModel) applyHistory(name string, apply func() (string, error)) (EditorModel, tea.Cmd, EditorAction) {
+	msg, err := apply()
 	if msg == "" {
-		m.setNotification("Nothing to undo", false)
+		if err != nil {
+			m.setNotification(name+" failed: "+err.Error(), true)
+		} else {
+			m.setNotification("Nothing to "+strings.ToLower(name), false)
+		}
 		return m, nil, EditorActionNone
 	}
 
 	m.setNotification(msg, false)
+	m.noteUndoErr(err)
 	return m, nil, EditorActionReload
 }
 
+// noteUndoErr reports a failure to save the undo history in place of the current notification
+func (m *EditorModel) noteUndoErr(err error) {
+	if err != nil {
+		m.setNotification("Undo history not saved: "+err.Error(), true)
+	}
+}
+
+// refreshRate retries the exchange-rate refresh in the background
+func (m EditorModel) refreshRate() (EditorModel, tea.Cmd, EditorAction) {
+	if !m.converter.CanRefresh() {
+		m.setNotification("Rate refresh is disabled (offline mode or fixed rate)", false)
+		return m, nil, EditorActionNone
+	}
+	if m.refreshing {
+		return m, nil, EditorActionNone
+	}
+
+	m.SetRefreshing(true)
+	m.setNotification("Refreshing rate...", false)
+	return m, refreshRateCmd(m.converter, true), EditorActionNone
+}
+
 func (m *EditorModel) updateFilteredEntries() {
 	query := m.search.GetQuery()
 	m.entries = m.day.Filter(query)
@@ -695,6 +1223,14 @@ func (m EditorModel) renderTopRibbon(date string) string {
 		modeText = "SCREEN TIME"
 	case EditorModeJournal:
 		modeText = "JOURNAL"
+	case EditorModeGoTo:
+		modeText = "GO TO"
+	case EditorModeSplit:
+		modeText = "SPLIT"
+	case EditorModeAccount:
+		modeText = "ACCOUNT"
+	case EditorModeReconcile:
+		modeText = "RECONCILE"
 	default:
 		if m.pendingDelete {
 			modeText = "d..."
@@ -705,32 +1241,32 @@ func (m EditorModel) renderTopRibbon(date string) string {
 
 	modeIndicator := "-- " + modeText + " --"
 
-	// Build ribbon content: mode on left, date on right
+	// Build ribbon content: mode and budgets on left, date on right
 	leftPart := modeIndicator
 	rightPart := date
 
+	// Vim-style ribbon styling (light bg, dark fg)
+	ribbonStyle := lipgloss.NewStyle().
+		Background(ColorLightGray).
+		Foreground(ColorBlack)
+
 	// Calculate spacing
 	leftWidth := lipgloss.Width(leftPart)
 	rightWidth := lipgloss.Width(rightPart)
 	totalContentWidth := leftWidth + rightWidth
 
-	var ribbonContent string
+	var ribbon string
 	if totalContentWidth+4 <= m.width {
-		// Enough space for both with spacing
-		spacing := m.width - totalContentWidth - 4 // -4 for padding (2 on each side)
-		ribbonContent = "  " + leftPart + strings.Repeat(" ", spacing) + rightPart + "  "
+		// Enough space for both with spacing; budgets fill what's left
+		budgets := m.renderBudgetSegments(ribbonStyle, m.width-totalContentWidth-4)
+		spacing := m.width - totalContentWidth - 4 - lipgloss.Width(budgets) // -4 for padding (2 on each side)
+		ribbon = ribbonStyle.Render("  "+leftPart) + budgets +
+			ribbonStyle.Render(strings.Repeat(" ", spacing)+rightPart+"  ")
 	} else {
 		// Not enough space, just show mode centered
-		ribbonContent = lipgloss.NewStyle().Width(m.width).Align(lipgloss.Center).Render(leftPart)
+		ribbon = ribbonStyle.Width(m.width).Align(lipgloss.Center).Render(leftPart)
 	}
 
-	// Apply vim-style ribbon styling (light bg, dark fg)
-	ribbon := lipgloss.NewStyle().
-		Background(ColorLightGray).
-		Foreground(ColorBlack).
-		Width(m.width).
-		Render(ribbonContent)
-
 	// Add notification if present
 	if m.notification != "" {
 		notifStyle := m.styles.Notification
@@ -743,6 +1279,36 @@ func (m EditorModel) renderTopRibbon(date string) string {
 	return ribbon
 }
 
+// renderBudgetSegments renders what's left of the day, week and month budgets
+// (those not tied to a category) for the top ribbon, in both currencies. Budgets
+// that are over use the error style. Segments that don't fit in width are left out.
+func (m EditorModel) renderBudgetSegments(ribbonStyle lipgloss.Style, width int) string {
+	var sb strings.Builder
+	used := 0
+	for _, status := range m.budgets {
+		if status.Budget.Category != "" {
+			continue
+		}
+
+		state, style := "left", ribbonStyle
+		if status.Over() {
+			state, style = "over", m.styles.StatusBarError
+		}
+		text := fmt.Sprintf("%s: %s / %s %s", status.Budget.Period.Label(),
+			formatCurrency(status.RemainingHome().Abs(), m.day.Currencies.Home),
+			formatCurrency(status.RemainingLocal().Abs(), m.day.Currencies.Local), state)
+		segment := ribbonStyle.Render("   ") + style.Render(text)
+
+		// Keep a gap before the date
+		if used+lipgloss.Width(segment)+2 > width {
+			break
+		}
+		sb.WriteString(segment)
+		used += lipgloss.Width(segment)
+	}
+	return sb.String()
+}
+
 // buildLedgerPanel builds a complete bordered panel for the ledger
 func (m EditorModel) buildLedgerPanel(width, height int) string {
 	contentWidth := width - 4 // Account for border and padding
@@ -755,6 +1321,15 @@ func (m EditorModel) buildLedgerPanel(width, height int) string {
 	if m.mode == EditorModeScreenTime {
 		// Editing mode - show input inline
 		screenTimeLine = m.styles.InputLabel.Render("Screen Time: ") + m.screenTimeInput.View()
+	} else if m.mode == EditorModeGoTo {
+		// Go-to prompt takes the screen time's place until a date is entered
+		screenTimeLine = m.styles.InputLabel.Render("Go to date: ") + m.goToInput.View()
+	} else if m.mode == EditorModeSplit {
+		screenTimeLine = m.styles.InputLabel.Render("Paid by / split: ") + m.splitInput.View()
+	} else if m.mode == EditorModeAccount {
+		screenTimeLine = m.styles.InputLabel.Render("Account: ") + m.accountInput.View()
+	} else if m.mode == EditorModeReconcile {
+		screenTimeLine = m.styles.InputLabel.Render("Actually have: ") + m.reconcileInput.View()
 	} else {
 		// Display mode
 		screenTime := "not set"
@@ -778,13 +1353,30 @@ func (m EditorModel) buildLedgerPanel(width, height int) string {
 		lines = append(lines, "")
 	}
 
+	// Service charge, tax and tip breakdown, the selected entry's split and account,
+	// and the account balances below the table
+	var details []string
+	if charges := m.renderChargeTotals(contentWidth); charges != "" {
+		details = append(details, charges)
+	}
+	if split := m.renderSplitDetails(contentWidth); split != "" {
+		details = append(details, split)
+	}
+	if account := m.renderAccountDetails(contentWidth); account != "" {
+		details = append(details, account)
+	}
+	if balances := m.renderAccountBalances(contentWidth); balances != "" {
+		details = append(details, balances)
+	}
+
 	// Calculate table height
-	usedLines := len(lines)
+	usedLines := len(lines) + len(details)
 	tableHeight := innerHeight - usedLines
 
 	// Use the standard table rendering with borders
 	tableLines := m.renderTableLines(contentWidth, tableHeight)
 	lines = append(lines, tableLines...)
+	lines = append(lines, details...)
 
 	// Build the bordered panel
 	return m.tableRenderer.BuildBorderedBox("Ledger", lines, width, height)
@@ -884,31 +1476,33 @@ func (m EditorModel) renderEntryRow(idx int, descWidth int) string {
 	}
 	sb.WriteString(" ")
 
-	// CAD
-	cadStr := formatCurrency(entry.CAD, "CAD")
-	if isEditing && m.selectedCol == ColCAD {
-		m.editInput.Width = 11
-		sb.WriteString("$" + m.editInput.View())
-		sb.WriteString(strings.Repeat(" ", 12-1-lipgloss.Width(m.editInput.View())))
+	// Home
+	homeStr := formatEntryAmount(entry, entry.Home, m.day.Currencies.Home)
+	if isEditing && m.selectedCol == ColHome {
+		symbol := m.day.Currencies.Home.Symbol
+		m.editInput.Width = 12 - lipgloss.Width(symbol)
+		sb.WriteString(symbol + m.editInput.View())
+		sb.WriteString(strings.Repeat(" ", 12-lipgloss.Width(symbol)-lipgloss.Width(m.editInput.View())))
 	} else {
-		if isSelected && m.selectedCol == ColCAD {
-			sb.WriteString(m.styles.TableRowSelected.Width(12).Render(cadStr))
+		if isSelected && m.selectedCol == ColHome {
+			sb.WriteString(m.styles.TableRowSelected.Width(12).Render(homeStr))
 		} else {
-			sb.WriteString(fmt.Sprintf("%12s", cadStr))
+			sb.WriteString(fmt.Sprintf("%12s", homeStr))
 		}
 	}
 	sb.WriteString(" ")
 
-	// IDR
-	idrStr := formatCurrency(entry.IDR, "IDR")
-	if isEditing && m.selectedCol == ColIDR {
-		m.editInput.Width = 9
-		sb.WriteString("Rp " + m.editInput.View())
+	// Local
+	localStr := formatEntryAmount(entry, entry.Local, m.day.Currencies.Local)
+	if isEditing && m.selectedCol == ColLocal {
+		symbol := m.day.Currencies.Local.Symbol
+		m.editInput.Width = 12 - lipgloss.Width(symbol)
+		sb.WriteString(symbol + m.editInput.View())
 	} else {
-		if isSelected && m.selectedCol == ColIDR {
-			sb.WriteString(m.styles.TableRowSelected.Width(12).Render(idrStr))
+		if isSelected && m.selectedCol == ColLocal {
+			sb.WriteString(m.styles.TableRowSelected.Width(12).Render(localStr))
 		} else {
-			sb.WriteString(fmt.Sprintf("%12s", idrStr))
+			sb.WriteString(fmt.Sprintf("%12s", localStr))
 		}
 	}
 
@@ -964,15 +1558,92 @@ func (m EditorModel) renderSplitLeftContent(contentWidth, contentHeight int) str
 	return sb.String()
 }
 
+// renderSplitDetails describes who paid for the selected entry, how it's shared
+// and what that means for you, or returns "" if it isn't shared
+func (m EditorModel) renderSplitDetails(width int) string {
+	if m.mode == EditorModeInlineEdit || len(m.entries) == 0 || m.selectedRow >= len(m.entries) {
+		return ""
+	}
+	entry := m.entries[m.selectedRow]
+	if !entry.IsShared() {
+		return ""
+	}
+
+	payer := "you"
+	if !ledger.IsMe(entry.Payer) {
+		payer = entry.Payer
+	}
+	text := "Paid by " + payer + ", " + entry.Split.Describe()
+	for _, b := range ledger.Balances([]*ledger.Entry{entry}) {
+		if ledger.IsMe(b.Person) && !b.IsSettled() {
+			amounts := formatCurrency(b.Local.Abs(), m.day.Currencies.Local) + " (" + formatCurrency(b.Home.Abs(), m.day.Currencies.Home) + ")"
+			if b.Owes() {
+				text += ": you owe " + amounts
+			} else {
+				text += ": you're owed " + amounts
+			}
+		}
+	}
+	return m.styles.Subtitle.Render(truncateStr(text, width))
+}
+
+// renderAccountDetails describes the account the selected entry was paid from or
+// the transfer it records, or returns "" if it has no account
+func (m EditorModel) renderAccountDetails(width int) string {
+	if m.mode == EditorModeInlineEdit || len(m.entries) == 0 || m.selectedRow >= len(m.entries) {
+		return ""
+	}
+	entry := m.entries[m.selectedRow]
+	if entry.Account == "" {
+		return ""
+	}
+
+	text := "Paid from " + entry.Account
+	if t := entry.Transfer; t != nil {
+		currencies := m.day.Currencies
+		text = fmt.Sprintf("Transfer from %s to %s: %s (%s)", entry.Account, t.To,
+			formatCurrency(t.Local, currencies.Local), formatCurrency(t.Home, currencies.Home))
+		if cost, ok := entry.TransferFXCost(currencies); ok && cost != 0 {
+			effective := t.Local.Float(currencies.Local) / t.Home.Float(currencies.Home)
+			text += fmt.Sprintf(", effective rate %s vs %s: %s in fees and markup",
+				currency.FormatRateValue(effective), currency.FormatRateValue(entry.Rate), formatCurrency(cost, currencies.Home))
+		}
+	}
+	return m.styles.Subtitle.Render(truncateStr(text, width))
+}
+
+// renderAccountBalances lists each account's balance at the end of the day, or
+// returns "" if there are no accounts
+func (m EditorModel) renderAccountBalances(width int) string {
+	if len(m.accountBalances) == 0 {
+		return ""
+	}
+	parts := make([]string, len(m.accountBalances))
+	for i, b := range m.accountBalances {
+		parts[i] = b.Account.Name + " " + formatCurrency(b.Balance, b.Account.Currency)
+	}
+	return m.styles.Subtitle.Render(truncateStr("Balances: "+strings.Join(parts, "  "), width))
+}
+
+// renderChargeTotals describes the service charge, tax and tip in the listed
+// entries, or returns "" if none have charges
+func (m EditorModel) renderChargeTotals(width int) string {
+	totals := ledger.SumCharges(m.entries, m.day.Currencies)
+	if totals.Entries == 0 {
+		return ""
+	}
+	return m.styles.Subtitle.Render(truncateStr(formatChargeTotals(totals, m.day.Currencies.Local), width))
+}
+
 // renderCompactTable renders a simple table for split view
 func (m EditorModel) renderCompactTable(width, maxRows int) string {
 	// Calculate column widths for compact view
-	descWidth := width - 30 // Leave room for CAD and IDR
+	descWidth := width - 30 // Leave room for the home and local amounts
 	if descWidth < 8 {
 		descWidth = 8
 	}
-	cadWidth := 12
-	idrWidth := 14
+	homeWidth := 12
+	localWidth := 14
 
 	var sb strings.Builder
 
@@ -980,10 +1651,10 @@ func (m EditorModel) renderCompactTable(width, maxRows int) string {
 	sb.WriteString(m.styles.TableHeader.Render("Description"))
 	sb.WriteString(strings.Repeat(" ", descWidth-11))
 	sb.WriteString(" ")
-	sb.WriteString(m.styles.TableHeader.Render("CAD"))
-	sb.WriteString(strings.Repeat(" ", cadWidth-3))
+	sb.WriteString(m.styles.TableHeader.Render(m.day.Currencies.Home.Code))
+	sb.WriteString(strings.Repeat(" ", homeWidth-3))
 	sb.WriteString(" ")
-	sb.WriteString(m.styles.TableHeader.Render("IDR"))
+	sb.WriteString(m.styles.TableHeader.Render(m.day.Currencies.Local.Code))
 	sb.WriteString("\n")
 	sb.WriteString(strings.Repeat("─", width))
 	sb.WriteString("\n")
@@ -1045,32 +1716,34 @@ func (m EditorModel) renderCompactTable(width, maxRows int) string {
 			sb.WriteString(strings.Repeat(" ", descWidth-2-len(truncateStr(entry.Description, descWidth-2))))
 			sb.WriteString(" ")
 
-			// CAD
-			if isEditing && m.selectedCol == ColCAD {
-				m.editInput.Width = cadWidth - 1
-				sb.WriteString("$")
+			// Home
+			if isEditing && m.selectedCol == ColHome {
+				symbol := m.day.Currencies.Home.Symbol
+				m.editInput.Width = homeWidth - lipgloss.Width(symbol)
+				sb.WriteString(symbol)
 				sb.WriteString(m.editInput.View())
 			} else {
-				cadStr := formatCurrency(entry.CAD, "CAD")
-				if isSelected && m.selectedCol == ColCAD {
-					sb.WriteString(m.styles.TableRowSelected.Width(cadWidth).Render(cadStr))
+				homeStr := formatEntryAmount(entry, entry.Home, m.day.Currencies.Home)
+				if isSelected && m.selectedCol == ColHome {
+					sb.WriteString(m.styles.TableRowSelected.Width(homeWidth).Render(homeStr))
 				} else {
-					sb.WriteString(m.styles.TableRow.Width(cadWidth).Render(cadStr))
+					sb.WriteString(m.styles.TableRow.Width(homeWidth).Render(homeStr))
 				}
 			}
 			sb.WriteString(" ")
 
-			// IDR
-			if isEditing && m.selectedCol == ColIDR {
-				m.editInput.Width = idrWidth - 3
-				sb.WriteString("Rp ")
+			// Local
+			if isEditing && m.selectedCol == ColLocal {
+				symbol := m.day.Currencies.Local.Symbol
+				m.editInput.Width = localWidth - lipgloss.Width(symbol)
+				sb.WriteString(symbol)
 				sb.WriteString(m.editInput.View())
 			} else {
-				idrStr := formatCurrency(entry.IDR, "IDR")
-				if isSelected && m.selectedCol == ColIDR {
-					sb.WriteString(m.styles.TableRowSelected.Width(idrWidth).Render(idrStr))
+				localStr := formatEntryAmount(entry, entry.Local, m.day.Currencies.Local)
+				if isSelected && m.selectedCol == ColLocal {
+					sb.WriteString(m.styles.TableRowSelected.Width(localWidth).Render(localStr))
 				} else {
-					sb.WriteString(m.styles.TableRow.Width(idrWidth).Render(idrStr))
+					sb.WriteString(m.styles.TableRow.Width(localWidth).Render(localStr))
 				}
 			}
 			sb.WriteString("\n")
@@ -1080,14 +1753,14 @@ func (m EditorModel) renderCompactTable(width, maxRows int) string {
 	// Totals
 	sb.WriteString(strings.Repeat("─", width))
 	sb.WriteString("\n")
-	totalCAD := m.day.TotalCAD()
-	totalIDR := m.day.TotalIDR()
+	totalHome := m.day.TotalHome()
+	totalLocal := m.day.TotalLocal()
 	sb.WriteString(m.styles.TotalsLabel.Render("Total"))
 	sb.WriteString(strings.Repeat(" ", descWidth-3))
 	sb.WriteString(" ")
-	sb.WriteString(m.styles.TotalsValue.Width(cadWidth).Render(formatCurrency(totalCAD, "CAD")))
+	sb.WriteString(m.styles.TotalsValue.Width(homeWidth).Render(formatCurrency(totalHome, m.day.Currencies.Home)))
 	sb.WriteString(" ")
-	sb.WriteString(m.styles.TotalsValue.Width(idrWidth).Render(formatCurrency(totalIDR, "IDR")))
+	sb.WriteString(m.styles.TotalsValue.Width(localWidth).Render(formatCurrency(totalLocal, m.day.Currencies.Local)))
 
 	return sb.String()
 }
@@ -1313,6 +1986,14 @@ func (m EditorModel) renderModeIndicator() string {
 		modeText = "SCREEN TIME"
 	case EditorModeJournal:
 		modeText = "JOURNAL"
+	case EditorModeGoTo:
+		modeText = "GO TO"
+	case EditorModeSplit:
+		modeText = "SPLIT"
+	case EditorModeAccount:
+		modeText = "ACCOUNT"
+	case EditorModeReconcile:
+		modeText = "RECONCILE"
 	default:
 		if m.pendingDelete {
 			modeText = "d..."
@@ -1346,7 +2027,7 @@ func (m EditorModel) renderTableLines(contentWidth, maxRows int) []string {
 }
 
 // renderTableRowCompact renders a compact table row for split view
-func (m EditorModel) renderTableRowCompact(idx int, entry *ledger.Entry, descWidth, idrWidth, cadWidth int) string {
+func (m EditorModel) renderTableRowCompact(idx int, entry *ledger.Entry, descWidth, catWidth, localWidth, homeWidth int) string {
 	border := m.styles.TableBorder
 
 	isSelected := idx == m.selectedRow
@@ -1377,44 +2058,65 @@ func (m EditorModel) renderTableRowCompact(idx int, entry *ledger.Entry, descWid
 	}
 	sb.WriteString(border.Render("│"))
 
-	// IDR column (now first)
-	if isEditing && m.selectedCol == ColIDR {
-		m.editInput.Width = idrWidth - 3
+	// Category column
+	if isEditing && m.selectedCol == ColCategory {
+		m.editInput.Width = catWidth
 		inputView := m.editInput.View()
-		sb.WriteString(" Rp " + lipgloss.NewStyle().Width(idrWidth-3).Render(inputView) + " ")
+		sb.WriteString(" " + lipgloss.NewStyle().Width(catWidth).MaxWidth(catWidth).Render(inputView) + " ")
 	} else {
-		idrDisplay := formatCurrency(entry.IDR, "IDR")
-		idrStyle := m.styles.ValueNeutral
-		if entry.IDR > 0 {
-			idrStyle = m.styles.ValuePositive
-		} else if entry.IDR < 0 {
-			idrStyle = m.styles.ValueNegative
-		}
-		if isSelected && m.selectedCol == ColIDR {
-			sb.WriteString(" " + m.styles.TableRowSelected.Width(idrWidth).Render(idrDisplay) + " ")
+		catDisplay := truncateStr(entry.CategoryLabel(), catWidth)
+		if isSelected && m.selectedCol == ColCategory {
+			sb.WriteString(" " + m.styles.TableRowSelected.Width(catWidth).Render(catDisplay) + " ")
 		} else {
-			sb.WriteString(" " + idrStyle.Width(idrWidth).Render(idrDisplay) + " ")
+			sb.WriteString(" " + m.styles.TableRow.Width(catWidth).Render(catDisplay) + " ")
 		}
 	}
 	sb.WriteString(border.Render("│"))
 
-	// CAD column (now second)
-	if isEditing && m.selectedCol == ColCAD {
-		m.editInput.Width = cadWidth - 1
-		inputView := m.editInput.View()
-		sb.WriteString(" $" + lipgloss.NewStyle().Width(cadWidth-1).Render(inputView) + " ")
+	// Local column (now first)
+	if isEditing && m.selectedCol == ColLocal {
+		symbol := m.day.Currencies.Local.Symbol
+		inputWidth := localWidth - lipgloss.Width(symbol)
+		m.editInput.Width = inputWidth
+		// Clip rather than wrap an expression that scrolls past the cell
+		inputView := lipgloss.NewStyle().MaxWidth(inputWidth).Render(m.editInput.View())
+		sb.WriteString(" " + symbol + lipgloss.NewStyle().Width(inputWidth).Render(inputView) + " ")
+	} else {
+		localDisplay := formatEntryAmount(entry, entry.Local, m.day.Currencies.Local)
+		localStyle := m.styles.ValueNeutral
+		if entry.Local > 0 {
+			localStyle = m.styles.ValuePositive
+		} else if entry.Local < 0 {
+			localStyle = m.styles.ValueNegative
+		}
+		if isSelected && m.selectedCol == ColLocal {
+			sb.WriteString(" " + m.styles.TableRowSelected.Width(localWidth).Render(localDisplay) + " ")
+		} else {
+			sb.WriteString(" " + localStyle.Width(localWidth).Render(localDisplay) + " ")
+		}
+	}
+	sb.WriteString(border.Render("│"))
+
+	// Home column (now second)
+	if isEditing && m.selectedCol == ColHome {
+		symbol := m.day.Currencies.Home.Symbol
+		inputWidth := homeWidth - lipgloss.Width(symbol)
+		m.editInput.Width = inputWidth
+		// Clip rather than wrap an expression that scrolls past the cell
+		inputView := lipgloss.NewStyle().MaxWidth(inputWidth).Render(m.editInput.View())
+		sb.WriteString(" " + symbol + lipgloss.NewStyle().Width(inputWidth).Render(inputView) + " ")
 	} else {
-		cadDisplay := formatCurrency(entry.CAD, "CAD")
-		cadStyle := m.styles.ValueNeutral
-		if entry.CAD > 0 {
-			cadStyle = m.styles.ValuePositive
-		} else if entry.CAD < 0 {
-			cadStyle = m.styles.ValueNegative
-		}
-		if isSelected && m.selectedCol == ColCAD {
-			sb.WriteString(" " + m.styles.TableRowSelected.Width(cadWidth).Render(cadDisplay) + " ")
+		homeDisplay := formatEntryAmount(entry, entry.Home, m.day.Currencies.Home)
+		homeStyle := m.styles.ValueNeutral
+		if entry.Home > 0 {
+			homeStyle = m.styles.ValuePositive
+		} else if entry.Home < 0 {
+			homeStyle = m.styles.ValueNegative
+		}
+		if isSelected && m.selectedCol == ColHome {
+			sb.WriteString(" " + m.styles.TableRowSelected.Width(homeWidth).Render(homeDisplay) + " ")
 		} else {
-			sb.WriteString(" " + cadStyle.Width(cadWidth).Render(cadDisplay) + " ")
+			sb.WriteString(" " + homeStyle.Width(homeWidth).Render(homeDisplay) + " ")
 		}
 	}
 	sb.WriteString(border.Render("│"))
@@ -1423,13 +2125,13 @@ func (m EditorModel) renderTableRowCompact(idx int, entry *ledger.Entry, descWid
 }
 
 func (m EditorModel) renderTableWithWidth(panelWidth, maxRows int) string {
-	// Fixed widths for CAD and IDR columns
-	cadWidth := 14
-	idrWidth := 16
+	// Fixed widths for home and local columns
+	homeWidth := 14
+	localWidth := 16
 
 	// Calculate description width based on available panel width
 	// Total table width: desc + cad(14) + idr(16) + borders(6) + padding(6)
-	descWidth := panelWidth - cadWidth - idrWidth - 12
+	descWidth := panelWidth - homeWidth - localWidth - 12
 	if descWidth < 15 {
 		descWidth = 15
 	}
@@ -1438,21 +2140,21 @@ func (m EditorModel) renderTableWithWidth(panelWidth, maxRows int) string {
 	border := m.styles.TableBorder
 
 	// Top border
-	sb.WriteString(border.Render("┌" + strings.Repeat("─", descWidth+2) + "┬" + strings.Repeat("─", idrWidth+2) + "┬" + strings.Repeat("─", cadWidth+2) + "┐"))
+	sb.WriteString(border.Render("┌" + strings.Repeat("─", descWidth+2) + "┬" + strings.Repeat("─", localWidth+2) + "┬" + strings.Repeat("─", homeWidth+2) + "┐"))
 	sb.WriteString("\n")
 
 	// Header
 	sb.WriteString(border.Render("│"))
 	sb.WriteString(" " + m.styles.TableHeader.Width(descWidth).Render("Description") + " ")
 	sb.WriteString(border.Render("│"))
-	sb.WriteString(" " + m.styles.TableHeader.Width(idrWidth).Render("IDR") + " ")
+	sb.WriteString(" " + m.styles.TableHeader.Width(localWidth).Render(m.day.Currencies.Local.Code) + " ")
 	sb.WriteString(border.Render("│"))
-	sb.WriteString(" " + m.styles.TableHeader.Width(cadWidth).Render("CAD") + " ")
+	sb.WriteString(" " + m.styles.TableHeader.Width(homeWidth).Render(m.day.Currencies.Home.Code) + " ")
 	sb.WriteString(border.Render("│"))
 	sb.WriteString("\n")
 
 	// Header separator
-	sb.WriteString(border.Render("├" + strings.Repeat("─", descWidth+2) + "┼" + strings.Repeat("─", idrWidth+2) + "┼" + strings.Repeat("─", cadWidth+2) + "┤"))
+	sb.WriteString(border.Render("├" + strings.Repeat("─", descWidth+2) + "┼" + strings.Repeat("─", localWidth+2) + "┼" + strings.Repeat("─", homeWidth+2) + "┤"))
 	sb.WriteString("\n")
 
 	// Calculate visible rows (subtract header and footer from maxRows)
@@ -1470,9 +2172,9 @@ func (m EditorModel) renderTableWithWidth(panelWidth, maxRows int) string {
 		}
 		sb.WriteString(" " + m.styles.Subtitle.Width(descWidth).Render(truncateStr(emptyMsg, descWidth)) + " ")
 		sb.WriteString(border.Render("│"))
-		sb.WriteString(" " + lipgloss.NewStyle().Width(idrWidth).Render("") + " ")
+		sb.WriteString(" " + lipgloss.NewStyle().Width(localWidth).Render("") + " ")
 		sb.WriteString(border.Render("│"))
-		sb.WriteString(" " + lipgloss.NewStyle().Width(cadWidth).Render("") + " ")
+		sb.WriteString(" " + lipgloss.NewStyle().Width(homeWidth).Render("") + " ")
 		sb.WriteString(border.Render("│"))
 		sb.WriteString("\n")
 	} else {
@@ -1499,21 +2201,21 @@ func (m EditorModel) renderTableWithWidth(panelWidth, maxRows int) string {
 
 		for i := startIdx; i < endIdx; i++ {
 			entry := m.entries[i]
-			sb.WriteString(m.renderTableRowWithWidth(i, entry, descWidth, idrWidth, cadWidth))
+			sb.WriteString(m.renderTableRowWithWidth(i, entry, descWidth, localWidth, homeWidth))
 			sb.WriteString("\n")
 		}
 	}
 
 	// Separator before totals
-	sb.WriteString(border.Render("├" + strings.Repeat("─", descWidth+2) + "┼" + strings.Repeat("─", idrWidth+2) + "┼" + strings.Repeat("─", cadWidth+2) + "┤"))
+	sb.WriteString(border.Render("├" + strings.Repeat("─", descWidth+2) + "┼" + strings.Repeat("─", localWidth+2) + "┼" + strings.Repeat("─", homeWidth+2) + "┤"))
 	sb.WriteString("\n")
 
 	// Totals row
-	sb.WriteString(m.tableRenderer.RenderTotalsRowWithWidth(m.day, m.search.GetQuery(), descWidth, idrWidth, cadWidth))
+	sb.WriteString(m.tableRenderer.RenderTotalsRowWithWidth(m.day, m.search.GetQuery(), descWidth, localWidth, homeWidth))
 	sb.WriteString("\n")
 
 	// Bottom border
-	sb.WriteString(border.Render("└" + strings.Repeat("─", descWidth+2) + "┴" + strings.Repeat("─", idrWidth+2) + "┴" + strings.Repeat("─", cadWidth+2) + "┘"))
+	sb.WriteString(border.Render("└" + strings.Repeat("─", descWidth+2) + "┴" + strings.Repeat("─", localWidth+2) + "┴" + strings.Repeat("─", homeWidth+2) + "┘"))
 
 	return sb.String()
 }
@@ -1522,7 +2224,7 @@ func (m EditorModel) renderTableRow(idx int, entry *ledger.Entry, descWidth int)
 	return m.renderTableRowWithWidth(idx, entry, descWidth, 16, 14)
 }
 
-func (m EditorModel) renderTableRowWithWidth(idx int, entry *ledger.Entry, descWidth, idrWidth, cadWidth int) string {
+func (m EditorModel) renderTableRowWithWidth(idx int, entry *ledger.Entry, descWidth, localWidth, homeWidth int) string {
 	var sb strings.Builder
 	border := m.styles.TableBorder
 
@@ -1552,46 +2254,50 @@ func (m EditorModel) renderTableRowWithWidth(idx int, entry *ledger.Entry, descW
 	}
 	sb.WriteString(border.Render("│"))
 
-	// IDR column (now first)
-	if isEditing && m.selectedCol == ColIDR {
-		m.editInput.Width = idrWidth - 3 // Account for "Rp "
+	// Local column (now first)
+	if isEditing && m.selectedCol == ColLocal {
+		symbol := m.day.Currencies.Local.Symbol
+		inputWidth := localWidth - lipgloss.Width(symbol) // Account for the symbol
+		m.editInput.Width = inputWidth
 		inputView := m.editInput.View()
-		// Static Rp prefix + editable number
-		sb.WriteString(" Rp " + lipgloss.NewStyle().Width(idrWidth-3).Render(inputView) + " ")
+		// Static symbol prefix + editable number
+		sb.WriteString(" " + symbol + lipgloss.NewStyle().Width(inputWidth).Render(inputView) + " ")
 	} else {
-		idrDisplay := formatCurrency(entry.IDR, "IDR")
-		idrStyle := m.styles.ValueNeutral
-		if entry.IDR > 0 {
-			idrStyle = m.styles.ValuePositive
-		} else if entry.IDR < 0 {
-			idrStyle = m.styles.ValueNegative
-		}
-		if isSelected && m.selectedCol == ColIDR {
-			sb.WriteString(" " + m.styles.TableRowSelected.Width(idrWidth).Render(idrDisplay) + " ")
+		localDisplay := formatEntryAmount(entry, entry.Local, m.day.Currencies.Local)
+		localStyle := m.styles.ValueNeutral
+		if entry.Local > 0 {
+			localStyle = m.styles.ValuePositive
+		} else if entry.Local < 0 {
+			localStyle = m.styles.ValueNegative
+		}
+		if isSelected && m.selectedCol == ColLocal {
+			sb.WriteString(" " + m.styles.TableRowSelected.Width(localWidth).Render(localDisplay) + " ")
 		} else {
-			sb.WriteString(" " + idrStyle.Width(idrWidth).Render(idrDisplay) + " ")
+			sb.WriteString(" " + localStyle.Width(localWidth).Render(localDisplay) + " ")
 		}
 	}
 	sb.WriteString(border.Render("│"))
 
-	// CAD column (now second)
-	if isEditing && m.selectedCol == ColCAD {
-		m.editInput.Width = cadWidth - 1 // Account for $
+	// Home column (now second)
+	if isEditing && m.selectedCol == ColHome {
+		symbol := m.day.Currencies.Home.Symbol
+		inputWidth := homeWidth - lipgloss.Width(symbol) // Account for the symbol
+		m.editInput.Width = inputWidth
 		inputView := m.editInput.View()
-		// Static $ prefix + editable number
-		sb.WriteString(" $" + lipgloss.NewStyle().Width(cadWidth-1).Render(inputView) + " ")
+		// Static symbol prefix + editable number
+		sb.WriteString(" " + symbol + lipgloss.NewStyle().Width(inputWidth).Render(inputView) + " ")
 	} else {
-		cadDisplay := formatCurrency(entry.CAD, "CAD")
-		cadStyle := m.styles.ValueNeutral
-		if entry.CAD > 0 {
-			cadStyle = m.styles.ValuePositive
-		} else if entry.CAD < 0 {
-			cadStyle = m.styles.ValueNegative
-		}
-		if isSelected && m.selectedCol == ColCAD {
-			sb.WriteString(" " + m.styles.TableRowSelected.Width(cadWidth).Render(cadDisplay) + " ")
+		homeDisplay := formatEntryAmount(entry, entry.Home, m.day.Currencies.Home)
+		homeStyle := m.styles.ValueNeutral
+		if entry.Home > 0 {
+			homeStyle = m.styles.ValuePositive
+		} else if entry.Home < 0 {
+			homeStyle = m.styles.ValueNegative
+		}
+		if isSelected && m.selectedCol == ColHome {
+			sb.WriteString(" " + m.styles.TableRowSelected.Width(homeWidth).Render(homeDisplay) + " ")
 		} else {
-			sb.WriteString(" " + cadStyle.Width(cadWidth).Render(cadDisplay) + " ")
+			sb.WriteString(" " + homeStyle.Width(homeWidth).Render(homeDisplay) + " ")
 		}
 	}
 	sb.WriteString(border.Render("│"))
@@ -1615,9 +2321,16 @@ func (m EditorModel) renderHelp() string {
 				m.styles.HelpKey.Render("Enter") + m.styles.HelpDesc.Render(" save  ") +
 				m.styles.HelpKey.Render("Esc") + m.styles.HelpDesc.Render(" cancel")
 		}
-		// IDR/CAD columns
+		if m.selectedCol == ColCategory {
+			return m.styles.HelpKey.Render("Tab") + m.styles.HelpDesc.Render(" complete/next  ") +
+				m.styles.HelpKey.Render("#tag") + m.styles.HelpDesc.Render(" add tag  ") +
+				m.styles.HelpKey.Render("Enter") + m.styles.HelpDesc.Render(" save  ") +
+				m.styles.HelpKey.Render("Esc") + m.styles.HelpDesc.Render(" cancel")
+		}
+		// Local/home columns
 		if m.isNewEntry && !m.hasTypedInCell {
-			return m.styles.HelpKey.Render("←/→") + m.styles.HelpDesc.Render(" switch IDR/CAD  ") +
+			switchDesc := " switch " + m.day.Currencies.Local.Code + "/" + m.day.Currencies.Home.Code + "  "
+			return m.styles.HelpKey.Render("←/→") + m.styles.HelpDesc.Render(switchDesc) +
 				m.styles.HelpKey.Render("Enter") + m.styles.HelpDesc.Render(" save  ") +
 				m.styles.HelpKey.Render("Esc") + m.styles.HelpDesc.Render(" cancel")
 		}
@@ -1627,6 +2340,24 @@ func (m EditorModel) renderHelp() string {
 	case EditorModeScreenTime:
 		return m.styles.HelpKey.Render("Enter") + m.styles.HelpDesc.Render(" save  ") +
 			m.styles.HelpKey.Render("Esc") + m.styles.HelpDesc.Render(" cancel")
+	case EditorModeGoTo:
+		return m.styles.HelpKey.Render("Enter") + m.styles.HelpDesc.Render(" go  ") +
+			m.styles.HelpKey.Render("Esc") + m.styles.HelpDesc.Render(" cancel")
+	case EditorModeAccount:
+		return m.styles.HelpKey.Render("name") + m.styles.HelpDesc.Render(" paid from  ") +
+			m.styles.HelpKey.Render("from > to") + m.styles.HelpDesc.Render(" transfer  ") +
+			m.styles.HelpKey.Render("Enter") + m.styles.HelpDesc.Render(" save (empty for none)  ") +
+			m.styles.HelpKey.Render("Esc") + m.styles.HelpDesc.Render(" cancel")
+	case EditorModeReconcile:
+		return m.styles.HelpKey.Render("[account] amount") + m.styles.HelpDesc.Render(" counted  ") +
+			m.styles.HelpKey.Render("Enter") + m.styles.HelpDesc.Render(" record difference  ") +
+			m.styles.HelpKey.Render("Esc") + m.styles.HelpDesc.Render(" cancel")
+	case EditorModeSplit:
+		return m.styles.HelpKey.Render("payer:") + m.styles.HelpDesc.Render(" who paid  ") +
+			m.styles.HelpKey.Render("name*2") + m.styles.HelpDesc.Render(" shares  ") +
+			m.styles.HelpKey.Render("name=50k") + m.styles.HelpDesc.Render(" amount  ") +
+			m.styles.HelpKey.Render("Enter") + m.styles.HelpDesc.Render(" save (empty to unshare)  ") +
+			m.styles.HelpKey.Render("Esc") + m.styles.HelpDesc.Render(" cancel")
 	case EditorModeSearch:
 		return m.styles.HelpKey.Render("Enter") + m.styles.HelpDesc.Render(" confirm  ") +
 			m.styles.HelpKey.Render("Esc") + m.styles.HelpDesc.Render(" exit search")
@@ -1638,13 +2369,47 @@ func (m EditorModel) renderHelp() string {
 			m.styles.HelpKey.Render("Enter") + m.styles.HelpDesc.Render(" edit  ") +
 			m.styles.HelpKey.Render("a") + m.styles.HelpDesc.Render(" add  ") +
 			m.styles.HelpKey.Render("dd") + m.styles.HelpDesc.Render(" del  ") +
+			m.styles.HelpKey.Render("%") + m.styles.HelpDesc.Render(" svc+tax  ") +
+			m.styles.HelpKey.Render("S") + m.styles.HelpDesc.Render(" split  ") +
+			m.styles.HelpKey.Render("A") + m.styles.HelpDesc.Render(" account  ") +
+			m.styles.HelpKey.Render("=") + m.styles.HelpDesc.Render(" reconcile  ") +
 			m.styles.HelpKey.Render("s") + m.styles.HelpDesc.Render(" screen  ") +
 			m.styles.HelpKey.Render("j") + m.styles.HelpDesc.Render(" journal  ") +
 			m.styles.HelpKey.Render("/") + m.styles.HelpDesc.Render(" search  ") +
+			m.styles.HelpKey.Render("u/Ctrl+R") + m.styles.HelpDesc.Render(" undo/redo  ") +
+			m.styles.HelpKey.Render("[/]") + m.styles.HelpDesc.Render(" day  ") +
+			m.styles.HelpKey.Render("{/}") + m.styles.HelpDesc.Render(" data day  ") +
+			m.styles.HelpKey.Render("g") + m.styles.HelpDesc.Render(" go to  ") +
+			m.styles.HelpKey.Render("R") + m.styles.HelpDesc.Render(" rate  ") +
 			m.styles.HelpKey.Render("q") + m.styles.HelpDesc.Render(" back")
 	}
 }
 
+// SetCategorySuggestions sets the categories offered for autocomplete
+func (m *EditorModel) SetCategorySuggestions(categories []string) {
+	m.categorySuggestions = categories
+}
+
+// SetChargeProfile sets the service charge and tax added by "++" and the % key
+func (m *EditorModel) SetChargeProfile(profile ledger.ChargeProfile) {
+	m.chargeProfile = profile
+}
+
+// SetBudgets sets the spending against the budgets for the periods containing the day
+func (m *EditorModel) SetBudgets(budgets []ledger.BudgetStatus) {
+	m.budgets = budgets
+}
+
+// SetAccounts sets the accounts entries can be paid from; new entries get the first
+func (m *EditorModel) SetAccounts(accounts []ledger.Account) {
+	m.accounts = accounts
+}
+
+// SetAccountBalances sets each account's balance at the end of the day
+func (m *EditorModel) SetAccountBalances(balances []ledger.AccountBalance) {
+	m.accountBalances = balances
+}
+
 // SetDay sets the day data
 func (m *EditorModel) SetDay(day *ledger.Day) {
 	m.day = day
@@ -1656,6 +2421,11 @@ func (m EditorModel) GetDay() *ledger.Day {
 	return m.day
 }
 
+// GetGoToDate returns the date entered in the go-to prompt
+func (m EditorModel) GetGoToDate() time.Time {
+	return m.goToDate
+}
+
 // SetSize sets the view dimensions
 func (m *EditorModel) SetSize(width, height int) {
 	m.width = width
@@ -1666,6 +2436,15 @@ func (m *EditorModel) SetSize(width, height int) {
 // RefreshCurrencyStatus refreshes the currency status message
 func (m *EditorModel) RefreshCurrencyStatus() {
 	m.currencyStatus = m.converter.GetStatusMessage()
+	if m.refreshing {
+		m.currencyStatus += " ⟳"
+	}
+}
+
+// SetRefreshing marks whether an exchange-rate refresh is in flight and updates the status
+func (m *EditorModel) SetRefreshing(refreshing bool) {
+	m.refreshing = refreshing
+	m.RefreshCurrencyStatus()
 }
 
 // ClearNotification clears the notification
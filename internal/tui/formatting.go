@@ -1,34 +1,39 @@
 package tui
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"ledger-a/internal/currency"
+	"ledger-a/internal/ledger"
 )
 
-// formatCurrency formats a currency value with commas
-func formatCurrency(amount float64, currency string) string {
-	if currency == "CAD" {
-		if amount >= 0 {
-			return "$" + formatNumberWithCommas(amount, 2)
-		}
-		return "-$" + formatNumberWithCommas(-amount, 2)
-	}
+// formatCurrency formats a money amount with its currency symbol and commas
+func formatCurrency(amount ledger.Money, currency ledger.Currency) string {
 	if amount >= 0 {
-		return "Rp " + formatNumberWithCommas(amount, 0)
+		return currency.Symbol + formatMoneyWithCommas(amount, currency)
 	}
-	return "-Rp " + formatNumberWithCommas(-amount, 0)
+	return "-" + currency.Symbol + formatMoneyWithCommas(-amount, currency)
 }
 
-// formatNumberWithCommas formats a number with comma separators
-func formatNumberWithCommas(n float64, decimals int) string {
-	// Format the number first
-	var formatted string
-	if decimals > 0 {
-		formatted = fmt.Sprintf("%.*f", decimals, n)
-	} else {
-		formatted = fmt.Sprintf("%.0f", n)
+// formatEntryAmount formats one of an entry's amounts, showing "no rate" for the
+// amount still waiting for the rate of the entry's date
+func formatEntryAmount(entry *ledger.Entry, amount ledger.Money, currency ledger.Currency) string {
+	if entry.NeedsRate() && entry.IsDerived(currency.Code) && amount == 0 {
+		return "no rate"
+	}
+	return formatCurrency(amount, currency)
+}
+
+// formatMoneyWithCommas formats a money amount with comma separators and no symbol
+func formatMoneyWithCommas(amount ledger.Money, currency ledger.Currency) string {
+	formatted := amount.Format(currency)
+
+	sign := ""
+	if strings.HasPrefix(formatted, "-") {
+		sign = "-"
+		formatted = formatted[1:]
 	}
 
 	// Split into integer and decimal parts
@@ -37,6 +42,7 @@ func formatNumberWithCommas(n float64, decimals int) string {
 
 	// Add commas to integer part
 	var result strings.Builder
+	result.WriteString(sign)
 	length := len(intPart)
 	for i, digit := range intPart {
 		if i > 0 && (length-i)%3 == 0 {
@@ -69,3 +75,41 @@ func truncateStr(s string, maxLen int) string {
 	truncated := lipgloss.NewStyle().MaxWidth(maxLen - 3).Render(s)
 	return truncated + "..."
 }
+
+// formatFXCost compares cash spending at the rates the cash cost with the market
+// rate (e.g., "Cash spent Rp 1,200,000: $111.07 at withdrawal rates vs $101.69 at market, FX cost $9.38")
+func formatFXCost(value ledger.CashValue, spent ledger.Money, currencies ledger.Pair) string {
+	return "Cash spent " + formatCurrency(spent, currencies.Local) + ": " +
+		formatCurrency(value.Effective, currencies.Home) + " at withdrawal rates vs " +
+		formatCurrency(value.Market, currencies.Home) + " at market, FX cost " +
+		formatCurrency(value.FXCost(), currencies.Home)
+}
+
+// formatCashBatch describes cash bought in one go and the rate it cost
+// (e.g., "Mar 15 Cash: Rp 1,000,000 for $105.00, 1 CAD = 9524 IDR, Rp 200,000 left")
+func formatCashBatch(batch *ledger.CashBatch, currencies ledger.Pair) string {
+	return batch.Date.Format("Jan 2") + " " + batch.Account + ": " +
+		formatCurrency(batch.Local, currencies.Local) + " for " + formatCurrency(batch.Home, currencies.Home) +
+		", 1 " + currencies.Home.Code + " = " + currency.FormatRateValue(batch.EffectiveRate(currencies)) + " " + currencies.Local.Code +
+		", " + formatCurrency(batch.Left, currencies.Local) + " left"
+}
+
+// formatChargeTotals describes the breakdown of bills with charges, with amounts
+// as positive values (e.g., "2 bills: menu Rp 300,000 + service Rp 30,000 + tax Rp 36,300 + tip Rp 20,000")
+func formatChargeTotals(totals ledger.ChargeTotals, currency ledger.Currency) string {
+	noun := "bills"
+	if totals.Entries == 1 {
+		noun = "bill"
+	}
+	parts := []string{"menu " + formatCurrency(totals.Base.Abs(), currency)}
+	if totals.Service != 0 {
+		parts = append(parts, "service "+formatCurrency(totals.Service.Abs(), currency))
+	}
+	if totals.Tax != 0 {
+		parts = append(parts, "tax "+formatCurrency(totals.Tax.Abs(), currency))
+	}
+	if totals.Tip != 0 {
+		parts = append(parts, "tip "+formatCurrency(totals.Tip.Abs(), currency))
+	}
+	return itoa(totals.Entries) + " " + noun + ": " + strings.Join(parts, " + ")
+}
@@ -0,0 +1,486 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ledger-a/internal/currency"
+	"ledger-a/internal/ledger"
+)
+
+// ImportAction represents an action taken in the import view
+type ImportAction int
+
+const (
+	ImportActionNone ImportAction = iota
+	ImportActionBack
+	ImportActionDone
+)
+
+// importStage is the step of the import the user is on
+type importStage int
+
+const (
+	importStageFile    importStage = iota // Choosing the statement and profile
+	importStagePreview                    // Accepting or rejecting rows
+)
+
+// importRatesMsg carries the historical rates fetched in the background for the
+// statement dates that weren't cached
+type importRatesMsg struct {
+	dates []time.Time
+	rates []float64 // Rate for each date fetched before the first error
+	err   error
+}
+
+// fetchImportRatesCmd fetches the rates for the given dates off the UI goroutine,
+// stopping at the first failure
+func fetchImportRatesCmd(converter *currency.Converter, dates []time.Time) tea.Cmd {
+	return func() tea.Msg {
+		msg := importRatesMsg{dates: dates}
+		for _, date := range dates {
+			rate, err := converter.FetchRateOn(date)
+			if err != nil {
+				msg.err = err
+				break
+			}
+			msg.rates = append(msg.rates, rate)
+		}
+		return msg
+	}
+}
+
+// ImportModel imports a bank or card statement: pick a file and a saved column
+// mapping, then accept or reject each row before anything is written
+type ImportModel struct {
+	styles    *Styles
+	service   *ledger.Service
+	converter *currency.Converter
+	width     int
+	height    int
+
+	stage      importStage
+	profiles   []ledger.ImportProfile
+	profileIdx int
+	pathInput  textinput.Model
+
+	importer    *ledger.Importer
+	rows        []*ledger.ImportRow
+	selectedIdx int
+
+	// Dates of the imported entries, for showing them afterwards
+	importedStart time.Time
+	importedEnd   time.Time
+
+	notification string
+	notifyError  bool
+}
+
+// NewImportModel creates an import view with the profiles saved in the data directory
+func NewImportModel(styles *Styles, service *ledger.Service, converter *currency.Converter) ImportModel {
+	pathInput := textinput.New()
+	pathInput.Placeholder = "~/Downloads/statement.csv"
+	pathInput.Prompt = ""
+	pathInput.Width = 50
+	pathInput.Focus()
+
+	m := ImportModel{
+		styles:    styles,
+		service:   service,
+		converter: converter,
+		width:     80,
+		height:    24,
+		pathInput: pathInput,
+	}
+
+	profiles, err := service.GetImportProfiles()
+	if err != nil {
+		m.setNotification(err.Error(), true)
+	}
+	m.profiles = profiles
+	return m
+}
+
+// Init initializes the import view
+func (m ImportModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles messages for the import view
+func (m ImportModel) Update(msg tea.Msg) (ImportModel, tea.Cmd, ImportAction) {
+	if msg, ok := msg.(importRatesMsg); ok {
+		m.applyRates(msg)
+		return m, nil, ImportActionNone
+	}
+	if m.stage == importStagePreview {
+		return m.updatePreview(msg)
+	}
+	return m.updateFile(msg)
+}
+
+func (m ImportModel) updateFile(msg tea.Msg) (ImportModel, tea.Cmd, ImportAction) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			return m, nil, ImportActionBack
+		case "tab":
+			if len(m.profiles) > 0 {
+				m.profileIdx = (m.profileIdx + 1) % len(m.profiles)
+			}
+			return m, nil, ImportActionNone
+		case "shift+tab":
+			if len(m.profiles) > 0 {
+				m.profileIdx = (m.profileIdx + len(m.profiles) - 1) % len(m.profiles)
+			}
+			return m, nil, ImportActionNone
+		case "enter":
+			return m, m.loadPreview(), ImportActionNone
+		}
+	}
+
+	var cmd tea.Cmd
+	m.pathInput, cmd = m.pathInput.Update(msg)
+	return m, cmd, ImportActionNone
+}
+
+// loadPreview reads the statement with the selected profile and moves to the preview.
+// Rows are converted at the cached rates; it returns a command fetching the rates
+// that aren't cached yet.
+func (m *ImportModel) loadPreview() tea.Cmd {
+	if len(m.profiles) == 0 {
+		m.setNotification("No import profiles", true)
+		return nil
+	}
+
+	path := strings.TrimSpace(m.pathInput.Value())
+	if path == "" {
+		m.setNotification("Enter the statement file", true)
+		return nil
+	}
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		m.setNotification(err.Error(), true)
+		return nil
+	}
+	defer file.Close()
+
+	m.importer = ledger.NewImporter(m.service, m.profiles[m.profileIdx], m.rateOn)
+	rows, err := m.importer.Preview(file)
+	if err != nil {
+		m.setNotification(err.Error(), true)
+		return nil
+	}
+	if len(rows) == 0 {
+		m.setNotification("The statement has no rows", true)
+		return nil
+	}
+
+	m.rows = rows
+	m.selectedIdx = 0
+	m.stage = importStagePreview
+	m.clearNotification()
+
+	dates := m.missingRateDates()
+	if len(dates) == 0 || !m.converter.CanRefresh() {
+		return nil
+	}
+	m.setNotification(fmt.Sprintf("Fetching rates for %d dates...", len(dates)), false)
+	return fetchImportRatesCmd(m.converter, dates)
+}
+
+// rateOn looks up the cached rate for a statement date (0 if it isn't cached), so
+// the preview never waits on the API. Rows without a rate are converted once it's fetched.
+func (m ImportModel) rateOn(date time.Time) float64 {
+	rate, _ := m.converter.CachedRateOn(date)
+	return rate
+}
+
+// missingRateDates returns the past dates of the rows waiting for a rate
+func (m ImportModel) missingRateDates() []time.Time {
+	var dates []time.Time
+	seen := make(map[string]bool)
+	today := ledger.Today()
+	for _, row := range m.rows {
+		if row.Entry == nil || !row.Entry.NeedsRate() || !row.Entry.Date.Before(today) {
+			continue
+		}
+		key := row.Entry.DateString()
+		if !seen[key] {
+			seen[key] = true
+			dates = append(dates, row.Entry.Date)
+		}
+	}
+	return dates
+}
+
+// applyRates caches the fetched rates and converts the rows that were waiting for them
+func (m *ImportModel) applyRates(msg importRatesMsg) {
+	currencies := m.service.GetCurrencies()
+	var saveErr error
+	for i, rate := range msg.rates {
+		date := msg.dates[i]
+		if err := m.converter.ApplyRateOn(date, rate, nil); err != nil {
+			saveErr = err
+		}
+		for _, row := range m.rows {
+			if row.Entry != nil && row.Entry.Date.Equal(date) {
+				row.Entry.FillRate(currencies, rate)
+			}
+		}
+	}
+
+	switch {
+	case msg.err != nil:
+		// Holds off fetching that date again, like any failed fetch
+		err := m.converter.ApplyRateOn(msg.dates[len(msg.rates)], 0, msg.err)
+		m.setNotification("Some rows have no rate and are converted when their day is opened: "+err.Error(), true)
+	case saveErr != nil:
+		m.setNotification("Rate cache not saved: "+saveErr.Error(), true)
+	case m.stage == importStagePreview:
+		m.clearNotification()
+	}
+}
+
+func (m ImportModel) updatePreview(msg tea.Msg) (ImportModel, tea.Cmd, ImportAction) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil, ImportActionNone
+	}
+
+	switch keyMsg.String() {
+	case "up", "k":
+		if m.selectedIdx > 0 {
+			m.selectedIdx--
+		}
+	case "down", "j":
+		if m.selectedIdx < len(m.rows)-1 {
+			m.selectedIdx++
+		}
+	case " ", "x":
+		row := m.rows[m.selectedIdx]
+		if row.Err == nil {
+			row.Accepted = !row.Accepted
+		}
+		if m.selectedIdx < len(m.rows)-1 {
+			m.selectedIdx++
+		}
+	case "a":
+		// Accept every row that can be imported, duplicates included
+		for _, row := range m.rows {
+			row.Accepted = row.Err == nil
+		}
+	case "n":
+		for _, row := range m.rows {
+			row.Accepted = false
+		}
+	case "enter":
+		return m.runImport()
+	case "esc", "q":
+		m.stage = importStageFile
+		m.rows = nil
+		m.clearNotification()
+		return m, textinput.Blink, ImportActionNone
+	}
+	return m, nil, ImportActionNone
+}
+
+// runImport writes the accepted rows to their days
+func (m ImportModel) runImport() (ImportModel, tea.Cmd, ImportAction) {
+	var start, end time.Time
+	for _, row := range m.rows {
+		if !row.Accepted {
+			continue
+		}
+		if start.IsZero() || row.Entry.Date.Before(start) {
+			start = row.Entry.Date
+		}
+		if end.IsZero() || row.Entry.Date.After(end) {
+			end = row.Entry.Date
+		}
+	}
+	if start.IsZero() {
+		m.setNotification("No rows selected", true)
+		return m, nil, ImportActionNone
+	}
+
+	imported, err := m.importer.Import(m.rows)
+	if err != nil {
+		m.setNotification(fmt.Sprintf("Import failed after %d entries: %v", imported, err), true)
+		return m, nil, ImportActionNone
+	}
+
+	m.importedStart, m.importedEnd = start, end
+	m.setNotification(fmt.Sprintf("Imported %d entries", imported), false)
+	return m, nil, ImportActionDone
+}
+
+// ImportedRange returns the first and last date of the imported entries
+func (m ImportModel) ImportedRange() (time.Time, time.Time) {
+	return m.importedStart, m.importedEnd
+}
+
+// GetNotification returns the current notification
+func (m ImportModel) GetNotification() string {
+	return m.notification
+}
+
+func (m *ImportModel) setNotification(msg string, isError bool) {
+	m.notification = msg
+	m.notifyError = isError
+}
+
+func (m *ImportModel) clearNotification() {
+	m.notification = ""
+	m.notifyError = false
+}
+
+// View renders the import view
+func (m ImportModel) View() string {
+	notification := m.notification
+	if m.notifyError && notification != "" {
+		notification = "Error: " + notification
+	}
+
+	if m.stage == importStagePreview {
+		footer := RenderRibbonFooter(m.previewSummary(), m.renderPreviewHelp(), m.styles)
+		return RenderBoxWithTitle(m.renderPreview(), "Import Preview", footer, notification, m.width, m.height)
+	}
+
+	footer := RenderRibbonFooter("", m.renderFileHelp(), m.styles)
+	return RenderBoxWithTitle(m.renderFile(), "Import Statement", footer, notification, m.width, m.height)
+}
+
+func (m ImportModel) renderFile() string {
+	var sb strings.Builder
+
+	sb.WriteString("\n\n" + m.styles.InputLabel.Render("Statement file:") + "\n\n")
+	sb.WriteString("  " + m.pathInput.View() + "\n\n")
+
+	if len(m.profiles) == 0 {
+		path := filepath.Join(m.service.GetCSVManager().GetDataDir(), ledger.ImportProfilesFileName)
+		sb.WriteString(m.styles.Subtitle.Render("No import profiles yet - add one to " + path))
+		return sb.String()
+	}
+
+	profile := m.profiles[m.profileIdx]
+	sb.WriteString(m.styles.InputLabel.Render("Profile:") + "  ")
+	sb.WriteString(m.styles.MenuItemSelected.Render(profile.Name))
+	if len(m.profiles) > 1 {
+		sb.WriteString(m.styles.Subtitle.Render(fmt.Sprintf("  (%d of %d)", m.profileIdx+1, len(m.profiles))))
+	}
+	return sb.String()
+}
+
+func (m ImportModel) renderPreview() string {
+	currencies := m.service.GetCurrencies()
+	contentWidth := m.width - 8
+	const (
+		markWidth  = 4
+		dateWidth  = 11
+		localWidth = 16
+		homeWidth  = 13
+		noteWidth  = 12
+	)
+	descWidth := contentWidth - markWidth - dateWidth - localWidth - homeWidth - noteWidth - 5
+	if descWidth < 15 {
+		descWidth = 15
+	}
+
+	header := fmt.Sprintf("%-*s %-*s %-*s %*s %*s %-*s", markWidth, "", dateWidth, "Date",
+		descWidth, "Description", localWidth, currencies.Local.Code, homeWidth, currencies.Home.Code, noteWidth, "")
+	lines := []string{m.styles.TableHeader.Render(header)}
+
+	// Scroll so the selected row stays visible
+	visible := max(1, m.height-10)
+	first := 0
+	if m.selectedIdx >= visible {
+		first = m.selectedIdx - visible + 1
+	}
+	last := min(len(m.rows), first+visible)
+
+	for i := first; i < last; i++ {
+		row := m.rows[i]
+		mark := "[ ]"
+		if row.Accepted {
+			mark = "[x]"
+		}
+
+		var line string
+		if row.Err != nil {
+			line = fmt.Sprintf("%-*s %-*s %s", markWidth, mark, dateWidth, fmt.Sprintf("line %d", row.Line),
+				truncateStr(row.Err.Error(), descWidth+localWidth+homeWidth+noteWidth+3))
+		} else {
+			note := ""
+			if row.Duplicate {
+				note = "duplicate"
+			}
+			entry := row.Entry
+			line = fmt.Sprintf("%-*s %-*s %-*s %*s %*s %-*s", markWidth, mark,
+				dateWidth, entry.DateDisplay(),
+				descWidth, truncateStr(entry.Description, descWidth),
+				localWidth, formatEntryAmount(entry, entry.Local, currencies.Local),
+				homeWidth, formatEntryAmount(entry, entry.Home, currencies.Home),
+				noteWidth, note)
+		}
+
+		switch {
+		case i == m.selectedIdx:
+			line = m.styles.TableRowSelected.Render(line)
+		case row.Err != nil || row.Duplicate:
+			line = m.styles.ValueNeutral.Render(line)
+		default:
+			line = m.styles.TableRow.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// previewSummary counts the rows by status
+func (m ImportModel) previewSummary() string {
+	var accepted, duplicates, invalid int
+	for _, row := range m.rows {
+		if row.Accepted {
+			accepted++
+		}
+		if row.Duplicate {
+			duplicates++
+		}
+		if row.Err != nil {
+			invalid++
+		}
+	}
+	return fmt.Sprintf("%d of %d selected · %d duplicate · %d invalid", accepted, len(m.rows), duplicates, invalid)
+}
+
+func (m ImportModel) renderFileHelp() string {
+	return m.styles.HelpKey.Render("Enter") + m.styles.HelpDesc.Render(" preview  ") +
+		m.styles.HelpKey.Render("Tab") + m.styles.HelpDesc.Render(" profile  ") +
+		m.styles.HelpKey.Render("Esc") + m.styles.HelpDesc.Render(" back")
+}
+
+func (m ImportModel) renderPreviewHelp() string {
+	return m.styles.HelpKey.Render("Space") + m.styles.HelpDesc.Render(" accept/reject  ") +
+		m.styles.HelpKey.Render("a/n") + m.styles.HelpDesc.Render(" all/none  ") +
+		m.styles.HelpKey.Render("Enter") + m.styles.HelpDesc.Render(" import  ") +
+		m.styles.HelpKey.Render("Esc") + m.styles.HelpDesc.Render(" back")
+}
+
+// SetSize sets the view dimensions
+func (m *ImportModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
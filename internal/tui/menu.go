@@ -15,6 +15,10 @@ const (
 	MenuToday
 	MenuQuery
 	MenuAddPastDay
+	MenuImport
+	MenuReport
+	MenuCalendar
+	MenuRecurring
 	MenuQuit
 )
 
@@ -25,6 +29,9 @@ type MenuModel struct {
 	styles   *Styles
 	width    int
 	height   int
+
+	notices      []string // Problems found at startup, shown under the items
+	notification string   // Error from the last selection, cleared on the next key
 }
 
 type menuItem struct {
@@ -44,6 +51,10 @@ func NewMenuModel(styles *Styles) MenuModel {
 			{key: "1", label: "Today (" + today + ")", description: "View and edit today's entries", selection: MenuToday},
 			{key: "2", label: "Query", description: "View a single day or date range", selection: MenuQuery},
 			{key: "3", label: "Add Entry for Past Day", description: "Add entries for a day you missed", selection: MenuAddPastDay},
+			{key: "4", label: "Import Statement", description: "Import a bank or card statement CSV", selection: MenuImport},
+			{key: "5", label: "Report", description: "Weekly and monthly totals for a date range", selection: MenuReport},
+			{key: "6", label: "Calendar", description: "Browse days by month and pick a range", selection: MenuCalendar},
+			{key: "7", label: "Recurring Entries", description: "Rent, rentals and top-ups added on a schedule", selection: MenuRecurring},
 		},
 		styles: styles,
 		width:  80,
@@ -60,6 +71,7 @@ func (m MenuModel) Init() tea.Cmd {
 func (m MenuModel) Update(msg tea.Msg) (MenuModel, tea.Cmd, MenuSelection) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.notification = ""
 		switch msg.String() {
 		case "up", "k":
 			if m.selected > 0 {
@@ -77,6 +89,14 @@ func (m MenuModel) Update(msg tea.Msg) (MenuModel, tea.Cmd, MenuSelection) {
 			return m, nil, MenuQuery
 		case "3":
 			return m, nil, MenuAddPastDay
+		case "4":
+			return m, nil, MenuImport
+		case "5":
+			return m, nil, MenuReport
+		case "6":
+			return m, nil, MenuCalendar
+		case "7":
+			return m, nil, MenuRecurring
 		case "q", "ctrl+c":
 			return m, nil, MenuQuit
 		}
@@ -131,13 +151,28 @@ func (m MenuModel) View() string {
 		content.WriteString("\n\n")
 	}
 
+	for _, notice := range m.notices {
+		content.WriteString(m.styles.NotificationError.Width(innerWidth - 4).Render(notice))
+		content.WriteString("\n")
+	}
+
 	// Footer with ribbon styling
 	help := m.styles.HelpKey.Render("↑/↓") + m.styles.HelpDesc.Render(" navigate  ") +
 		m.styles.HelpKey.Render("Enter") + m.styles.HelpDesc.Render(" select  ") +
 		m.styles.HelpKey.Render("q") + m.styles.HelpDesc.Render(" quit")
 	footer.WriteString(RenderRibbonFooter("", help, m.styles))
 
-	return RenderBoxWithTitle(content.String(), "LEDGER-A", footer.String(), "", m.width, m.height)
+	return RenderBoxWithTitle(content.String(), "LEDGER-A", footer.String(), m.notification, m.width, m.height)
+}
+
+// SetNotices sets the problems shown under the menu items
+func (m *MenuModel) SetNotices(notices []string) {
+	m.notices = notices
+}
+
+// SetNotification sets a notification shown until the next key
+func (m *MenuModel) SetNotification(msg string) {
+	m.notification = msg
 }
 
 // SetSize sets the size of the menu
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -18,6 +19,8 @@ const (
 	RangeViewBack
 	RangeViewSelectDay
 	RangeViewShowJournal
+	RangeViewShowReport
+	RangeViewShowBalances
 )
 
 // RangeViewItem represents an item in the range view (entry or journal)
@@ -40,6 +43,14 @@ type RangeViewModel struct {
 	height       int
 	notification string
 
+	// Per-category subtotals or budget vs actual instead of the entry list
+	showCategories bool
+	showBudgets    bool
+	budgets        []ledger.BudgetStatus // Every budget period overlapping the range
+
+	cashValues  map[string]ledger.CashValue // Cash expenses at the rates their cash cost, by entry ID
+	cashBatches []*ledger.CashBatch         // Cash bought up to the end of the range
+
 	// For journal viewing
 	viewingJournal bool
 	journalContent string
@@ -145,6 +156,16 @@ func (m RangeViewModel) Update(msg tea.Msg) (RangeViewModel, tea.Cmd, RangeViewA
 		case "/":
 			cmd = m.search.Activate()
 			return m, cmd, RangeViewNone
+		case "c":
+			m.showCategories = !m.showCategories
+			m.showBudgets = false
+		case "b":
+			m.showBudgets = !m.showBudgets
+			m.showCategories = false
+		case "r":
+			return m, nil, RangeViewShowReport
+		case "o":
+			return m, nil, RangeViewShowBalances
 		case "esc":
 			if m.search.HasQuery() {
 				m.search.Clear()
@@ -155,6 +176,9 @@ func (m RangeViewModel) Update(msg tea.Msg) (RangeViewModel, tea.Cmd, RangeViewA
 		case "q":
 			return m, nil, RangeViewBack
 		case "enter":
+			if m.showCategories || m.showBudgets {
+				return m, nil, RangeViewNone
+			}
 			if len(m.items) > 0 && m.selectedIdx < len(m.items) {
 				item := m.items[m.selectedIdx]
 				if item.IsJournal {
@@ -193,7 +217,23 @@ func (m RangeViewModel) View() string {
 	}
 
 	// Table with borders
-	content.WriteString(m.renderTable())
+	switch {
+	case m.showCategories:
+		content.WriteString(m.renderCategoryTable())
+	case m.showBudgets:
+		content.WriteString(m.renderBudgetTable())
+	default:
+		content.WriteString(m.renderTable())
+		if totals := ledger.SumCharges(m.dateRange.AllEntries(m.search.GetQuery()), m.dateRange.Currencies); totals.Entries > 0 {
+			content.WriteString("\n" + m.styles.Subtitle.Render(formatChargeTotals(totals, m.dateRange.Currencies.Local)))
+		}
+		if fx := m.renderFXCost(); fx != "" {
+			content.WriteString("\n" + fx)
+		}
+		if batches := m.renderCashBatches(); batches != "" {
+			content.WriteString("\n" + batches)
+		}
+	}
 
 	// Footer with ribbon styling
 	footer.WriteString(RenderRibbonFooter("", m.renderHelp(), m.styles))
@@ -236,7 +276,7 @@ func (m RangeViewModel) renderJournalView() string {
 }
 
 func (m RangeViewModel) renderTable() string {
-	descWidth := m.width - 80
+	descWidth := m.width - 97
 	if descWidth < 20 {
 		descWidth = 20
 	}
@@ -245,7 +285,7 @@ func (m RangeViewModel) renderTable() string {
 	border := m.styles.TableBorder
 
 	// Top border
-	sb.WriteString(border.Render("┌" + strings.Repeat("─", 3) + "┬" + strings.Repeat("─", 14) + "┬" + strings.Repeat("─", descWidth+2) + "┬" + strings.Repeat("─", 16) + "┬" + strings.Repeat("─", 18) + "┬" + strings.Repeat("─", 10) + "┐"))
+	sb.WriteString(border.Render("┌" + strings.Repeat("─", 3) + "┬" + strings.Repeat("─", 14) + "┬" + strings.Repeat("─", descWidth+2) + "┬" + strings.Repeat("─", 16) + "┬" + strings.Repeat("─", 16) + "┬" + strings.Repeat("─", 18) + "┬" + strings.Repeat("─", 10) + "┐"))
 	sb.WriteString("\n")
 
 	// Header
@@ -256,16 +296,18 @@ func (m RangeViewModel) renderTable() string {
 	sb.WriteString(border.Render("│"))
 	sb.WriteString(" " + m.styles.TableHeader.Width(descWidth).Render("Description") + " ")
 	sb.WriteString(border.Render("│"))
-	sb.WriteString(" " + m.styles.TableHeader.Width(14).Align(lipgloss.Right).Render("CAD") + " ")
+	sb.WriteString(" " + m.styles.TableHeader.Width(14).Render("Category") + " ")
+	sb.WriteString(border.Render("│"))
+	sb.WriteString(" " + m.styles.TableHeader.Width(14).Align(lipgloss.Right).Render(m.dateRange.Currencies.Home.Code) + " ")
 	sb.WriteString(border.Render("│"))
-	sb.WriteString(" " + m.styles.TableHeader.Width(16).Align(lipgloss.Right).Render("IDR") + " ")
+	sb.WriteString(" " + m.styles.TableHeader.Width(16).Align(lipgloss.Right).Render(m.dateRange.Currencies.Local.Code) + " ")
 	sb.WriteString(border.Render("│"))
 	sb.WriteString(" " + m.styles.TableHeader.Width(8).Render("Time") + " ")
 	sb.WriteString(border.Render("│"))
 	sb.WriteString("\n")
 
 	// Header separator
-	sb.WriteString(border.Render("├" + strings.Repeat("─", 3) + "┼" + strings.Repeat("─", 14) + "┼" + strings.Repeat("─", descWidth+2) + "┼" + strings.Repeat("─", 16) + "┼" + strings.Repeat("─", 18) + "┼" + strings.Repeat("─", 10) + "┤"))
+	sb.WriteString(border.Render("├" + strings.Repeat("─", 3) + "┼" + strings.Repeat("─", 14) + "┼" + strings.Repeat("─", descWidth+2) + "┼" + strings.Repeat("─", 16) + "┼" + strings.Repeat("─", 16) + "┼" + strings.Repeat("─", 18) + "┼" + strings.Repeat("─", 10) + "┤"))
 	sb.WriteString("\n")
 
 	// Rows
@@ -275,7 +317,7 @@ func (m RangeViewModel) renderTable() string {
 		if m.search.HasQuery() {
 			emptyMsg = "No matches for '" + m.search.GetQuery() + "'"
 		}
-		totalWidth := 3 + 14 + descWidth + 2 + 16 + 18 + 10 + 5
+		totalWidth := 3 + 14 + descWidth + 2 + 16 + 16 + 18 + 10 + 6
 		sb.WriteString(" " + m.styles.Subtitle.Width(totalWidth).Render(emptyMsg) + " ")
 		sb.WriteString(border.Render("│"))
 		sb.WriteString("\n")
@@ -306,7 +348,7 @@ func (m RangeViewModel) renderTable() string {
 	}
 
 	// Separator before totals
-	sb.WriteString(border.Render("├" + strings.Repeat("─", 3) + "┼" + strings.Repeat("─", 14) + "┼" + strings.Repeat("─", descWidth+2) + "┼" + strings.Repeat("─", 16) + "┼" + strings.Repeat("─", 18) + "┼" + strings.Repeat("─", 10) + "┤"))
+	sb.WriteString(border.Render("├" + strings.Repeat("─", 3) + "┼" + strings.Repeat("─", 14) + "┼" + strings.Repeat("─", descWidth+2) + "┼" + strings.Repeat("─", 16) + "┼" + strings.Repeat("─", 16) + "┼" + strings.Repeat("─", 18) + "┼" + strings.Repeat("─", 10) + "┤"))
 	sb.WriteString("\n")
 
 	// Totals row
@@ -314,7 +356,7 @@ func (m RangeViewModel) renderTable() string {
 	sb.WriteString("\n")
 
 	// Bottom border
-	sb.WriteString(border.Render("└" + strings.Repeat("─", 3) + "┴" + strings.Repeat("─", 14) + "┴" + strings.Repeat("─", descWidth+2) + "┴" + strings.Repeat("─", 16) + "┴" + strings.Repeat("─", 18) + "┴" + strings.Repeat("─", 10) + "┘"))
+	sb.WriteString(border.Render("└" + strings.Repeat("─", 3) + "┴" + strings.Repeat("─", 14) + "┴" + strings.Repeat("─", descWidth+2) + "┴" + strings.Repeat("─", 16) + "┴" + strings.Repeat("─", 16) + "┴" + strings.Repeat("─", 18) + "┴" + strings.Repeat("─", 10) + "┘"))
 
 	return sb.String()
 }
@@ -351,24 +393,28 @@ func (m RangeViewModel) renderTableRow(idx int, entry *ledger.Entry, descWidth i
 	sb.WriteString(" " + rowStyle.Width(descWidth).Render(desc) + " ")
 	sb.WriteString(border.Render("│"))
 
-	// CAD
-	cadStyle := m.styles.ValueNeutral
-	if entry.CAD > 0 {
-		cadStyle = m.styles.ValuePositive
-	} else if entry.CAD < 0 {
-		cadStyle = m.styles.ValueNegative
+	// Category
+	sb.WriteString(" " + m.styles.TableRow.Width(14).Render(truncateStr(entry.CategoryLabel(), 14)) + " ")
+	sb.WriteString(border.Render("│"))
+
+	// Home
+	homeStyle := m.styles.ValueNeutral
+	if entry.Home > 0 {
+		homeStyle = m.styles.ValuePositive
+	} else if entry.Home < 0 {
+		homeStyle = m.styles.ValueNegative
 	}
-	sb.WriteString(" " + cadStyle.Width(14).Align(lipgloss.Right).Render(formatCurrency(entry.CAD, "CAD")) + " ")
+	sb.WriteString(" " + homeStyle.Width(14).Align(lipgloss.Right).Render(formatEntryAmount(entry, entry.Home, m.dateRange.Currencies.Home)) + " ")
 	sb.WriteString(border.Render("│"))
 
-	// IDR
-	idrStyle := m.styles.ValueNeutral
-	if entry.IDR > 0 {
-		idrStyle = m.styles.ValuePositive
-	} else if entry.IDR < 0 {
-		idrStyle = m.styles.ValueNegative
+	// Local
+	localStyle := m.styles.ValueNeutral
+	if entry.Local > 0 {
+		localStyle = m.styles.ValuePositive
+	} else if entry.Local < 0 {
+		localStyle = m.styles.ValueNegative
 	}
-	sb.WriteString(" " + idrStyle.Width(16).Align(lipgloss.Right).Render(formatCurrency(entry.IDR, "IDR")) + " ")
+	sb.WriteString(" " + localStyle.Width(16).Align(lipgloss.Right).Render(formatEntryAmount(entry, entry.Local, m.dateRange.Currencies.Local)) + " ")
 	sb.WriteString(border.Render("│"))
 
 	// Screen time
@@ -426,7 +472,11 @@ func (m RangeViewModel) renderJournalRow(idx int, item RangeViewItem, descWidth
 	sb.WriteString(" " + journalStyle.Width(descWidth).Render(preview) + " ")
 	sb.WriteString(border.Render("│"))
 
-	// Empty CAD/IDR columns for journal
+	// Empty category column for journal
+	sb.WriteString(" " + lipgloss.NewStyle().Width(14).Render("") + " ")
+	sb.WriteString(border.Render("│"))
+
+	// Empty amount columns for journal
 	sb.WriteString(" " + lipgloss.NewStyle().Width(14).Render("") + " ")
 	sb.WriteString(border.Render("│"))
 	sb.WriteString(" " + lipgloss.NewStyle().Width(16).Render("") + " ")
@@ -447,34 +497,175 @@ func (m RangeViewModel) renderTotalsRow(descWidth int) string {
 		label = "Filtered"
 	}
 
-	var totalCAD, totalIDR float64
+	var totalHome, totalLocal ledger.Money
 	if query != "" {
-		totalCAD = m.dateRange.FilteredTotalCAD(query)
-		totalIDR = m.dateRange.FilteredTotalIDR(query)
+		totalHome = m.dateRange.FilteredTotalHome(query)
+		totalLocal = m.dateRange.FilteredTotalLocal(query)
 	} else {
-		totalCAD = m.dateRange.TotalCAD()
-		totalIDR = m.dateRange.TotalIDR()
+		totalHome = m.dateRange.TotalHome()
+		totalLocal = m.dateRange.TotalLocal()
 	}
 
-	sb.WriteString(border.Render("│"))
-	sb.WriteString("   ")
-	sb.WriteString(border.Render("│"))
-	sb.WriteString(" " + m.styles.TableCell.Width(12).Render("") + " ")
-	sb.WriteString(border.Render("│"))
-	sb.WriteString(" " + m.styles.TotalsLabel.Width(descWidth).Render(label) + " ")
-	sb.WriteString(border.Render("│"))
-	sb.WriteString(" " + m.styles.TotalsValue.Width(14).Align(lipgloss.Right).Render(formatCurrency(totalCAD, "CAD")) + " ")
-	sb.WriteString(border.Render("│"))
-	sb.WriteString(" " + m.styles.TotalsValue.Width(16).Align(lipgloss.Right).Render(formatCurrency(totalIDR, "IDR")) + " ")
-	sb.WriteString(border.Render("│"))
-	sb.WriteString(" " + m.styles.TableCell.Width(8).Render("") + " ")
-	sb.WriteString(border.Render("│"))
+	row := func(label, home, local string) {
+		sb.WriteString(border.Render("│"))
+		sb.WriteString("   ")
+		sb.WriteString(border.Render("│"))
+		sb.WriteString(" " + m.styles.TableCell.Width(12).Render("") + " ")
+		sb.WriteString(border.Render("│"))
+		sb.WriteString(" " + m.styles.TotalsLabel.Width(descWidth).Render(label) + " ")
+		sb.WriteString(border.Render("│"))
+		sb.WriteString(" " + m.styles.TableCell.Width(14).Render("") + " ")
+		sb.WriteString(border.Render("│"))
+		sb.WriteString(" " + m.styles.TotalsValue.Width(14).Align(lipgloss.Right).Render(home) + " ")
+		sb.WriteString(border.Render("│"))
+		sb.WriteString(" " + m.styles.TotalsValue.Width(16).Align(lipgloss.Right).Render(local) + " ")
+		sb.WriteString(border.Render("│"))
+		sb.WriteString(" " + m.styles.TableCell.Width(8).Render("") + " ")
+		sb.WriteString(border.Render("│"))
+	}
+	row(label, formatCurrency(totalHome, m.dateRange.Currencies.Home), formatCurrency(totalLocal, m.dateRange.Currencies.Local))
+
+	// Cash expenses valued at the rates their cash was bought at
+	if value, _ := m.cashValue(); value.FXCost() != 0 {
+		sb.WriteString("\n")
+		row(label+" at cash cost", formatCurrency(totalHome-value.FXCost(), m.dateRange.Currencies.Home), "")
+	}
+
+	return sb.String()
+}
+
+// renderCategoryTable renders per-category subtotals for the (filtered) range
+func (m RangeViewModel) renderCategoryTable() string {
+	const catWidth, countWidth, homeWidth, localWidth, shareWidth = 24, 7, 14, 16, 7
+
+	var sb strings.Builder
+	border := m.styles.TableBorder
+
+	line := func(left, mid, right string) string {
+		return border.Render(left + strings.Repeat("─", catWidth+2) + mid + strings.Repeat("─", countWidth+2) + mid +
+			strings.Repeat("─", homeWidth+2) + mid + strings.Repeat("─", localWidth+2) + mid + strings.Repeat("─", shareWidth+2) + right)
+	}
+	row := func(cat, count, home, local, share string, labelStyle, valueStyle lipgloss.Style) string {
+		return border.Render("│") +
+			" " + labelStyle.Width(catWidth).Render(truncateStr(cat, catWidth)) + " " + border.Render("│") +
+			" " + valueStyle.Width(countWidth).Align(lipgloss.Right).Render(count) + " " + border.Render("│") +
+			" " + valueStyle.Width(homeWidth).Align(lipgloss.Right).Render(home) + " " + border.Render("│") +
+			" " + valueStyle.Width(localWidth).Align(lipgloss.Right).Render(local) + " " + border.Render("│") +
+			" " + valueStyle.Width(shareWidth).Align(lipgloss.Right).Render(share) + " " + border.Render("│")
+	}
+
+	query := m.search.GetQuery()
+	totals := m.dateRange.CategoryTotals(query)
+	totalHome := m.dateRange.FilteredTotalHome(query)
+	totalLocal := m.dateRange.FilteredTotalLocal(query)
+	currencies := m.dateRange.Currencies
+
+	sb.WriteString(line("┌", "┬", "┐") + "\n")
+	sb.WriteString(row("Category", "Count", currencies.Home.Code, currencies.Local.Code, "Share", m.styles.TableHeader, m.styles.TableHeader) + "\n")
+	sb.WriteString(line("├", "┼", "┤") + "\n")
+
+	if len(totals) == 0 {
+		sb.WriteString(row("No entries in range", "", "", "", "", m.styles.Subtitle, m.styles.TableCell) + "\n")
+	}
+	for _, total := range totals {
+		share := ""
+		if totalHome != 0 {
+			share = fmt.Sprintf("%.0f%%", 100*total.Home.Float(currencies.Home)/totalHome.Float(currencies.Home))
+		}
+		sb.WriteString(row(total.Category, itoa(total.Count), formatCurrency(total.Home, currencies.Home),
+			formatCurrency(total.Local, currencies.Local), share, m.styles.TableRow, m.styles.TableRow) + "\n")
+	}
+
+	label := "Total"
+	if query != "" {
+		label = "Filtered"
+	}
+	sb.WriteString(line("├", "┼", "┤") + "\n")
+	sb.WriteString(row(label, itoa(len(m.dateRange.AllEntries(query))), formatCurrency(totalHome, currencies.Home),
+		formatCurrency(totalLocal, currencies.Local), "", m.styles.TotalsLabel, m.styles.TotalsValue) + "\n")
+	sb.WriteString(line("└", "┴", "┘"))
+
+	return sb.String()
+}
+
+// renderBudgetTable renders the spending against each budget for every period
+// overlapping the range, in the currency the budget is set in. Periods are whole,
+// and the search filter doesn't apply. Days without spending are left out of
+// daily budgets so long ranges stay readable.
+func (m RangeViewModel) renderBudgetTable() string {
+	const budgetWidth, periodWidth, amountWidth, usedWidth = 14, 18, 14, 5
+
+	var sb strings.Builder
+	border := m.styles.TableBorder
+
+	line := func(left, mid, right string) string {
+		amount := strings.Repeat("─", amountWidth+2)
+		return border.Render(left + strings.Repeat("─", budgetWidth+2) + mid + strings.Repeat("─", periodWidth+2) + mid +
+			amount + mid + amount + mid + amount + mid + strings.Repeat("─", usedWidth+2) + right)
+	}
+	row := func(budget, period, limit, spent, left, used string, labelStyle, valueStyle, overStyle lipgloss.Style) string {
+		return border.Render("│") +
+			" " + labelStyle.Width(budgetWidth).Render(truncateStr(budget, budgetWidth)) + " " + border.Render("│") +
+			" " + labelStyle.Width(periodWidth).Render(period) + " " + border.Render("│") +
+			" " + valueStyle.Width(amountWidth).Align(lipgloss.Right).Render(limit) + " " + border.Render("│") +
+			" " + valueStyle.Width(amountWidth).Align(lipgloss.Right).Render(spent) + " " + border.Render("│") +
+			" " + overStyle.Width(amountWidth).Align(lipgloss.Right).Render(left) + " " + border.Render("│") +
+			" " + overStyle.Width(usedWidth).Align(lipgloss.Right).Render(used) + " " + border.Render("│")
+	}
+
+	sb.WriteString(line("┌", "┬", "┐") + "\n")
+	sb.WriteString(row("Budget", "Period", "Limit", "Spent", "Left", "Used",
+		m.styles.TableHeader, m.styles.TableHeader, m.styles.TableHeader) + "\n")
+	sb.WriteString(line("├", "┼", "┤") + "\n")
+
+	if len(m.budgets) == 0 {
+		sb.WriteString(row("No budgets", "set in config", "", "", "", "", m.styles.Subtitle, m.styles.TableCell, m.styles.TableCell) + "\n")
+	}
+	currencies := m.dateRange.Currencies
+	var previous *ledger.Budget
+	for _, status := range m.budgets {
+		if status.Budget.Period == ledger.BudgetDay && status.SpentHome == 0 && status.SpentLocal == 0 {
+			continue
+		}
+		if previous != nil && status.Budget != *previous {
+			sb.WriteString(line("├", "┼", "┤") + "\n")
+		}
+		previous = &status.Budget
+
+		cur, limit, spent := currencies.Home, status.LimitHome, status.SpentHome
+		if status.Budget.Currency.Code != currencies.Home.Code {
+			cur, limit, spent = currencies.Local, status.LimitLocal, status.SpentLocal
+		}
+		used := ""
+		if limit != 0 {
+			used = fmt.Sprintf("%.0f%%", 100*spent.Float(cur)/limit.Float(cur))
+		}
+		overStyle := m.styles.TableRow
+		if status.Over() {
+			overStyle = m.styles.BudgetOver
+		}
+		sb.WriteString(row(status.Budget.Name(), status.FormatPeriod(), formatCurrency(limit, cur),
+			formatCurrency(spent, cur), formatCurrency(limit-spent, cur), used,
+			m.styles.TableRow, m.styles.TableRow, overStyle) + "\n")
+	}
+	sb.WriteString(line("└", "┴", "┘"))
 
 	return sb.String()
 }
 
 func (m RangeViewModel) renderHelp() string {
+	view, budgets := " categories  ", " budgets  "
+	if m.showCategories {
+		view = " entries  "
+	}
+	if m.showBudgets {
+		budgets = " entries  "
+	}
 	return m.styles.HelpKey.Render("/") + m.styles.HelpDesc.Render(" search  ") +
+		m.styles.HelpKey.Render("c") + m.styles.HelpDesc.Render(view) +
+		m.styles.HelpKey.Render("b") + m.styles.HelpDesc.Render(budgets) +
+		m.styles.HelpKey.Render("r") + m.styles.HelpDesc.Render(" report  ") +
+		m.styles.HelpKey.Render("o") + m.styles.HelpDesc.Render(" owed  ") +
 		m.styles.HelpKey.Render("Enter") + m.styles.HelpDesc.Render(" open day  ") +
 		m.styles.HelpKey.Render("q") + m.styles.HelpDesc.Render(" back")
 }
@@ -485,6 +676,55 @@ func (m *RangeViewModel) SetDateRange(dateRange *ledger.DateRange) {
 	m.updateFilteredEntries()
 }
 
+// SetBudgets sets the budget periods shown in the budget table
+func (m *RangeViewModel) SetBudgets(budgets []ledger.BudgetStatus) {
+	m.budgets = budgets
+}
+
+// SetCashValues sets what the cash expenses cost at the rates their cash was
+// bought at, and the batches of cash bought
+func (m *RangeViewModel) SetCashValues(values map[string]ledger.CashValue, batches []*ledger.CashBatch) {
+	m.cashValues = values
+	m.cashBatches = batches
+}
+
+// cashValue sums the values of the listed cash expenses and the local amount spent on them
+func (m RangeViewModel) cashValue() (ledger.CashValue, ledger.Money) {
+	var total ledger.CashValue
+	var spent ledger.Money
+	for _, entry := range m.dateRange.AllEntries(m.search.GetQuery()) {
+		if value, ok := m.cashValues[entry.ID]; ok {
+			total.Market += value.Market
+			total.Effective += value.Effective
+			spent -= entry.Local
+		}
+	}
+	return total, spent
+}
+
+// renderFXCost compares the listed cash expenses at the rates their cash cost with
+// the market rate, or returns "" if the rates they were paid at are all market rates
+func (m RangeViewModel) renderFXCost() string {
+	total, spent := m.cashValue()
+	if total.FXCost() == 0 {
+		return ""
+	}
+	return m.styles.Subtitle.Render(formatFXCost(total, spent, m.dateRange.Currencies))
+}
+
+// renderCashBatches lists the cash bought in the range with the rate it was
+// bought at, one line per batch, or returns "" if none was bought
+func (m RangeViewModel) renderCashBatches() string {
+	var lines []string
+	for _, batch := range m.cashBatches {
+		if batch.Date.Before(m.dateRange.Start) || batch.Home == 0 {
+			continue
+		}
+		lines = append(lines, m.styles.Subtitle.Render(truncateStr(formatCashBatch(batch, m.dateRange.Currencies), m.width-4)))
+	}
+	return strings.Join(lines, "\n")
+}
+
 // SetSize sets the view dimensions
 func (m *RangeViewModel) SetSize(width, height int) {
 	m.width = width
@@ -503,6 +743,11 @@ func (m RangeViewModel) GetSelectedEntry() *ledger.Entry {
 	return nil
 }
 
+// GetQuery returns the active search query
+func (m RangeViewModel) GetQuery() string {
+	return m.search.GetQuery()
+}
+
 // SetNotification sets a notification
 func (m *RangeViewModel) SetNotification(msg string) {
 	m.notification = msg
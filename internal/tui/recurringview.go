@@ -0,0 +1,455 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"ledger-a/internal/ledger"
+)
+
+// RecurringAction represents an action taken in the recurring rules view
+type RecurringAction int
+
+const (
+	RecurringActionNone RecurringAction = iota
+	RecurringActionBack
+)
+
+// Fields of the rule form, in tab order
+const (
+	recurringFieldDescription = iota
+	recurringFieldCategory
+	recurringFieldAmount
+	recurringFieldCurrency
+	recurringFieldFrequency
+	recurringFieldInterval
+	recurringFieldStart
+	recurringFieldEnd
+	recurringFieldCount
+)
+
+var recurringFieldLabels = [recurringFieldCount]string{
+	"Description", "Category", "Amount", "Currency", "Repeats", "Every", "Start", "End",
+}
+
+// RecurringModel lists the recurring entry rules and lets them be added, edited,
+// paused and deleted. Changes are saved to the data directory right away.
+type RecurringModel struct {
+	styles  *Styles
+	service *ledger.Service
+	width   int
+	height  int
+
+	rules         []ledger.RecurringRule
+	selectedIdx   int
+	pendingDelete bool
+
+	// Rule form
+	editing bool
+	editIdx int // Index of the rule being edited, -1 for a new rule
+	inputs  [recurringFieldCount]textinput.Model
+	focus   int
+
+	notification string
+	notifyError  bool
+}
+
+// NewRecurringModel creates the recurring rules view with the rules saved in the data directory
+func NewRecurringModel(styles *Styles, service *ledger.Service) RecurringModel {
+	m := RecurringModel{
+		styles:  styles,
+		service: service,
+		width:   80,
+		height:  24,
+	}
+
+	placeholders := [recurringFieldCount]string{
+		"Villa rent", "housing #villa", "-5000000", service.GetCurrencies().Local.Code,
+		"daily, weekly or monthly", "1", "MM/DD/YYYY", "MM/DD/YYYY (blank = never)",
+	}
+	for i := range m.inputs {
+		input := textinput.New()
+		input.Prompt = ""
+		input.Placeholder = placeholders[i]
+		input.Width = 30
+		m.inputs[i] = input
+	}
+
+	rules, err := service.GetRecurringRules()
+	if err != nil {
+		m.setNotification(err.Error(), true)
+	}
+	m.rules = rules
+	return m
+}
+
+// Init initializes the recurring rules view
+func (m RecurringModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the recurring rules view
+func (m RecurringModel) Update(msg tea.Msg) (RecurringModel, tea.Cmd, RecurringAction) {
+	if m.editing {
+		return m.updateForm(msg)
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil, RecurringActionNone
+	}
+	m.clearNotification()
+
+	key := keyMsg.String()
+	if m.pendingDelete {
+		m.pendingDelete = false
+		if key == "d" && m.selectedIdx < len(m.rules) {
+			description := m.rules[m.selectedIdx].Description
+			rules := append(append([]ledger.RecurringRule(nil), m.rules[:m.selectedIdx]...), m.rules[m.selectedIdx+1:]...)
+			if m.save(rules) {
+				m.setNotification(fmt.Sprintf("Deleted '%s'", truncateStr(description, 20)), false)
+				if m.selectedIdx >= len(m.rules) {
+					m.selectedIdx = max(0, len(m.rules)-1)
+				}
+			}
+			return m, nil, RecurringActionNone
+		}
+	}
+
+	switch key {
+	case "up", "k":
+		if m.selectedIdx > 0 {
+			m.selectedIdx--
+		}
+	case "down", "j":
+		if m.selectedIdx < len(m.rules)-1 {
+			m.selectedIdx++
+		}
+	case "a":
+		return m.startEdit(-1)
+	case "enter", "e":
+		if len(m.rules) > 0 {
+			return m.startEdit(m.selectedIdx)
+		}
+	case "p", " ":
+		if len(m.rules) > 0 {
+			rules := append([]ledger.RecurringRule(nil), m.rules...)
+			rule := &rules[m.selectedIdx]
+			rule.Paused = !rule.Paused
+			if m.save(rules) {
+				state := "Resumed"
+				if rule.Paused {
+					state = "Paused"
+				}
+				m.setNotification(fmt.Sprintf("%s '%s'", state, truncateStr(rule.Description, 20)), false)
+			}
+		}
+	case "d":
+		if len(m.rules) > 0 {
+			m.pendingDelete = true
+		}
+	case "esc", "q":
+		return m, nil, RecurringActionBack
+	}
+
+	return m, nil, RecurringActionNone
+}
+
+// save writes the rules and keeps them if that worked
+func (m *RecurringModel) save(rules []ledger.RecurringRule) bool {
+	if err := m.service.SaveRecurringRules(rules); err != nil {
+		m.setNotification(err.Error(), true)
+		return false
+	}
+	m.rules = rules
+	return true
+}
+
+// startEdit opens the form on a rule, or on a new rule starting today if idx is -1
+func (m RecurringModel) startEdit(idx int) (RecurringModel, tea.Cmd, RecurringAction) {
+	rule := ledger.NewRecurringRule()
+	rule.Currency = m.service.GetCurrencies().Local.Code
+	rule.Start = ledger.Today().Format(ledger.DateFormat)
+	if idx >= 0 {
+		rule = m.rules[idx]
+	}
+
+	interval := ""
+	if rule.Interval > 1 {
+		interval = strconv.Itoa(rule.Interval)
+	}
+	values := [recurringFieldCount]string{
+		rule.Description, rule.Category, rule.Amount, rule.Currency,
+		string(rule.Frequency), interval, displayDate(rule.Start), displayDate(rule.End),
+	}
+	for i := range m.inputs {
+		m.inputs[i].SetValue(values[i])
+		m.inputs[i].CursorEnd()
+		m.inputs[i].Blur()
+	}
+
+	m.editing = true
+	m.editIdx = idx
+	m.focus = recurringFieldDescription
+	return m, m.inputs[m.focus].Focus(), RecurringActionNone
+}
+
+// displayDate turns a stored YYYY-MM-DD date into MM/DD/YYYY ("" stays "")
+func displayDate(s string) string {
+	date, err := time.Parse(ledger.DateFormat, s)
+	if err != nil {
+		return s
+	}
+	return date.Format("01/02/2006")
+}
+
+func (m RecurringModel) updateForm(msg tea.Msg) (RecurringModel, tea.Cmd, RecurringAction) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			m.editing = false
+			m.clearNotification()
+			return m, nil, RecurringActionNone
+		case "enter":
+			return m.submitForm()
+		case "tab", "down":
+			return m.focusField((m.focus + 1) % recurringFieldCount)
+		case "shift+tab", "up":
+			return m.focusField((m.focus + recurringFieldCount - 1) % recurringFieldCount)
+		}
+	}
+
+	input := &m.inputs[m.focus]
+	oldVal := input.Value()
+	var cmd tea.Cmd
+	*input, cmd = input.Update(msg)
+
+	// Auto-insert slashes as dates are typed
+	if m.focus == recurringFieldStart || m.focus == recurringFieldEnd {
+		if newVal := input.Value(); len(newVal) > len(oldVal) {
+			newVal = autoInsertDateSlashes(newVal)
+			input.SetValue(newVal)
+			input.SetCursor(len(newVal))
+		}
+	}
+	return m, cmd, RecurringActionNone
+}
+
+// focusField moves the cursor to another field of the form
+func (m RecurringModel) focusField(field int) (RecurringModel, tea.Cmd, RecurringAction) {
+	m.inputs[m.focus].Blur()
+	m.focus = field
+	return m, m.inputs[m.focus].Focus(), RecurringActionNone
+}
+
+// submitForm validates the form and saves the rule
+func (m RecurringModel) submitForm() (RecurringModel, tea.Cmd, RecurringAction) {
+	rule, err := m.formRule()
+	if err != nil {
+		m.setNotification(err.Error(), true)
+		return m, nil, RecurringActionNone
+	}
+
+	rules := append([]ledger.RecurringRule(nil), m.rules...)
+	if m.editIdx >= 0 {
+		rules[m.editIdx] = rule
+	} else {
+		rules = append(rules, rule)
+		m.selectedIdx = len(rules) - 1
+	}
+	if !m.save(rules) {
+		return m, nil, RecurringActionNone
+	}
+
+	m.editing = false
+	m.setNotification(fmt.Sprintf("Saved '%s'", truncateStr(rule.Description, 20)), false)
+	return m, nil, RecurringActionNone
+}
+
+// formRule builds the rule from the form, keeping the edited rule's ID, pause
+// state and added occurrences
+func (m RecurringModel) formRule() (ledger.RecurringRule, error) {
+	rule := ledger.NewRecurringRule()
+	if m.editIdx >= 0 {
+		rule = m.rules[m.editIdx]
+	}
+	value := func(field int) string {
+		return strings.TrimSpace(m.inputs[field].Value())
+	}
+
+	currencies := m.service.GetCurrencies()
+	cur, ok := currencies.Currency(value(recurringFieldCurrency))
+	if !ok {
+		return rule, fmt.Errorf("currency must be %s or %s", currencies.Home.Code, currencies.Local.Code)
+	}
+	frequency, err := ledger.ParseRecurringFrequency(value(recurringFieldFrequency))
+	if err != nil {
+		return rule, err
+	}
+	interval := 0
+	if s := value(recurringFieldInterval); s != "" {
+		if interval, err = strconv.Atoi(s); err != nil || interval < 1 {
+			return rule, fmt.Errorf("every must be a whole number (blank = 1)")
+		}
+	}
+	start, err := time.Parse("01/02/2006", value(recurringFieldStart))
+	if err != nil {
+		return rule, fmt.Errorf("invalid start date (use MM/DD/YYYY)")
+	}
+	end := ""
+	if s := value(recurringFieldEnd); s != "" {
+		date, err := time.Parse("01/02/2006", s)
+		if err != nil {
+			return rule, fmt.Errorf("invalid end date (use MM/DD/YYYY)")
+		}
+		end = date.Format(ledger.DateFormat)
+	}
+
+	rule.Description = value(recurringFieldDescription)
+	rule.Category = value(recurringFieldCategory)
+	rule.Amount = strings.ReplaceAll(value(recurringFieldAmount), ",", "")
+	rule.Currency = cur.Code
+	rule.Frequency = frequency
+	rule.Interval = interval
+	rule.Start = start.Format(ledger.DateFormat)
+	rule.End = end
+	return rule, rule.Validate()
+}
+
+// View renders the recurring rules view
+func (m RecurringModel) View() string {
+	notification := m.notification
+	if m.notifyError && notification != "" {
+		notification = "Error: " + notification
+	}
+
+	if m.editing {
+		title := "Edit Recurring Entry"
+		if m.editIdx < 0 {
+			title = "New Recurring Entry"
+		}
+		footer := RenderRibbonFooter("", m.renderFormHelp(), m.styles)
+		return RenderBoxWithTitle(m.renderForm(), title, footer, notification, m.width, m.height)
+	}
+
+	count := fmt.Sprintf("%d rules", len(m.rules))
+	if len(m.rules) == 1 {
+		count = "1 rule"
+	}
+	footer := RenderRibbonFooter(count, m.renderListHelp(), m.styles)
+	return RenderBoxWithTitle(m.renderList(), "Recurring Entries", footer, notification, m.width, m.height)
+}
+
+func (m RecurringModel) renderList() string {
+	if len(m.rules) == 0 {
+		return m.styles.Subtitle.Render("No recurring entries yet - press a to add one")
+	}
+
+	currencies := m.service.GetCurrencies()
+	contentWidth := m.width - 8
+	const (
+		statusWidth   = 7
+		amountWidth   = 16
+		scheduleWidth = 16
+		nextWidth     = 11
+	)
+	descWidth := max(15, contentWidth-statusWidth-amountWidth-scheduleWidth-nextWidth-4)
+
+	header := fmt.Sprintf("%-*s %-*s %*s %-*s %-*s", statusWidth, "", descWidth, "Description",
+		amountWidth, "Amount", scheduleWidth, "Repeats", nextWidth, "Next")
+	lines := []string{m.styles.TableHeader.Render(header)}
+
+	// Scroll so the selected rule stays visible
+	visible := max(1, m.height-10)
+	first := 0
+	if m.selectedIdx >= visible {
+		first = m.selectedIdx - visible + 1
+	}
+	last := min(len(m.rules), first+visible)
+
+	today := ledger.Today()
+	for i := first; i < last; i++ {
+		rule := m.rules[i]
+
+		status := "active"
+		if rule.Paused {
+			status = "paused"
+		}
+		amount := rule.Amount + " " + rule.Currency
+		if cur, ok := currencies.Currency(rule.Currency); ok {
+			if money, err := ledger.ParseMoney(rule.Amount, cur); err == nil {
+				amount = formatCurrency(money, cur)
+			}
+		}
+		next := "ended"
+		if date, ok := rule.NextOn(today); ok {
+			next = date.Format("01/02/2006")
+		}
+
+		line := fmt.Sprintf("%-*s %-*s %*s %-*s %-*s", statusWidth, status,
+			descWidth, truncateStr(rule.Description, descWidth),
+			amountWidth, amount, scheduleWidth, rule.Schedule(), nextWidth, next)
+		switch {
+		case i == m.selectedIdx:
+			line = m.styles.TableRowSelected.Render(line)
+		case rule.Paused:
+			line = m.styles.ValueNeutral.Render(line)
+		default:
+			line = m.styles.TableRow.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m RecurringModel) renderForm() string {
+	var sb strings.Builder
+	for i, input := range m.inputs {
+		label := m.styles.InputLabel
+		if i == m.focus {
+			label = m.styles.InputPrompt
+		}
+		sb.WriteString(label.Width(14).Render(recurringFieldLabels[i]+":") + input.View() + "\n\n")
+	}
+	sb.WriteString(m.styles.Subtitle.Render("Amounts are cash flow: negative for spending"))
+
+	// Pad the lines to one width so the form stays left-aligned when centered
+	return lipgloss.NewStyle().Width(48).Render(sb.String())
+}
+
+func (m RecurringModel) renderListHelp() string {
+	return m.styles.HelpKey.Render("a") + m.styles.HelpDesc.Render(" add  ") +
+		m.styles.HelpKey.Render("Enter") + m.styles.HelpDesc.Render(" edit  ") +
+		m.styles.HelpKey.Render("p") + m.styles.HelpDesc.Render(" pause/resume  ") +
+		m.styles.HelpKey.Render("dd") + m.styles.HelpDesc.Render(" delete  ") +
+		m.styles.HelpKey.Render("Esc") + m.styles.HelpDesc.Render(" back")
+}
+
+func (m RecurringModel) renderFormHelp() string {
+	return m.styles.HelpKey.Render("Tab") + m.styles.HelpDesc.Render(" next field  ") +
+		m.styles.HelpKey.Render("Enter") + m.styles.HelpDesc.Render(" save  ") +
+		m.styles.HelpKey.Render("Esc") + m.styles.HelpDesc.Render(" cancel")
+}
+
+func (m *RecurringModel) setNotification(msg string, isError bool) {
+	m.notification = msg
+	m.notifyError = isError
+}
+
+func (m *RecurringModel) clearNotification() {
+	m.notification = ""
+	m.notifyError = false
+}
+
+// SetSize sets the view dimensions
+func (m *RecurringModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
@@ -0,0 +1,332 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"ledger-a/internal/ledger"
+)
+
+// ReportAction represents an action taken in the report view
+type ReportAction int
+
+const (
+	ReportActionNone ReportAction = iota
+	ReportActionBack
+)
+
+// reportColumn describes a column of a report table
+type reportColumn struct {
+	title string
+	width int
+	right bool // Right-align (amounts)
+}
+
+// ReportModel shows the summary report of a date range
+type ReportModel struct {
+	report *ledger.Report
+	lines  []string // Rendered report, scrolled as a whole
+	offset int
+	styles *Styles
+	width  int
+	height int
+}
+
+// NewReportModel creates a report view for a date range, counting only the
+// entries that match the query and valuing cash expenses with cashValues
+func NewReportModel(styles *Styles, dateRange *ledger.DateRange, query string, cashValues map[string]ledger.CashValue) ReportModel {
+	m := ReportModel{
+		report: ledger.NewReport(dateRange, query, cashValues),
+		styles: styles,
+		width:  80,
+		height: 24,
+	}
+	m.lines = m.renderLines()
+	return m
+}
+
+// Init initializes the report view
+func (m ReportModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the report view
+func (m ReportModel) Update(msg tea.Msg) (ReportModel, tea.Cmd, ReportAction) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			m.scroll(-1)
+		case "down", "j":
+			m.scroll(1)
+		case "pgup", "b":
+			m.scroll(-m.visibleLines())
+		case "pgdown", "f", " ":
+			m.scroll(m.visibleLines())
+		case "home", "g":
+			m.offset = 0
+		case "end", "G":
+			m.scroll(len(m.lines))
+		case "esc", "q":
+			return m, nil, ReportActionBack
+		}
+	}
+	return m, nil, ReportActionNone
+}
+
+// scroll moves the report by n lines, staying within the content
+func (m *ReportModel) scroll(n int) {
+	m.offset = max(0, min(m.offset+n, len(m.lines)-m.visibleLines()))
+}
+
+// visibleLines returns how many report lines fit in the box
+func (m ReportModel) visibleLines() int {
+	return max(1, m.height-5)
+}
+
+// View renders the report view
+func (m ReportModel) View() string {
+	end := min(len(m.lines), m.offset+m.visibleLines())
+	content := strings.Join(m.lines[m.offset:end], "\n")
+
+	position := ""
+	if len(m.lines) > m.visibleLines() {
+		position = fmt.Sprintf("%d-%d of %d", m.offset+1, end, len(m.lines))
+	}
+
+	help := m.styles.HelpKey.Render("↑/↓") + m.styles.HelpDesc.Render(" scroll  ") +
+		m.styles.HelpKey.Render("PgUp/PgDn") + m.styles.HelpDesc.Render(" page  ") +
+		m.styles.HelpKey.Render("q") + m.styles.HelpDesc.Render(" back")
+	footer := RenderRibbonFooter(position, help, m.styles)
+
+	title := "Report: " + m.report.Start.Format("01/02/2006") + " - " + m.report.End.Format("01/02/2006")
+	return RenderBoxWithTitle(content, title, footer, "", m.width, m.height)
+}
+
+// renderLines renders every section of the report
+func (m ReportModel) renderLines() []string {
+	var lines []string
+	section := func(title string, table []string) {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, m.styles.Title.Render(title))
+		lines = append(lines, table...)
+	}
+
+	subtitle := fmt.Sprintf("%d days", m.report.Days())
+	if m.report.Query != "" {
+		subtitle += ", entries matching '" + m.report.Query + "'"
+	}
+	lines = append(lines, m.styles.Subtitle.Render(subtitle))
+
+	section("Summary", m.renderSummary())
+	section("By Month", m.renderPeriods("Month", m.report.Months, func(p ledger.Period) string {
+		return p.Start.Format("January 2006")
+	}))
+	section("By Week", m.renderPeriods("Week", m.report.Weeks, func(p ledger.Period) string {
+		return p.Start.Format("Jan 2") + " - " + p.End.Format("Jan 2")
+	}))
+	section("By Weekday", m.renderWeekdays())
+	section("Largest Expenses", m.renderLargest())
+
+	return lines
+}
+
+// renderSummary renders totals, averages and screen time for the whole range
+func (m ReportModel) renderSummary() []string {
+	r := m.report
+	cols := []reportColumn{
+		{title: "", width: 16},
+		{title: r.Currencies.Home.Code, width: 14, right: true},
+		{title: r.Currencies.Local.Code, width: 18, right: true},
+	}
+
+	amountRow := func(label string, a ledger.Sum) []string {
+		return []string{label, m.formatHome(a.Home), m.formatLocal(a.Local)}
+	}
+	rows := [][]string{
+		amountRow("Spent", r.Totals.Spent),
+		amountRow("Income", r.Totals.Income),
+		amountRow("Net", r.Totals.Net()),
+		amountRow("Daily average", r.DailyAverage()),
+	}
+	if r.Totals.FXCost != 0 {
+		rows = append(rows,
+			[]string{"Spent at cost", m.formatHome(r.Totals.SpentAtCost()), ""},
+			[]string{"FX cost", m.formatHome(r.Totals.FXCost), ""})
+	}
+	rows = append(rows, [][]string{
+		{"Entries", itoa(r.Totals.Entries), ""},
+		{"Screen time avg", formatDuration(r.ScreenTime.Average()), screenTimeNote(r.ScreenTime)},
+	}...)
+	return m.renderTable(cols, rows, nil)
+}
+
+// renderPeriods renders spending per week or month
+func (m ReportModel) renderPeriods(name string, periods []ledger.Period, label func(ledger.Period) string) []string {
+	r := m.report
+	cols := []reportColumn{
+		{title: name, width: 16},
+		{title: "Days", width: 4, right: true},
+		{title: "Spent " + r.Currencies.Home.Code, width: 14, right: true},
+		{title: "Spent " + r.Currencies.Local.Code, width: 18, right: true},
+		{title: "Per day " + r.Currencies.Home.Code, width: 12, right: true},
+		{title: "Per day " + r.Currencies.Local.Code, width: 16, right: true},
+		{title: "Screen", width: 7, right: true},
+	}
+
+	var rows [][]string
+	for _, p := range periods {
+		avg := p.DailyAverage()
+		rows = append(rows, []string{
+			label(p), itoa(p.Days()),
+			m.formatHome(p.Totals.Spent.Home), m.formatLocal(p.Totals.Spent.Local),
+			m.formatHome(avg.Home), m.formatLocal(avg.Local),
+			formatDuration(p.ScreenTime.Average()),
+		})
+	}
+
+	avg := r.DailyAverage()
+	total := []string{
+		"Total", itoa(r.Days()),
+		m.formatHome(r.Totals.Spent.Home), m.formatLocal(r.Totals.Spent.Local),
+		m.formatHome(avg.Home), m.formatLocal(avg.Local),
+		formatDuration(r.ScreenTime.Average()),
+	}
+	return m.renderTable(cols, rows, total)
+}
+
+// renderWeekdays renders spending by day of the week
+func (m ReportModel) renderWeekdays() []string {
+	r := m.report
+	cols := []reportColumn{
+		{title: "Weekday", width: 10},
+		{title: "Entries", width: 7, right: true},
+		{title: "Spent " + r.Currencies.Home.Code, width: 14, right: true},
+		{title: "Spent " + r.Currencies.Local.Code, width: 18, right: true},
+		{title: "Avg " + r.Currencies.Home.Code, width: 12, right: true},
+		{title: "Avg " + r.Currencies.Local.Code, width: 16, right: true},
+	}
+
+	var rows [][]string
+	for _, w := range r.Weekdays {
+		avg := w.Average()
+		rows = append(rows, []string{
+			w.Day.String(), itoa(w.Totals.Entries),
+			m.formatHome(w.Totals.Spent.Home), m.formatLocal(w.Totals.Spent.Local),
+			m.formatHome(avg.Home), m.formatLocal(avg.Local),
+		})
+	}
+	return m.renderTable(cols, rows, nil)
+}
+
+// renderLargest renders the biggest single expenses
+func (m ReportModel) renderLargest() []string {
+	r := m.report
+	cols := []reportColumn{
+		{title: "Date", width: 10},
+		{title: "Description", width: 30},
+		{title: "Category", width: 14},
+		{title: r.Currencies.Home.Code, width: 14, right: true},
+		{title: r.Currencies.Local.Code, width: 18, right: true},
+	}
+
+	var rows [][]string
+	for _, entry := range r.Largest {
+		rows = append(rows, []string{
+			entry.Date.Format("01/02/2006"), entry.Description, entry.CategoryLabel(),
+			m.formatHome(-entry.Home), m.formatLocal(-entry.Local),
+		})
+	}
+	if len(rows) == 0 {
+		rows = append(rows, []string{"", "No expenses in range", "", "", ""})
+	}
+	return m.renderTable(cols, rows, nil)
+}
+
+// renderTable renders a bordered table in the style of the range view, with an
+// optional totals row
+func (m ReportModel) renderTable(cols []reportColumn, rows [][]string, total []string) []string {
+	return renderColumnTable(m.styles, cols, rows, total, -1)
+}
+
+// renderColumnTable renders a bordered table with an optional totals row,
+// highlighting the row at selected (-1 for none)
+func renderColumnTable(styles *Styles, cols []reportColumn, rows [][]string, total []string, selected int) []string {
+	border := styles.TableBorder
+
+	line := func(left, mid, right string) string {
+		parts := make([]string, len(cols))
+		for i, col := range cols {
+			parts[i] = strings.Repeat("─", col.width+2)
+		}
+		return border.Render(left + strings.Join(parts, mid) + right)
+	}
+	row := func(cells []string, style lipgloss.Style) string {
+		var sb strings.Builder
+		sb.WriteString(border.Render("│"))
+		for i, col := range cols {
+			cellStyle := style.Width(col.width)
+			if col.right {
+				cellStyle = cellStyle.Align(lipgloss.Right)
+			}
+			sb.WriteString(" " + cellStyle.Render(truncateStr(cells[i], col.width)) + " " + border.Render("│"))
+		}
+		return sb.String()
+	}
+
+	headers := make([]string, len(cols))
+	for i, col := range cols {
+		headers[i] = col.title
+	}
+
+	lines := []string{line("┌", "┬", "┐"), row(headers, styles.TableHeader), line("├", "┼", "┤")}
+	for i, cells := range rows {
+		style := styles.TableRow
+		if i == selected {
+			style = styles.TableRowSelected
+		}
+		lines = append(lines, row(cells, style))
+	}
+	if total != nil {
+		lines = append(lines, line("├", "┼", "┤"), row(total, styles.TotalsValue))
+	}
+	return append(lines, line("└", "┴", "┘"))
+}
+
+func (m ReportModel) formatHome(amount ledger.Money) string {
+	return formatCurrency(amount, m.report.Currencies.Home)
+}
+
+func (m ReportModel) formatLocal(amount ledger.Money) string {
+	return formatCurrency(amount, m.report.Currencies.Local)
+}
+
+// formatDuration formats a screen time like "3h45m" ("-" if there is none)
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// screenTimeNote describes how much screen time data the average is based on
+func screenTimeNote(s ledger.ScreenTimeStats) string {
+	if s.Days == 0 {
+		return "no data"
+	}
+	return fmt.Sprintf("%d days, max %s", s.Days, formatDuration(s.Max))
+}
+
+// SetSize sets the view dimensions
+func (m *ReportModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.scroll(0)
+}
@@ -77,6 +77,8 @@ type Styles struct {
 	TotalsLabel lipgloss.Style
 	TotalsValue lipgloss.Style
 
+	BudgetOver lipgloss.Style // Spending past a budget's limit
+
 	// Footer ribbon styles
 	RibbonLeft   lipgloss.Style
 	RibbonMiddle lipgloss.Style
@@ -272,6 +274,11 @@ func DefaultStyles() *Styles {
 		Foreground(ColorWhite).
 		Bold(true)
 
+	s.BudgetOver = lipgloss.NewStyle().
+		Foreground(ColorBlack).
+		Background(ColorLightGray).
+		Bold(true)
+
 	// Footer ribbon styles - elegant dark ribbons
 	s.RibbonLeft = lipgloss.NewStyle().
 		Background(ColorDarkerGray).
@@ -66,7 +66,7 @@ func (r *TableRenderer) BuildBorderedBox(title string, contentLines []string, wi
 }
 
 // RenderTotalsRowCompact renders a compact totals row for split view
-func (r *TableRenderer) RenderTotalsRowCompact(day *ledger.Day, searchQuery string, descWidth, idrWidth, cadWidth int) string {
+func (r *TableRenderer) RenderTotalsRowCompact(day *ledger.Day, searchQuery string, descWidth, catWidth, localWidth, homeWidth int) string {
 	border := r.styles.TableBorder
 
 	label := "Total"
@@ -74,29 +74,31 @@ func (r *TableRenderer) RenderTotalsRowCompact(day *ledger.Day, searchQuery stri
 		label = "Filtered"
 	}
 
-	var totalCAD, totalIDR float64
+	var totalHome, totalLocal ledger.Money
 	if searchQuery != "" {
-		totalCAD = day.FilteredTotalCAD(searchQuery)
-		totalIDR = day.FilteredTotalIDR(searchQuery)
+		totalHome = day.FilteredTotalHome(searchQuery)
+		totalLocal = day.FilteredTotalLocal(searchQuery)
 	} else {
-		totalCAD = day.TotalCAD()
-		totalIDR = day.TotalIDR()
+		totalHome = day.TotalHome()
+		totalLocal = day.TotalLocal()
 	}
 
 	var sb strings.Builder
 	sb.WriteString(border.Render("│"))
 	sb.WriteString(" " + r.styles.TotalsLabel.Width(descWidth).Render(label) + " ")
 	sb.WriteString(border.Render("│"))
-	sb.WriteString(" " + r.styles.TotalsValue.Width(idrWidth).Render(formatCurrency(totalIDR, "IDR")) + " ")
+	sb.WriteString(" " + lipgloss.NewStyle().Width(catWidth).Render("") + " ")
 	sb.WriteString(border.Render("│"))
-	sb.WriteString(" " + r.styles.TotalsValue.Width(cadWidth).Render(formatCurrency(totalCAD, "CAD")) + " ")
+	sb.WriteString(" " + r.styles.TotalsValue.Width(localWidth).Render(formatCurrency(totalLocal, day.Currencies.Local)) + " ")
+	sb.WriteString(border.Render("│"))
+	sb.WriteString(" " + r.styles.TotalsValue.Width(homeWidth).Render(formatCurrency(totalHome, day.Currencies.Home)) + " ")
 	sb.WriteString(border.Render("│"))
 
 	return sb.String()
 }
 
 // RenderTotalsRowWithWidth renders a totals row for full-width view
-func (r *TableRenderer) RenderTotalsRowWithWidth(day *ledger.Day, searchQuery string, descWidth, idrWidth, cadWidth int) string {
+func (r *TableRenderer) RenderTotalsRowWithWidth(day *ledger.Day, searchQuery string, descWidth, localWidth, homeWidth int) string {
 	var sb strings.Builder
 	border := r.styles.TableBorder
 
@@ -105,61 +107,70 @@ func (r *TableRenderer) RenderTotalsRowWithWidth(day *ledger.Day, searchQuery st
 		label = "Filtered"
 	}
 
-	var totalCAD, totalIDR float64
+	var totalHome, totalLocal ledger.Money
 	if searchQuery != "" {
-		totalCAD = day.FilteredTotalCAD(searchQuery)
-		totalIDR = day.FilteredTotalIDR(searchQuery)
+		totalHome = day.FilteredTotalHome(searchQuery)
+		totalLocal = day.FilteredTotalLocal(searchQuery)
 	} else {
-		totalCAD = day.TotalCAD()
-		totalIDR = day.TotalIDR()
+		totalHome = day.TotalHome()
+		totalLocal = day.TotalLocal()
 	}
 
 	sb.WriteString(border.Render("│"))
 	sb.WriteString(" " + r.styles.TotalsLabel.Width(descWidth).Render(label) + " ")
 	sb.WriteString(border.Render("│"))
-	sb.WriteString(" " + r.styles.TotalsValue.Width(idrWidth).Render(formatCurrency(totalIDR, "IDR")) + " ")
+	sb.WriteString(" " + r.styles.TotalsValue.Width(localWidth).Render(formatCurrency(totalLocal, day.Currencies.Local)) + " ")
 	sb.WriteString(border.Render("│"))
-	sb.WriteString(" " + r.styles.TotalsValue.Width(cadWidth).Render(formatCurrency(totalCAD, "CAD")) + " ")
+	sb.WriteString(" " + r.styles.TotalsValue.Width(homeWidth).Render(formatCurrency(totalHome, day.Currencies.Home)) + " ")
 	sb.WriteString(border.Render("│"))
 
 	return sb.String()
 }
 
 // RowRenderer is a callback function for rendering individual table rows
-type RowRenderer func(idx int, entry *ledger.Entry, descWidth, idrWidth, cadWidth int) string
+type RowRenderer func(idx int, entry *ledger.Entry, descWidth, catWidth, localWidth, homeWidth int) string
 
 // RenderTableLines renders the table as individual lines for embedding in bordered panel
 func (r *TableRenderer) RenderTableLines(entries []*ledger.Entry, day *ledger.Day, searchQuery string, selectedIdx int, contentWidth, maxRows int, rowRenderer RowRenderer) []string {
-	borderOverhead := 10 // 3 borders + padding spaces
+	borderOverhead := 13 // 5 borders + padding spaces
 
 	// Responsive column widths based on available space
 	// Minimum widths to keep data readable
-	minCAD := 9  // "$X,XXX.XX"
-	minIDR := 10 // "Rp X,XXX,XXX" truncated
-	minDesc := 6 // At least some description visible
+	minHome := 9   // "$X,XXX.XX"
+	minLocal := 10 // "Rp X,XXX,XXX" truncated
+	minCat := 6    // Short category names
+	minDesc := 6   // At least some description visible
 
 	// Ideal widths when space allows
-	idealCAD := 11 // "$XX,XXX.XX"
-	idealIDR := 14 // "Rp XX,XXX,XXX"
+	idealHome := 11  // "$XX,XXX.XX"
+	idealLocal := 14 // "Rp XX,XXX,XXX"
+	idealCat := 14   // "accommodation"
 
 	// Calculate available space for data columns
 	availableForData := contentWidth - borderOverhead
 
 	// Start with ideal widths and scale down if needed
-	cadWidth := idealCAD
-	idrWidth := idealIDR
-	descWidth := availableForData - cadWidth - idrWidth
+	homeWidth := idealHome
+	localWidth := idealLocal
+	catWidth := idealCat
+	descWidth := availableForData - catWidth - homeWidth - localWidth
 
-	// If description is too small, shrink currency columns progressively
+	// If description is too small, shrink the other columns progressively
 	if descWidth < minDesc {
-		// First, reduce IDR to minimum (it's usually the widest)
-		idrWidth = minIDR
-		descWidth = availableForData - cadWidth - idrWidth
+		// First, reduce category to minimum (it's the least important)
+		catWidth = minCat
+		descWidth = availableForData - catWidth - homeWidth - localWidth
+
+		if descWidth < minDesc {
+			// Then reduce local to minimum (it's usually the widest)
+			localWidth = minLocal
+			descWidth = availableForData - catWidth - homeWidth - localWidth
+		}
 
 		if descWidth < minDesc {
-			// Then reduce CAD to minimum
-			cadWidth = minCAD
-			descWidth = availableForData - cadWidth - idrWidth
+			// Then reduce home to minimum
+			homeWidth = minHome
+			descWidth = availableForData - catWidth - homeWidth - localWidth
 		}
 
 		// Final clamp - description gets whatever is left
@@ -172,21 +183,23 @@ func (r *TableRenderer) RenderTableLines(entries []*ledger.Entry, day *ledger.Da
 	border := r.styles.TableBorder
 
 	// Top border
-	topBorder := border.Render("┌" + strings.Repeat("─", descWidth+2) + "┬" + strings.Repeat("─", idrWidth+2) + "┬" + strings.Repeat("─", cadWidth+2) + "┐")
+	topBorder := border.Render("┌" + strings.Repeat("─", descWidth+2) + "┬" + strings.Repeat("─", catWidth+2) + "┬" + strings.Repeat("─", localWidth+2) + "┬" + strings.Repeat("─", homeWidth+2) + "┐")
 	lines = append(lines, topBorder)
 
 	// Header
 	header := border.Render("│") +
 		" " + r.styles.TableHeader.Width(descWidth).Render("Description") + " " +
 		border.Render("│") +
-		" " + r.styles.TableHeader.Width(idrWidth).Render("IDR") + " " +
+		" " + r.styles.TableHeader.Width(catWidth).Render("Category") + " " +
+		border.Render("│") +
+		" " + r.styles.TableHeader.Width(localWidth).Render(day.Currencies.Local.Code) + " " +
 		border.Render("│") +
-		" " + r.styles.TableHeader.Width(cadWidth).Render("CAD") + " " +
+		" " + r.styles.TableHeader.Width(homeWidth).Render(day.Currencies.Home.Code) + " " +
 		border.Render("│")
 	lines = append(lines, header)
 
 	// Header separator
-	headerSep := border.Render("├" + strings.Repeat("─", descWidth+2) + "┼" + strings.Repeat("─", idrWidth+2) + "┼" + strings.Repeat("─", cadWidth+2) + "┤")
+	headerSep := border.Render("├" + strings.Repeat("─", descWidth+2) + "┼" + strings.Repeat("─", catWidth+2) + "┼" + strings.Repeat("─", localWidth+2) + "┼" + strings.Repeat("─", homeWidth+2) + "┤")
 	lines = append(lines, headerSep)
 
 	// Calculate visible rows
@@ -200,9 +213,11 @@ func (r *TableRenderer) RenderTableLines(entries []*ledger.Entry, day *ledger.Da
 		emptyRow := border.Render("│") +
 			" " + r.styles.Subtitle.Width(descWidth).Render(truncateStr("No entries", descWidth)) + " " +
 			border.Render("│") +
-			" " + lipgloss.NewStyle().Width(idrWidth).Render("") + " " +
+			" " + lipgloss.NewStyle().Width(catWidth).Render("") + " " +
+			border.Render("│") +
+			" " + lipgloss.NewStyle().Width(localWidth).Render("") + " " +
 			border.Render("│") +
-			" " + lipgloss.NewStyle().Width(cadWidth).Render("") + " " +
+			" " + lipgloss.NewStyle().Width(homeWidth).Render("") + " " +
 			border.Render("│")
 		lines = append(lines, emptyRow)
 	} else {
@@ -227,21 +242,21 @@ func (r *TableRenderer) RenderTableLines(entries []*ledger.Entry, day *ledger.Da
 
 		for i := startIdx; i < endIdx; i++ {
 			entry := entries[i]
-			row := rowRenderer(i, entry, descWidth, idrWidth, cadWidth)
+			row := rowRenderer(i, entry, descWidth, catWidth, localWidth, homeWidth)
 			lines = append(lines, row)
 		}
 	}
 
 	// Separator before totals
-	totalsSep := border.Render("├" + strings.Repeat("─", descWidth+2) + "┼" + strings.Repeat("─", idrWidth+2) + "┼" + strings.Repeat("─", cadWidth+2) + "┤")
+	totalsSep := border.Render("├" + strings.Repeat("─", descWidth+2) + "┼" + strings.Repeat("─", catWidth+2) + "┼" + strings.Repeat("─", localWidth+2) + "┼" + strings.Repeat("─", homeWidth+2) + "┤")
 	lines = append(lines, totalsSep)
 
 	// Totals row
-	totalsRow := r.RenderTotalsRowCompact(day, searchQuery, descWidth, idrWidth, cadWidth)
+	totalsRow := r.RenderTotalsRowCompact(day, searchQuery, descWidth, catWidth, localWidth, homeWidth)
 	lines = append(lines, totalsRow)
 
 	// Bottom border
-	bottomBorder := border.Render("└" + strings.Repeat("─", descWidth+2) + "┴" + strings.Repeat("─", idrWidth+2) + "┴" + strings.Repeat("─", cadWidth+2) + "┘")
+	bottomBorder := border.Render("└" + strings.Repeat("─", descWidth+2) + "┴" + strings.Repeat("─", catWidth+2) + "┴" + strings.Repeat("─", localWidth+2) + "┴" + strings.Repeat("─", homeWidth+2) + "┘")
 	lines = append(lines, bottomBorder)
 
 	return lines
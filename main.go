@@ -1,16 +1,34 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"ledger-a/internal/cli"
+	"ledger-a/internal/config"
 	"ledger-a/internal/tui"
 )
 
 func main() {
-	app := tui.NewApp()
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	// A subcommand runs without the UI (e.g., "ledger-a add today lunch 45000")
+	if flag.NArg() > 0 {
+		os.Exit(cli.Run(cfg, flag.Args(), os.Stdout, os.Stderr))
+	}
+
+	app, err := tui.NewApp(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
 	p := tea.NewProgram(app, tea.WithAltScreen())
 
@@ -19,3 +37,49 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// loadConfig reads the config file and applies command-line flags on top of it
+func loadConfig() (*config.Config, error) {
+	defaultPath, err := config.DefaultPath()
+	if err != nil {
+		defaultPath = ""
+	}
+
+	configPath := flag.String("config", defaultPath, "path to the config file")
+	dataDir := flag.String("data-dir", "", "ledger data directory")
+	cacheDir := flag.String("cache-dir", "", "exchange rate cache directory (default: data directory)")
+	offline := flag.Bool("offline", false, "never contact the exchange rate API")
+	rate := flag.Float64("rate", 0, "fixed home to local exchange rate (e.g., 11800)")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), cli.Usage+"\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cfg := &config.Config{}
+	if *configPath != "" {
+		cfg, err = config.Load(*configPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Flags override the config file only when given
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "data-dir":
+			cfg.DataDir = *dataDir
+		case "cache-dir":
+			cfg.CacheDir = *cacheDir
+		case "offline":
+			cfg.Offline = *offline
+		case "rate":
+			cfg.Rate = *rate
+		}
+	})
+
+	if err := cfg.Resolve(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}